@@ -29,6 +29,7 @@ import (
 	"github.com/wavetermdev/waveterm/pkg/service/termdashservice"
 	"github.com/wavetermdev/waveterm/pkg/telemetry"
 	"github.com/wavetermdev/waveterm/pkg/telemetry/telemetrydata"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
 	"github.com/wavetermdev/waveterm/pkg/util/envutil"
 	"github.com/wavetermdev/waveterm/pkg/util/shellutil"
 	"github.com/wavetermdev/waveterm/pkg/util/sigutil"
@@ -85,6 +86,7 @@ func doShutdown(reason string) {
 		shutdownActivityUpdate()
 		sendTelemetryWrapper()
 		// TODO deal with flush in progress
+		termdash.FlushAllAndStop(ctx)
 		clearTempFiles()
 		filestore.WFS.FlushCache(ctx)
 		watcher := wconfig.GetWatcher()
@@ -574,6 +576,7 @@ func main() {
 	blockcontroller.InitBlockController()
 	wcore.InitTabIndicatorStore()
 	termdashservice.StartSummaryLoop()
+	termdashservice.StartRetentionLoop()
 	go func() {
 		defer func() {
 			panichandler.PanicHandler("GetSystemSummary", recover())
@@ -5,6 +5,7 @@ package blockcontroller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
@@ -18,12 +19,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/wavetermdev/waveterm/pkg/blocklogger"
-	"github.com/wavetermdev/waveterm/pkg/termdash"
 	"github.com/wavetermdev/waveterm/pkg/filestore"
 	"github.com/wavetermdev/waveterm/pkg/panichandler"
 	"github.com/wavetermdev/waveterm/pkg/remote"
 	"github.com/wavetermdev/waveterm/pkg/remote/conncontroller"
+	"github.com/wavetermdev/waveterm/pkg/service/termdashservice"
 	"github.com/wavetermdev/waveterm/pkg/shellexec"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
 	"github.com/wavetermdev/waveterm/pkg/util/envutil"
 	"github.com/wavetermdev/waveterm/pkg/util/fileutil"
 	"github.com/wavetermdev/waveterm/pkg/util/shellutil"
@@ -50,6 +52,10 @@ const (
 	LocalConnVariant_GitBash = "gitbash"
 )
 
+// maxOutputTailBytes bounds how much recent output is kept around to scan
+// for a Claude Code usage/cost summary banner when the process exits.
+const maxOutputTailBytes = 8192
+
 type ShellController struct {
 	Lock *sync.Mutex
 
@@ -69,8 +75,15 @@ type ShellController struct {
 	ShellInputCh chan *BlockInputUnion
 
 	// termdash: Claude status tracking and transcript recording
-	StatusDetector      *termdash.StatusDetector
-	TranscriptRecorder  *termdash.TranscriptRecorder
+	StatusDetector     *termdash.StatusDetector
+	TranscriptRecorder *termdash.TranscriptRecorder
+	CwdTracker         *termdash.CwdTracker
+	resumeFromDetected bool
+	checkedInteractive bool
+	outputTail         string
+	claudeMode         string
+	claudeModel        string
+	shellType          string
 }
 
 // Constructor that returns the Controller interface
@@ -534,16 +547,42 @@ func (bc *ShellController) manageRunningShellProcess(shellProc *shellexec.ShellP
 
 	// Initialize status detector and transcript recorder for Claude blocks
 	isClaudeBlock := blockMeta.GetString(waveobj.MetaKey_TermDashType, "") == "claude"
+	// One-shot `claude -p` invocations never present a prompt, so idle
+	// timers and needs-input detection don't apply; only skip the status
+	// detector, not transcript recording or cwd tracking.
+	isInteractive := blockMeta.GetBool(waveobj.MetaKey_TermDashInteractive, true)
 	if isClaudeBlock {
-		bc.StatusDetector = termdash.NewStatusDetector(func(oldStatus, newStatus string) {
-			bc.handleClaudeStatusChange(oldStatus, newStatus)
-		})
+		settings := wconfig.GetWatcher().GetFullConfig().Settings
+		if isInteractive {
+			var detectorOpts []termdash.StatusDetectorOption
+			inferCtx, inferCancel := context.WithTimeout(context.Background(), 2*time.Second)
+			initialStatus, err := (&termdashservice.TermDashService{}).InferStatusFromTranscript(inferCtx, bc.BlockId)
+			inferCancel()
+			if err == nil {
+				detectorOpts = append(detectorOpts, termdash.WithInitialStatus(initialStatus))
+			}
+			if settings.TermDashClassifyThrottleMs != nil {
+				detectorOpts = append(detectorOpts, termdash.WithClassifyThrottle(time.Duration(*settings.TermDashClassifyThrottleMs)*time.Millisecond))
+			}
+			bc.StatusDetector = termdashservice.AttachDetector(bc.BlockId, func(oldStatus, newStatus string) {
+				bc.handleClaudeStatusChange(oldStatus, newStatus)
+			}, detectorOpts...)
+		}
+		var recorderOpts []termdash.TranscriptRecorderOption
+		if settings.TermDashTranscriptFormat == termdash.TranscriptFormatBinary {
+			recorderOpts = append(recorderOpts, termdash.WithBinaryFormat())
+		}
 		bc.TranscriptRecorder = termdash.NewTranscriptRecorder(func(data []byte) {
 			err := HandleAppendBlockFile(bc.BlockId, "termdash:transcript", data)
 			if err != nil {
 				log.Printf("[termdash] error appending transcript: %v\n", err)
 			}
+		}, recorderOpts...)
+		startCwd := blockMeta.GetString(waveobj.MetaKey_CmdCwd, "")
+		bc.CwdTracker = termdash.NewCwdTracker(startCwd, wavebase.GetHomeDir(), func(_, newCwd string) {
+			go bc.recordCwd(newCwd)
 		})
+		go termdashservice.FireSessionCreated(bc.BlockId)
 	}
 
 	go func() {
@@ -557,10 +596,16 @@ func (bc *ShellController) manageRunningShellProcess(shellProc *shellexec.ShellP
 			if bc.StatusDetector != nil {
 				bc.StatusDetector.SetExited()
 				bc.StatusDetector.Stop()
+				termdashservice.DetachDetector(bc.BlockId)
 			}
 			if bc.TranscriptRecorder != nil {
 				bc.TranscriptRecorder.Stop()
 			}
+			if isClaudeBlock {
+				if usage, ok := termdash.ParseUsageSummary(bc.outputTail); ok {
+					go bc.recordSessionUsage(usage)
+				}
+			}
 			shellProc.Close()
 			bc.WithLock(func() {
 				// so no other events are sent
@@ -585,6 +630,20 @@ func (bc *ShellController) manageRunningShellProcess(shellProc *shellexec.ShellP
 				if err != nil {
 					log.Printf("error appending to blockfile: %v\n", err)
 				}
+				// If the command line didn't reveal a one-shot `claude -p`
+				// invocation (e.g. wrapped in a shell alias), fall back to
+				// checking the first chunk of real output: an interactive
+				// session's terminal UI styles it with ANSI codes, -p
+				// output doesn't.
+				if isClaudeBlock && !bc.checkedInteractive {
+					bc.checkedInteractive = true
+					if bc.StatusDetector != nil && termdash.DetectNonInteractiveFromOutput(string(buf[:nr])) {
+						bc.StatusDetector.Stop()
+						bc.StatusDetector = nil
+						termdashservice.DetachDetector(bc.BlockId)
+						go bc.recordInteractive(false)
+					}
+				}
 				// Feed output to status detector and transcript for Claude blocks
 				if bc.StatusDetector != nil {
 					bc.StatusDetector.ProcessOutput(buf[:nr])
@@ -592,6 +651,33 @@ func (bc *ShellController) manageRunningShellProcess(shellProc *shellexec.ShellP
 				if bc.TranscriptRecorder != nil {
 					bc.TranscriptRecorder.RecordOutput(buf[:nr])
 				}
+				if isClaudeBlock && !bc.resumeFromDetected {
+					if resumedFrom, ok := termdash.DetectResumeSessionID(string(buf[:nr])); ok {
+						bc.resumeFromDetected = true
+						go bc.recordResumedFrom(resumedFrom)
+					}
+				}
+				if isClaudeBlock {
+					bc.outputTail += string(buf[:nr])
+					if len(bc.outputTail) > maxOutputTailBytes {
+						bc.outputTail = bc.outputTail[len(bc.outputTail)-maxOutputTailBytes:]
+					}
+					if mode, found := termdash.DetectClaudeMode(string(buf[:nr])); found && mode != bc.claudeMode {
+						bc.claudeMode = mode
+						go bc.recordClaudeMode(mode)
+					}
+					if model, found := termdash.DetectClaudeModel(string(buf[:nr])); found && model != bc.claudeModel {
+						bc.claudeModel = model
+						go bc.recordClaudeModel(model)
+					}
+					if shell, found := termdash.DetectShell(string(buf[:nr])); found && shell != bc.shellType {
+						bc.shellType = shell
+						if bc.StatusDetector != nil {
+							bc.StatusDetector.SetShellType(shell)
+						}
+						go bc.recordShellType(shell)
+					}
+				}
 			}
 			if err == io.EOF {
 				break
@@ -615,6 +701,12 @@ func (bc *ShellController) manageRunningShellProcess(shellProc *shellexec.ShellP
 				if bc.TranscriptRecorder != nil {
 					bc.TranscriptRecorder.RecordInput(ic.InputData)
 				}
+				if bc.CwdTracker != nil {
+					bc.CwdTracker.ProcessInput(ic.InputData)
+				}
+				if bc.StatusDetector != nil {
+					bc.StatusDetector.ProcessInput(ic.InputData)
+				}
 			}
 			if ic.TermSize != nil {
 				updateTermSize(shellProc, bc.BlockId, *ic.TermSize)
@@ -773,12 +865,14 @@ func createCmdStrAndOpts(blockId string, blockMeta waveobj.MetaMapType, connName
 	// TermDash: Claude Code session management
 	tdType := blockMeta.GetString(waveobj.MetaKey_TermDashType, "")
 	if tdType == "claude" {
+		interactive := termdash.IsInteractiveCommand(cmdStr)
 		cmdStr = buildClaudeCommand(blockId, blockMeta, cmdStr)
 		// Set initial status to active
 		initCtx, initCancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer initCancel()
 		statusMeta := waveobj.MetaMapType{
-			waveobj.MetaKey_TermDashStatus: termdash.StatusActive,
+			waveobj.MetaKey_TermDashStatus:      termdash.StatusActive,
+			waveobj.MetaKey_TermDashInteractive: interactive,
 		}
 		wstore.UpdateObjectMeta(initCtx, waveobj.MakeORef(waveobj.OType_Block, blockId), statusMeta, false)
 	}
@@ -881,21 +975,146 @@ func buildLearningsContext(ctx context.Context) string {
 	return sb.String()
 }
 
-// handleClaudeStatusChange is called by the status detector when Claude's status changes.
-// It updates block metadata and publishes tab indicator events.
-func (bc *ShellController) handleClaudeStatusChange(oldStatus, newStatus string) {
-	log.Printf("[termdash] Claude status change: %s -> %s (block=%s)\n", oldStatus, newStatus, bc.BlockId)
+// recordResumedFrom saves the original session ID a resumed Claude session
+// was detected to continue from, linking the two blocks for the UI and for
+// transcript/learnings merging.
+func (bc *ShellController) recordResumedFrom(resumedFrom string) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFn()
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashResumedFrom: resumedFrom,
+	}
+	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, bc.BlockId), metaUpdate, false)
+	if err != nil {
+		log.Printf("[termdash] error recording resumedfrom for block %s: %v\n", bc.BlockId, err)
+	}
+}
+
+// recordSessionUsage saves the parsed token/cost usage from a Claude
+// session's exit banner to block metadata, giving real cost data instead
+// of an estimate.
+func (bc *ShellController) recordSessionUsage(usage *termdash.Usage) {
+	raw, err := json.Marshal(usage)
+	if err != nil {
+		log.Printf("[termdash] error marshaling usage for block %s: %v\n", bc.BlockId, err)
+		return
+	}
+	ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFn()
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashUsage: string(raw),
+	}
+	err = wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, bc.BlockId), metaUpdate, false)
+	if err != nil {
+		log.Printf("[termdash] error recording usage for block %s: %v\n", bc.BlockId, err)
+	}
+}
+
+// recordStatusHistory appends a status-history entry so
+// TermDashService.GetStatusTimeline can reconstruct the session's full
+// active/idle/waiting timeline.
+func (bc *ShellController) recordStatusHistory(newStatus string) {
+	entry := termdash.StatusHistoryEntry{
+		Timestamp: time.Now().UnixMilli(),
+		Status:    newStatus,
+	}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[termdash] error marshaling status history entry for block %s: %v\n", bc.BlockId, err)
+		return
+	}
+	raw = append(raw, '\n')
+	err = HandleAppendBlockFile(bc.BlockId, termdashservice.StatusHistoryFile, raw)
+	if err != nil {
+		log.Printf("[termdash] error appending status history: %v\n", err)
+	}
+}
+
+// recordClaudeMode is called when a fresh mode footer reports a change in
+// Claude's plan/accept-edits mode. It updates block metadata so the mode is
+// surfaced in listings.
+func (bc *ShellController) recordClaudeMode(newMode string) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFn()
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashClaudeMode: newMode,
+	}
+	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, bc.BlockId), metaUpdate, false)
+	if err != nil {
+		log.Printf("[termdash] error updating block claude mode meta: %v\n", err)
+	}
+}
+
+// recordClaudeModel is called when output reveals a change in the active
+// Claude model (e.g. a mid-session model switch). It updates block metadata
+// so the model is surfaced in listings and usable for cost estimation.
+func (bc *ShellController) recordClaudeModel(newModel string) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFn()
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashClaudeModel: newModel,
+	}
+	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, bc.BlockId), metaUpdate, false)
+	if err != nil {
+		log.Printf("[termdash] error updating block claude model meta: %v\n", err)
+	}
+}
+
+// recordShellType is called when output reveals the session's shell (bash,
+// zsh, or fish). It updates block metadata so prompt-pattern selection
+// (StatusDetector.SetShellType) survives a resumed/reattached session, and
+// so the shell is surfaced in listings.
+func (bc *ShellController) recordShellType(shell string) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFn()
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashShell: shell,
+	}
+	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, bc.BlockId), metaUpdate, false)
+	if err != nil {
+		log.Printf("[termdash] error updating block shell meta: %v\n", err)
+	}
+}
+
+// recordInteractive persists a session's interactivity classification once
+// it's determined from its early output rather than its command line.
+func (bc *ShellController) recordInteractive(interactive bool) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFn()
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashInteractive: interactive,
+	}
+	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, bc.BlockId), metaUpdate, false)
+	if err != nil {
+		log.Printf("[termdash] error updating block interactive meta: %v\n", err)
+	}
+}
 
-	// Update block metadata with new status
+// recordCwd persists the CwdTracker's latest detected working directory to
+// block meta, so file-path anchoring elsewhere can read it without
+// replaying the transcript.
+func (bc *ShellController) recordCwd(newCwd string) {
 	ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancelFn()
 	metaUpdate := waveobj.MetaMapType{
-		waveobj.MetaKey_TermDashStatus: newStatus,
+		waveobj.MetaKey_TermDashCwd: newCwd,
 	}
 	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, bc.BlockId), metaUpdate, false)
 	if err != nil {
-		log.Printf("[termdash] error updating block status meta: %v\n", err)
+		log.Printf("[termdash] error updating block cwd meta: %v\n", err)
 	}
+}
+
+// handleClaudeStatusChange is called by the status detector when Claude's status changes.
+// It runs the block-specific side effects of a status
+// change (tab indicators, status history). AttachDetector has already
+// persisted newStatus to MetaKey_TermDashStatus by the time this runs.
+func (bc *ShellController) handleClaudeStatusChange(oldStatus, newStatus string) {
+	log.Printf("[termdash] Claude status change: %s -> %s (block=%s)\n", oldStatus, newStatus, bc.BlockId)
+
+	// Append to the status history so GetStatusTimeline can reconstruct when
+	// the session was active/idle/waiting.
+	bc.recordStatusHistory(newStatus)
 
 	// Publish tab indicator based on status
 	var indicator *wshrpc.TabIndicator
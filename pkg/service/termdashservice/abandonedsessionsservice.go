@@ -0,0 +1,92 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/wavebase"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// abandonedCandidate is the subset of an active block's state
+// selectAbandonedSessions needs to decide whether it's been abandoned.
+type abandonedCandidate struct {
+	blockId        string
+	pinned         bool
+	lastActivityMs int64
+}
+
+// selectAbandonedSessions returns the candidates that are unpinned and whose
+// lastActivityMs is older than threshold as of now. Split out from
+// ListAbandonedSessions so the abandonment rule is testable without a
+// DB/filestore-backed set of blocks.
+func selectAbandonedSessions(candidates []abandonedCandidate, threshold time.Duration, now time.Time) []abandonedCandidate {
+	cutoff := now.Add(-threshold).UnixMilli()
+	var abandoned []abandonedCandidate
+	for _, c := range candidates {
+		if c.pinned {
+			continue
+		}
+		if c.lastActivityMs >= cutoff {
+			continue
+		}
+		abandoned = append(abandoned, c)
+	}
+	return abandoned
+}
+
+// ListAbandonedSessions returns active (non-archived), unpinned Claude
+// sessions whose terminal output hasn't been touched in at least threshold,
+// so the UI can suggest archiving them ("archive these 12 stale sessions").
+// Last activity is determined from the terminal output blockfile's
+// (wavebase.BlockFile_Term) modification time, the same proxy
+// collectSessionSummariesSince uses, since an active session carries no
+// last-activity timestamp of its own.
+func (s *TermDashService) ListAbandonedSessions(ctx context.Context, threshold time.Duration) ([]ArchivedSession, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	blocksById := map[string]*waveobj.Block{}
+	var candidates []abandonedCandidate
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		if block.Meta.GetBool(waveobj.MetaKey_TermDashArchived, false) {
+			continue
+		}
+		wfile, err := filestore.WFS.Stat(ctx, block.OID, wavebase.BlockFile_Term)
+		if err != nil {
+			continue // no terminal output yet; nothing to judge staleness against
+		}
+		blocksById[block.OID] = block
+		candidates = append(candidates, abandonedCandidate{
+			blockId:        block.OID,
+			pinned:         block.Meta.GetBool(waveobj.MetaKey_TermDashPinned, false),
+			lastActivityMs: wfile.ModTs,
+		})
+	}
+
+	var abandoned []ArchivedSession
+	for _, c := range selectAbandonedSessions(candidates, threshold, time.Now()) {
+		block := blocksById[c.blockId]
+		abandoned = append(abandoned, ArchivedSession{
+			BlockId:     block.OID,
+			SessionId:   block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, ""),
+			Summary:     block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""),
+			Status:      block.Meta.GetString(waveobj.MetaKey_TermDashStatus, ""),
+			ClaudeMode:  block.Meta.GetString(waveobj.MetaKey_TermDashClaudeMode, ""),
+			ClaudeModel: block.Meta.GetString(waveobj.MetaKey_TermDashClaudeModel, ""),
+			ArchivedAt:  c.lastActivityMs,
+		})
+	}
+	return abandoned, nil
+}
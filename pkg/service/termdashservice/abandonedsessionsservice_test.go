@@ -0,0 +1,37 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelectAbandonedSessionsReturnsStaleUnpinnedOnly(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	threshold := 7 * 24 * time.Hour
+
+	candidates := []abandonedCandidate{
+		{blockId: "fresh", lastActivityMs: now.Add(-1 * time.Hour).UnixMilli()},
+		{blockId: "stale", lastActivityMs: now.Add(-10 * 24 * time.Hour).UnixMilli()},
+		{blockId: "stale-pinned", pinned: true, lastActivityMs: now.Add(-30 * 24 * time.Hour).UnixMilli()},
+		{blockId: "borderline-fresh", lastActivityMs: now.Add(-threshold + time.Minute).UnixMilli()},
+	}
+
+	abandoned := selectAbandonedSessions(candidates, threshold, now)
+	if len(abandoned) != 1 || abandoned[0].blockId != "stale" {
+		t.Errorf("selectAbandonedSessions() = %v, want just the stale, unpinned session", abandoned)
+	}
+}
+
+func TestSelectAbandonedSessionsWithinThresholdReturnsNil(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	candidates := []abandonedCandidate{
+		{blockId: "a", lastActivityMs: now.Add(-time.Hour).UnixMilli()},
+		{blockId: "b", lastActivityMs: now.Add(-2 * time.Hour).UnixMilli()},
+	}
+	if got := selectAbandonedSessions(candidates, 24*time.Hour, now); got != nil {
+		t.Errorf("selectAbandonedSessions() = %v, want nil when nothing is stale", got)
+	}
+}
@@ -5,6 +5,7 @@ package termdashservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,11 +17,13 @@ import (
 type TermDashService struct{}
 
 type ArchivedSession struct {
-	BlockId    string `json:"blockid"`
-	SessionId  string `json:"sessionid"`
-	Summary    string `json:"summary"`
-	Status     string `json:"status"`
-	ArchivedAt int64  `json:"archivedat"`
+	BlockId     string `json:"blockid"`
+	SessionId   string `json:"sessionid"`
+	Summary     string `json:"summary"`
+	Status      string `json:"status"`
+	ClaudeMode  string `json:"claudemode"`
+	ClaudeModel string `json:"claudemodel"`
+	ArchivedAt  int64  `json:"archivedat"`
 }
 
 // ArchiveBlock marks a Claude block as archived with a timestamp.
@@ -29,7 +32,11 @@ func (s *TermDashService) ArchiveBlock(ctx context.Context, blockId string) erro
 		waveobj.MetaKey_TermDashArchived:   true,
 		waveobj.MetaKey_TermDashArchivedAt: time.Now().UnixMilli(),
 	}
-	return wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, blockId), metaUpdate, false)
+	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, blockId), metaUpdate, false)
+	if errors.Is(err, wstore.ErrNotFound) {
+		return fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return err
 }
 
 // UnarchiveBlock removes the archived flag from a block.
@@ -38,7 +45,11 @@ func (s *TermDashService) UnarchiveBlock(ctx context.Context, blockId string) er
 		waveobj.MetaKey_TermDashArchived:   nil,
 		waveobj.MetaKey_TermDashArchivedAt: nil,
 	}
-	return wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, blockId), metaUpdate, false)
+	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, blockId), metaUpdate, false)
+	if errors.Is(err, wstore.ErrNotFound) {
+		return fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return err
 }
 
 // ListArchivedSessions returns all archived Claude sessions.
@@ -57,11 +68,13 @@ func (s *TermDashService) ListArchivedSessions(ctx context.Context) ([]ArchivedS
 			continue
 		}
 		archived = append(archived, ArchivedSession{
-			BlockId:    block.OID,
-			SessionId:  block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, ""),
-			Summary:    block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""),
-			Status:     block.Meta.GetString(waveobj.MetaKey_TermDashStatus, ""),
-			ArchivedAt: int64(block.Meta.GetFloat(waveobj.MetaKey_TermDashArchivedAt, 0)),
+			BlockId:     block.OID,
+			SessionId:   block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, ""),
+			Summary:     block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""),
+			Status:      block.Meta.GetString(waveobj.MetaKey_TermDashStatus, ""),
+			ClaudeMode:  block.Meta.GetString(waveobj.MetaKey_TermDashClaudeMode, ""),
+			ClaudeModel: block.Meta.GetString(waveobj.MetaKey_TermDashClaudeModel, ""),
+			ArchivedAt:  int64(block.Meta.GetFloat(waveobj.MetaKey_TermDashArchivedAt, 0)),
 		})
 	}
 	return archived, nil
@@ -83,10 +96,12 @@ func (s *TermDashService) ListActiveSessions(ctx context.Context) ([]ArchivedSes
 			continue
 		}
 		active = append(active, ArchivedSession{
-			BlockId:   block.OID,
-			SessionId: block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, ""),
-			Summary:   block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""),
-			Status:    block.Meta.GetString(waveobj.MetaKey_TermDashStatus, ""),
+			BlockId:     block.OID,
+			SessionId:   block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, ""),
+			Summary:     block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""),
+			Status:      block.Meta.GetString(waveobj.MetaKey_TermDashStatus, ""),
+			ClaudeMode:  block.Meta.GetString(waveobj.MetaKey_TermDashClaudeMode, ""),
+			ClaudeModel: block.Meta.GetString(waveobj.MetaKey_TermDashClaudeModel, ""),
 		})
 	}
 	return active, nil
@@ -108,11 +123,13 @@ func (s *TermDashService) SearchSessions(ctx context.Context, query string) ([]A
 		summary := block.Meta.GetString(waveobj.MetaKey_TermDashSummary, "")
 		if summary != "" && strings.Contains(strings.ToLower(summary), query) {
 			results = append(results, ArchivedSession{
-				BlockId:    block.OID,
-				SessionId:  block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, ""),
-				Summary:    summary,
-				Status:     block.Meta.GetString(waveobj.MetaKey_TermDashStatus, ""),
-				ArchivedAt: int64(block.Meta.GetFloat(waveobj.MetaKey_TermDashArchivedAt, 0)),
+				BlockId:     block.OID,
+				SessionId:   block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, ""),
+				Summary:     summary,
+				Status:      block.Meta.GetString(waveobj.MetaKey_TermDashStatus, ""),
+				ClaudeMode:  block.Meta.GetString(waveobj.MetaKey_TermDashClaudeMode, ""),
+				ClaudeModel: block.Meta.GetString(waveobj.MetaKey_TermDashClaudeModel, ""),
+				ArchivedAt:  int64(block.Meta.GetFloat(waveobj.MetaKey_TermDashArchivedAt, 0)),
 			})
 		}
 	}
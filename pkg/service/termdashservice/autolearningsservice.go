@@ -0,0 +1,132 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/panichandler"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// SetAutoLearnings flags or unflags a block for continuous learnings
+// re-extraction: once flagged, pollAutoLearnings periodically re-runs
+// ExtractLearnings for it as its transcript grows, instead of leaving
+// extraction to happen only once on exit.
+func (s *TermDashService) SetAutoLearnings(ctx context.Context, blockId string, enabled bool) error {
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashAutoLearnings: enabled,
+	}
+	if !enabled {
+		metaUpdate[waveobj.MetaKey_TermDashAutoLearnings] = nil
+	}
+	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, blockId), metaUpdate, false)
+	if errors.Is(err, wstore.ErrNotFound) {
+		return fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return err
+}
+
+// GetAutoLearnings reports whether blockId is flagged for continuous
+// learnings re-extraction.
+func (s *TermDashService) GetAutoLearnings(ctx context.Context, blockId string) (bool, error) {
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return block.Meta.GetBool(waveobj.MetaKey_TermDashAutoLearnings, false), nil
+}
+
+// DefaultAutoLearningsCooldown is the minimum time between automatic
+// re-extraction attempts for a single flagged block, enforced by
+// pollAutoLearnings.
+const DefaultAutoLearningsCooldown = 2 * time.Minute
+
+// AutoLearningsCooldown controls the cooldown enforced by pollAutoLearnings.
+// Defaults to DefaultAutoLearningsCooldown.
+var AutoLearningsCooldown = DefaultAutoLearningsCooldown
+
+var (
+	autoLearningsMu      sync.Mutex
+	lastAutoLearningsAt  = map[string]time.Time{}
+	lastAutoLearningsLen = map[string]int{}
+)
+
+// autoLearningsDueLocked reports whether blockId, whose transcript is
+// currently transcriptLen bytes, is due for another automatic re-extraction
+// as of now: the transcript must have grown since the last extraction (or
+// never been extracted from), and AutoLearningsCooldown must have elapsed
+// since the last attempt. Records now and transcriptLen as the new attempt
+// state when it returns true, mirroring allowSummaryGenerationLocked in
+// summaryservice.go.
+func autoLearningsDueLocked(blockId string, transcriptLen int, now time.Time) bool {
+	autoLearningsMu.Lock()
+	defer autoLearningsMu.Unlock()
+	if last, ok := lastAutoLearningsAt[blockId]; ok && now.Sub(last) < AutoLearningsCooldown {
+		return false
+	}
+	if lastLen, ok := lastAutoLearningsLen[blockId]; ok && transcriptLen <= lastLen {
+		return false
+	}
+	lastAutoLearningsAt[blockId] = now
+	lastAutoLearningsLen[blockId] = transcriptLen
+	return true
+}
+
+// pollAutoLearnings re-extracts learnings for every non-archived Claude
+// block flagged with MetaKey_TermDashAutoLearnings whose transcript has
+// grown since the last extraction and whose cooldown has elapsed. Called
+// from pollClaudeBlocks with the same block list that poll cycle already
+// fetched.
+func pollAutoLearnings(ctx context.Context, blocks []*waveobj.Block) {
+	now := time.Now()
+	for _, block := range blocks {
+		if !blockEligibleForAutoLearnings(block.Meta) {
+			continue
+		}
+		_, data, err := filestore.WFS.ReadFile(ctx, block.OID, "termdash:transcript")
+		if err != nil {
+			continue
+		}
+		transcriptLen := len(reconstructTranscriptText(data))
+		if !autoLearningsDueLocked(block.OID, transcriptLen, now) {
+			continue
+		}
+		go runAutoLearningsExtraction(block.OID)
+	}
+}
+
+// blockEligibleForAutoLearnings reports whether a block is flagged for
+// continuous learnings re-extraction: a non-archived Claude block with
+// MetaKey_TermDashAutoLearnings set.
+func blockEligibleForAutoLearnings(meta waveobj.MetaMapType) bool {
+	if meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+		return false
+	}
+	if meta.GetBool(waveobj.MetaKey_TermDashArchived, false) {
+		return false
+	}
+	return meta.GetBool(waveobj.MetaKey_TermDashAutoLearnings, false)
+}
+
+// runAutoLearningsExtraction re-extracts learnings for blockId in the
+// background, logging (rather than propagating) any error, mirroring
+// generateSummary's fire-and-forget pattern in the poll loop.
+func runAutoLearningsExtraction(blockId string) {
+	defer func() {
+		panichandler.PanicHandler("termdash:autoLearnings", recover())
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), SummaryTimeout)
+	defer cancel()
+	if _, err := (&TermDashService{}).ExtractLearnings(ctx, blockId); err != nil {
+		log.Printf("[termdash:learnings] error auto re-extracting learnings for block %s: %v\n", blockId, err)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+)
+
+func TestBlockEligibleForAutoLearningsRequiresFlagClaudeTypeNotArchived(t *testing.T) {
+	flagged := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashType:          "claude",
+		waveobj.MetaKey_TermDashAutoLearnings: true,
+	}
+	if !blockEligibleForAutoLearnings(flagged) {
+		t.Error("blockEligibleForAutoLearnings(flagged claude block) = false, want true")
+	}
+
+	unflagged := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashType: "claude",
+	}
+	if blockEligibleForAutoLearnings(unflagged) {
+		t.Error("blockEligibleForAutoLearnings(unflagged claude block) = true, want false")
+	}
+
+	archived := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashType:          "claude",
+		waveobj.MetaKey_TermDashAutoLearnings: true,
+		waveobj.MetaKey_TermDashArchived:      true,
+	}
+	if blockEligibleForAutoLearnings(archived) {
+		t.Error("blockEligibleForAutoLearnings(archived flagged block) = true, want false")
+	}
+
+	nonClaude := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashType:          "shell",
+		waveobj.MetaKey_TermDashAutoLearnings: true,
+	}
+	if blockEligibleForAutoLearnings(nonClaude) {
+		t.Error("blockEligibleForAutoLearnings(flagged non-claude block) = true, want false")
+	}
+}
+
+func TestAutoLearningsDueLockedTriggersOnGrowthAndRespectsCooldown(t *testing.T) {
+	flaggedBlockId := "test-auto-learnings-flagged-block"
+	unflaggedBlockId := "test-auto-learnings-unflagged-block"
+	now := time.Now()
+
+	if !autoLearningsDueLocked(flaggedBlockId, 100, now) {
+		t.Fatal("autoLearningsDueLocked() = false on first call with a nonzero transcript, want true")
+	}
+
+	if autoLearningsDueLocked(flaggedBlockId, 150, now.Add(1*time.Millisecond)) {
+		t.Error("autoLearningsDueLocked() = true within cooldown despite growth, want false")
+	}
+
+	if autoLearningsDueLocked(flaggedBlockId, 100, now.Add(AutoLearningsCooldown+time.Millisecond)) {
+		t.Error("autoLearningsDueLocked() = true after cooldown but without growth, want false")
+	}
+
+	if !autoLearningsDueLocked(flaggedBlockId, 200, now.Add(AutoLearningsCooldown+time.Millisecond)) {
+		t.Error("autoLearningsDueLocked() = false after cooldown elapsed and transcript grew, want true")
+	}
+
+	// A block that was never flagged never calls autoLearningsDueLocked at all
+	// (pollAutoLearnings filters it out via blockEligibleForAutoLearnings
+	// first), so it has no recorded state and would report due on first use.
+	if !autoLearningsDueLocked(unflaggedBlockId, 100, now) {
+		t.Error("autoLearningsDueLocked() = false for a block with no prior state, want true")
+	}
+}
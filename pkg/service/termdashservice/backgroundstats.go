@@ -0,0 +1,72 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import "sync/atomic"
+
+var (
+	summariesGeneratedCount atomic.Int64
+	summariesFailedCount    atomic.Int64
+	learningsExtractedCount atomic.Int64
+	pollCyclesCount         atomic.Int64
+	cliInvocationsCount     atomic.Int64
+	cliFailuresCount        atomic.Int64
+)
+
+// BackgroundStatsSnapshot is a point-in-time read of the background
+// subsystem's cumulative counters, for an operator to gauge how much work
+// it's doing and its failure rate.
+type BackgroundStatsSnapshot struct {
+	SummariesGenerated int64 `json:"summariesgenerated"`
+	SummariesFailed    int64 `json:"summariesfailed"`
+	LearningsExtracted int64 `json:"learningsextracted"`
+	PollCycles         int64 `json:"pollcycles"`
+	CLIInvocations     int64 `json:"cliinvocations"`
+	CLIFailures        int64 `json:"clifailures"`
+}
+
+// BackgroundStats returns a snapshot of the background subsystem's counters.
+func BackgroundStats() BackgroundStatsSnapshot {
+	return BackgroundStatsSnapshot{
+		SummariesGenerated: summariesGeneratedCount.Load(),
+		SummariesFailed:    summariesFailedCount.Load(),
+		LearningsExtracted: learningsExtractedCount.Load(),
+		PollCycles:         pollCyclesCount.Load(),
+		CLIInvocations:     cliInvocationsCount.Load(),
+		CLIFailures:        cliFailuresCount.Load(),
+	}
+}
+
+// recordSummaryOutcome increments the summary-generated or summary-failed
+// counter depending on whether generateSummary's attempt ended in err.
+func recordSummaryOutcome(err error) {
+	if err != nil {
+		summariesFailedCount.Add(1)
+		return
+	}
+	summariesGeneratedCount.Add(1)
+}
+
+// recordLearningsExtracted increments the learnings-extracted counter by
+// count, the number of insights a single ExtractLearnings call produced.
+func recordLearningsExtracted(count int) {
+	if count > 0 {
+		learningsExtractedCount.Add(int64(count))
+	}
+}
+
+// recordPollCycle increments the poll-cycles counter, once per
+// pollClaudeBlocks invocation.
+func recordPollCycle() {
+	pollCyclesCount.Add(1)
+}
+
+// recordCLIOutcome increments the CLI-invocation counter, and the
+// CLI-failure counter as well when err is non-nil.
+func recordCLIOutcome(err error) {
+	cliInvocationsCount.Add(1)
+	if err != nil {
+		cliFailuresCount.Add(1)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRecordSummaryOutcomeIncrementsGeneratedAndFailedCounters(t *testing.T) {
+	before := BackgroundStats()
+
+	recordSummaryOutcome(nil)
+	recordSummaryOutcome(errors.New("simulated generation failure"))
+
+	after := BackgroundStats()
+
+	if got := after.SummariesGenerated - before.SummariesGenerated; got != 1 {
+		t.Errorf("SummariesGenerated increased by %d, want 1", got)
+	}
+	if got := after.SummariesFailed - before.SummariesFailed; got != 1 {
+		t.Errorf("SummariesFailed increased by %d, want 1", got)
+	}
+}
+
+func TestRecordLearningsExtractedIncrementsByCount(t *testing.T) {
+	before := BackgroundStats()
+
+	recordLearningsExtracted(3)
+	recordLearningsExtracted(0) // no-op: an extraction that yielded nothing
+
+	after := BackgroundStats()
+
+	if got := after.LearningsExtracted - before.LearningsExtracted; got != 3 {
+		t.Errorf("LearningsExtracted increased by %d, want 3", got)
+	}
+}
+
+func TestRecordPollCycleIncrementsCounter(t *testing.T) {
+	before := BackgroundStats()
+
+	recordPollCycle()
+	recordPollCycle()
+
+	after := BackgroundStats()
+
+	if got := after.PollCycles - before.PollCycles; got != 2 {
+		t.Errorf("PollCycles increased by %d, want 2", got)
+	}
+}
+
+func TestRecordCLIOutcomeIncrementsInvocationsAndFailures(t *testing.T) {
+	before := BackgroundStats()
+
+	recordCLIOutcome(nil)
+	recordCLIOutcome(errors.New("simulated CLI failure"))
+
+	after := BackgroundStats()
+
+	if got := after.CLIInvocations - before.CLIInvocations; got != 2 {
+		t.Errorf("CLIInvocations increased by %d, want 2", got)
+	}
+	if got := after.CLIFailures - before.CLIFailures; got != 1 {
+		t.Errorf("CLIFailures increased by %d, want 1", got)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+const (
+	// DefaultClusterSimilarityThreshold is the minimum Jaccard similarity
+	// between two sessions' summary/learnings terms for them to be
+	// considered part of the same cluster.
+	DefaultClusterSimilarityThreshold = 0.3
+
+	// MaxClusterSessions bounds the number of sessions considered by
+	// ClusterSessions, since the agglomerative pass is O(n^2).
+	MaxClusterSessions = 200
+)
+
+// sessionProfile is a session's block ID paired with the term set derived
+// from its summary and learnings, used for similarity comparisons.
+type sessionProfile struct {
+	blockId string
+	terms   map[string]bool
+}
+
+// ClusterSessions groups Claude sessions into clusters by summary/learnings
+// similarity using single-linkage agglomerative clustering with a distance
+// threshold. Each returned cluster is a list of block IDs. This lets the UI
+// show groupings like "you have 3 sessions about the auth refactor."
+func (s *TermDashService) ClusterSessions(ctx context.Context) ([][]string, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	var profiles []sessionProfile
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		summary := block.Meta.GetString(waveobj.MetaKey_TermDashSummary, "")
+		learnings, _ := s.GetLearnings(ctx, block.OID)
+		text := strings.TrimSpace(summary + " " + strings.Join(learnings, " "))
+		if text == "" {
+			continue
+		}
+		terms := extractTerms(text)
+		if len(terms) == 0 {
+			continue
+		}
+		profiles = append(profiles, sessionProfile{blockId: block.OID, terms: terms})
+	}
+
+	return clusterBySimilarity(profiles, ActiveSimilarityConfig.Cluster), nil
+}
+
+// clusterBySimilarity groups profiles using single-linkage agglomerative
+// clustering: repeatedly merge the two closest clusters as long as their
+// similarity is at or above threshold. Bounded to MaxClusterSessions
+// profiles to keep the O(n^2) comparisons tractable for large N.
+func clusterBySimilarity(profiles []sessionProfile, threshold float64) [][]string {
+	if len(profiles) > MaxClusterSessions {
+		profiles = profiles[:MaxClusterSessions]
+	}
+
+	clusters := make([][]sessionProfile, len(profiles))
+	for i, p := range profiles {
+		clusters[i] = []sessionProfile{p}
+	}
+
+	for {
+		bestI, bestJ := -1, -1
+		bestScore := -1.0
+		for i := 0; i < len(clusters); i++ {
+			for j := i + 1; j < len(clusters); j++ {
+				score := clusterSimilarity(clusters[i], clusters[j])
+				if score >= threshold && score > bestScore {
+					bestI, bestJ, bestScore = i, j, score
+				}
+			}
+		}
+		if bestI == -1 {
+			break
+		}
+		clusters[bestI] = append(clusters[bestI], clusters[bestJ]...)
+		clusters = append(clusters[:bestJ], clusters[bestJ+1:]...)
+	}
+
+	result := make([][]string, len(clusters))
+	for i, cluster := range clusters {
+		ids := make([]string, len(cluster))
+		for j, p := range cluster {
+			ids[j] = p.blockId
+		}
+		result[i] = ids
+	}
+	return result
+}
+
+// clusterSimilarity returns the maximum pairwise Jaccard similarity between
+// any two profiles across clusters a and b (single-linkage).
+func clusterSimilarity(a, b []sessionProfile) float64 {
+	best := 0.0
+	for _, pa := range a {
+		for _, pb := range b {
+			if score := jaccardSimilarity(pa.terms, pb.terms); score > best {
+				best = score
+			}
+		}
+	}
+	return best
+}
+
+// jaccardSimilarity returns the intersection-over-union of two term sets.
+func jaccardSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	for term := range a {
+		if b[term] {
+			intersection++
+		}
+	}
+	union := len(a) + len(b) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
@@ -0,0 +1,60 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestClusterBySimilarityFormsTwoClusters(t *testing.T) {
+	profiles := []sessionProfile{
+		{blockId: "auth-1", terms: extractTerms("refactor the auth login token flow")},
+		{blockId: "auth-2", terms: extractTerms("fix auth login token refresh bug")},
+		{blockId: "db-1", terms: extractTerms("migrate the database schema tables")},
+		{blockId: "db-2", terms: extractTerms("update database schema tables again")},
+	}
+
+	clusters := clusterBySimilarity(profiles, DefaultClusterSimilarityThreshold)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d: %v", len(clusters), clusters)
+	}
+
+	var authCluster, dbCluster []string
+	for _, cluster := range clusters {
+		sorted := append([]string(nil), cluster...)
+		sort.Strings(sorted)
+		if sorted[0] == "auth-1" {
+			authCluster = sorted
+		} else {
+			dbCluster = sorted
+		}
+	}
+
+	if !reflect.DeepEqual(authCluster, []string{"auth-1", "auth-2"}) {
+		t.Errorf("expected auth cluster to contain auth-1 and auth-2, got %v", authCluster)
+	}
+	if !reflect.DeepEqual(dbCluster, []string{"db-1", "db-2"}) {
+		t.Errorf("expected db cluster to contain db-1 and db-2, got %v", dbCluster)
+	}
+}
+
+func TestClusterBySimilarityNoMatchesStaySeparate(t *testing.T) {
+	profiles := []sessionProfile{
+		{blockId: "a", terms: extractTerms("completely unrelated topic about cooking")},
+		{blockId: "b", terms: extractTerms("totally different subject involving astronomy")},
+	}
+
+	clusters := clusterBySimilarity(profiles, DefaultClusterSimilarityThreshold)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 separate clusters, got %d: %v", len(clusters), clusters)
+	}
+}
+
+func TestJaccardSimilarityEmptySets(t *testing.T) {
+	if score := jaccardSimilarity(map[string]bool{}, map[string]bool{"a": true}); score != 0 {
+		t.Errorf("expected 0 similarity for empty set, got %f", score)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// ExtractCodeBlocks scans a Claude session's transcript for fenced code
+// blocks so the user can recover a snippet Claude suggested without
+// hunting through the raw transcript.
+func (s *TermDashService) ExtractCodeBlocks(ctx context.Context, blockId string) ([]termdash.CodeBlock, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	return termdash.ExtractCodeBlocks(reconstructTranscriptText(data)), nil
+}
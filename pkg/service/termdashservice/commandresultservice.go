@@ -0,0 +1,23 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// GetCommandResults scans a Claude session's transcript for shell commands
+// run in it and returns each one paired with its exit status when
+// detectable, so the UI can flag commands that failed.
+func (s *TermDashService) GetCommandResults(ctx context.Context, blockId string) ([]termdash.CommandResult, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	return termdash.DetectCommandResults(reconstructTranscriptText(data)), nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// CompactTranscript re-reads a block's transcript, applies stronger
+// deduplication and entry-coalescing than the live recorder, and rewrites
+// the file with the compacted result, reclaiming disk space for archived
+// sessions. It returns the number of bytes saved.
+func (s *TermDashService) CompactTranscript(ctx context.Context, blockId string) (int64, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+
+	format := termdash.TranscriptFormatJSONL
+	if termdash.IsBinaryTranscript(data) {
+		format = termdash.TranscriptFormatBinary
+	}
+	compacted := termdash.EncodeTranscriptEntries(
+		termdash.CompactTranscriptEntries(termdash.DecodeTranscriptEntries(data)),
+		format,
+	)
+
+	err = filestore.WFS.WriteFile(ctx, blockId, "termdash:transcript", compacted)
+	if err != nil {
+		return 0, fmt.Errorf("error writing compacted transcript: %w", err)
+	}
+
+	return int64(len(data)) - int64(len(compacted)), nil
+}
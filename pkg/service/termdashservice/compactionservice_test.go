@@ -0,0 +1,43 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestCompactionShrinksTranscriptAndKeepsMeaningfulContent(t *testing.T) {
+	original := []byte(
+		`{"ts":1000,"type":"output","text":"|"}` + "\n" +
+			`{"ts":1010,"type":"output","text":"|"}` + "\n" +
+			`{"ts":1020,"type":"output","text":"|"}` + "\n" +
+			`{"ts":1030,"type":"output","text":"build finished"}` + "\n" +
+			`{"ts":1040,"type":"input","text":"y"}` + "\n",
+	)
+
+	// Mirrors what CompactTranscript does against a real filestore file.
+	compacted := termdash.MarshalTranscriptEntries(
+		termdash.CompactTranscriptEntries(termdash.ParseTranscriptEntries(original)),
+	)
+
+	if len(compacted) >= len(original) {
+		t.Errorf("compacted size %d, want it smaller than original size %d", len(compacted), len(original))
+	}
+
+	entries := termdash.ParseTranscriptEntries(compacted)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries after compaction, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Text != "[repeated 3 times]" {
+		t.Errorf("entries[0].Text = %q, want a repeat marker", entries[0].Text)
+	}
+	if entries[1].Text != "build finished" {
+		t.Errorf("entries[1].Text = %q, want %q", entries[1].Text, "build finished")
+	}
+	if entries[2].Type != "input" || entries[2].Text != "y" {
+		t.Errorf("entries[2] = %+v, want the input entry preserved", entries[2])
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// ConversationTurn is one grouped turn of a structured conversation view: a
+// user message or a Claude response, with any tool noise inside a Claude
+// turn collapsed into its Text rather than surfaced as separate turns.
+type ConversationTurn struct {
+	Role      string `json:"role"` // "user" or "assistant"
+	Text      string `json:"text"`
+	StartedAt int64  `json:"startedat"`
+	EndedAt   int64  `json:"endedat"`
+}
+
+// turnRole maps a raw TranscriptEntry.Type to the conversational role it
+// belongs to.
+func turnRole(entryType string) string {
+	if entryType == "input" {
+		return "user"
+	}
+	return "assistant"
+}
+
+// structureTranscriptEntries groups entries into alternating user/assistant
+// turns: consecutive entries that map to the same role (e.g. a Claude
+// response interleaved with tool banners, all "output" entries) are merged
+// into a single turn's Text, and a turn starts whenever the role changes.
+// This is the heuristic for ambiguous boundaries: role changes are the only
+// signal used, since the transcript carries no explicit turn markers.
+// Split out from StructureConversation so it's testable without a
+// filestore-backed transcript.
+func structureTranscriptEntries(entries []termdash.TranscriptEntry) []ConversationTurn {
+	var turns []ConversationTurn
+	var current *ConversationTurn
+	for _, entry := range entries {
+		if entry.Text == "" {
+			continue
+		}
+		role := turnRole(entry.Type)
+		if current == nil || current.Role != role {
+			if current != nil {
+				turns = append(turns, *current)
+			}
+			current = &ConversationTurn{
+				Role:      role,
+				StartedAt: entry.Timestamp,
+			}
+		}
+		if current.Text != "" {
+			current.Text += "\n"
+		}
+		current.Text += entry.Text
+		current.EndedAt = entry.Timestamp
+	}
+	if current != nil {
+		turns = append(turns, *current)
+	}
+	return turns
+}
+
+// StructureConversation reads a block's transcript and groups it into
+// alternating user/Claude turns (user message, Claude response, user
+// message, ...), collapsing tool banners and other output noise within a
+// Claude turn into that turn's text. This gives a clean chat-like view of a
+// terminal transcript.
+func (s *TermDashService) StructureConversation(ctx context.Context, blockId string) ([]ConversationTurn, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	entries := termdash.DecodeTranscriptEntries(data)
+	return structureTranscriptEntries(entries), nil
+}
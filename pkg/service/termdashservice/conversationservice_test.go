@@ -0,0 +1,76 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestStructureTranscriptEntriesGroupsAlternatingTurns(t *testing.T) {
+	entries := []termdash.TranscriptEntry{
+		{Timestamp: 1, Type: "input", Text: "ls -la"},
+		{Timestamp: 2, Type: "output", Text: "total 0"},
+		{Timestamp: 3, Type: "output", Text: "Running tool: Read"},
+		{Timestamp: 4, Type: "output", Text: "drwxr-xr-x  2 user user 4096 file.txt"},
+		{Timestamp: 5, Type: "input", Text: "git status"},
+		{Timestamp: 6, Type: "output", Text: "nothing to commit"},
+	}
+
+	turns := structureTranscriptEntries(entries)
+	if len(turns) != 4 {
+		t.Fatalf("expected 4 turns, got %d: %+v", len(turns), turns)
+	}
+
+	wantRoles := []string{"user", "assistant", "user", "assistant"}
+	for i, want := range wantRoles {
+		if turns[i].Role != want {
+			t.Errorf("turns[%d].Role = %q, want %q", i, turns[i].Role, want)
+		}
+	}
+
+	if turns[0].Text != "ls -la" {
+		t.Errorf("turns[0].Text = %q, want %q", turns[0].Text, "ls -la")
+	}
+
+	// The three consecutive "output" entries (real output plus tool noise)
+	// collapse into a single assistant turn.
+	wantAssistant := "total 0\nRunning tool: Read\ndrwxr-xr-x  2 user user 4096 file.txt"
+	if turns[1].Text != wantAssistant {
+		t.Errorf("turns[1].Text = %q, want %q", turns[1].Text, wantAssistant)
+	}
+	if turns[1].StartedAt != 2 || turns[1].EndedAt != 4 {
+		t.Errorf("turns[1] StartedAt/EndedAt = %d/%d, want 2/4", turns[1].StartedAt, turns[1].EndedAt)
+	}
+
+	if turns[2].Text != "git status" {
+		t.Errorf("turns[2].Text = %q, want %q", turns[2].Text, "git status")
+	}
+	if turns[3].Text != "nothing to commit" {
+		t.Errorf("turns[3].Text = %q, want %q", turns[3].Text, "nothing to commit")
+	}
+}
+
+func TestStructureTranscriptEntriesSkipsEmptyEntries(t *testing.T) {
+	entries := []termdash.TranscriptEntry{
+		{Timestamp: 1, Type: "input", Text: "ls"},
+		{Timestamp: 2, Type: "output", Text: ""},
+		{Timestamp: 3, Type: "output", Text: "file.txt"},
+	}
+
+	turns := structureTranscriptEntries(entries)
+	if len(turns) != 2 {
+		t.Fatalf("expected 2 turns, got %d: %+v", len(turns), turns)
+	}
+	if turns[1].Text != "file.txt" {
+		t.Errorf("turns[1].Text = %q, want %q", turns[1].Text, "file.txt")
+	}
+}
+
+func TestStructureTranscriptEntriesEmptyTranscriptReturnsNil(t *testing.T) {
+	if turns := structureTranscriptEntries(nil); turns != nil {
+		t.Errorf("structureTranscriptEntries(nil) = %v, want nil", turns)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// GetCwdHistory replays a Claude session's recorded input for cd commands
+// and returns every working directory it visited, starting with the
+// session's initial cwd, so file-path anchoring (extraction, language
+// detection) can be corrected for a session that changed directories.
+func (s *TermDashService) GetCwdHistory(ctx context.Context, blockId string) ([]string, error) {
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+
+	startCwd := block.Meta.GetString(waveobj.MetaKey_CmdCwd, "")
+	return termdash.DetectCwdChanges(reconstructInputText(data), startCwd, ""), nil
+}
+
+// reconstructInputText concatenates the text of only the "input"-typed
+// entries in transcript data, in order, reconstructing the raw keystroke
+// stream sent to the pty (as opposed to reconstructTranscriptText, which
+// concatenates every entry regardless of type).
+func reconstructInputText(data []byte) string {
+	var text string
+	for _, entry := range termdash.DecodeTranscriptEntries(data) {
+		if entry.Type == "input" {
+			text += entry.Text
+		}
+	}
+	return text
+}
@@ -0,0 +1,35 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"sync"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+var (
+	detectorRegistryMu sync.Mutex
+	detectorRegistry   = make(map[string]*termdash.StatusDetector)
+)
+
+// IsDetectorAttached reports whether blockId currently has a live
+// StatusDetector registered via AttachDetector.
+func IsDetectorAttached(blockId string) bool {
+	detectorRegistryMu.Lock()
+	defer detectorRegistryMu.Unlock()
+	_, ok := detectorRegistry[blockId]
+	return ok
+}
+
+// DetachDetector removes blockId's registered StatusDetector, if any, so a
+// later AttachDetector call for the same block creates a fresh one instead
+// of returning the stale one. It does not stop the detector -- the caller
+// is still responsible for calling StatusDetector.Stop() itself, the same
+// as before this registry existed.
+func DetachDetector(blockId string) {
+	detectorRegistryMu.Lock()
+	defer detectorRegistryMu.Unlock()
+	delete(detectorRegistry, blockId)
+}
@@ -0,0 +1,41 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestAttachDetectorIsIdempotent(t *testing.T) {
+	detectorRegistryMu.Lock()
+	detectorRegistry = make(map[string]*termdash.StatusDetector)
+	detectorRegistryMu.Unlock()
+	t.Cleanup(func() {
+		detectorRegistryMu.Lock()
+		detectorRegistry = make(map[string]*termdash.StatusDetector)
+		detectorRegistryMu.Unlock()
+	})
+
+	if IsDetectorAttached("block-1") {
+		t.Fatal("expected block-1 to have no detector attached yet")
+	}
+
+	first := AttachDetector("block-1", nil)
+	defer first.Stop()
+	if !IsDetectorAttached("block-1") {
+		t.Fatal("expected block-1 to be attached after AttachDetector")
+	}
+
+	second := AttachDetector("block-1", nil)
+	if second != first {
+		t.Error("expected a second AttachDetector call for the same block to return the existing detector")
+	}
+
+	DetachDetector("block-1")
+	if IsDetectorAttached("block-1") {
+		t.Error("expected block-1 to no longer be attached after DetachDetector")
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+const (
+	// DuplicateSimilarityThreshold is the minimum Jaccard similarity between
+	// two sessions' summary/early-transcript terms for them to be flagged as
+	// likely duplicates. Higher than DefaultClusterSimilarityThreshold since
+	// a duplicate flag (suggesting the user merge or archive a session) is a
+	// stronger claim than a topical cluster.
+	DuplicateSimilarityThreshold = 0.6
+
+	// DuplicateTimeWindow is how close together two sessions must have
+	// started to be considered possible duplicates, since unrelated sessions
+	// about similar topics started weeks apart are unlikely to be the same
+	// forgotten task.
+	DuplicateTimeWindow = 24 * time.Hour
+
+	// earlyTranscriptLines is how many leading transcript lines are used to
+	// compare sessions' early context, on the theory that a duplicate task
+	// restates the same goal near the start of the session.
+	earlyTranscriptLines = 20
+)
+
+// firstNLines returns the first n lines of text, joined back with newlines.
+func firstNLines(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) <= n {
+		return text
+	}
+	return strings.Join(lines[:n], "\n")
+}
+
+// duplicateProfile is a session's block ID paired with the term set derived
+// from its summary and early transcript, and the time it started, used to
+// detect likely-duplicate sessions.
+type duplicateProfile struct {
+	blockId string
+	terms   map[string]bool
+	startTs int64
+}
+
+// sessionStartTs returns the timestamp of a block's earliest recorded status
+// transition, used as its session start time. Returns 0, false if the block
+// has no status history yet.
+func sessionStartTs(ctx context.Context, blockId string) (int64, bool) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, StatusHistoryFile)
+	if err != nil {
+		return 0, false
+	}
+	entries := termdash.ParseStatusHistory(data)
+	if len(entries) == 0 {
+		return 0, false
+	}
+	return entries[0].Timestamp, true
+}
+
+// FindDuplicateSessions groups Claude sessions that look like the same task
+// started twice: their summaries and early transcripts are highly similar
+// and they were started close together in time. Each returned group is a
+// list of block IDs the caller can offer to merge or archive. Builds on the
+// same term-overlap similarity scoring as ClusterSessions, but additionally
+// requires the sessions to have started within DuplicateTimeWindow of each
+// other, since topic similarity alone doesn't imply the same forgotten task.
+func (s *TermDashService) FindDuplicateSessions(ctx context.Context) ([][]string, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	var profiles []duplicateProfile
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		startTs, ok := sessionStartTs(ctx, block.OID)
+		if !ok {
+			continue
+		}
+
+		summary := block.Meta.GetString(waveobj.MetaKey_TermDashSummary, "")
+		early := ""
+		if _, data, err := filestore.WFS.ReadFile(ctx, block.OID, "termdash:transcript"); err == nil {
+			early = firstNLines(reconstructTranscriptText(data), earlyTranscriptLines)
+		}
+		text := strings.TrimSpace(summary + " " + early)
+		if text == "" {
+			continue
+		}
+		terms := extractTerms(text)
+		if len(terms) == 0 {
+			continue
+		}
+		profiles = append(profiles, duplicateProfile{blockId: block.OID, terms: terms, startTs: startTs})
+	}
+
+	return groupDuplicates(profiles, ActiveSimilarityConfig.Duplicate, DuplicateTimeWindow), nil
+}
+
+// groupDuplicates clusters profiles using single-linkage agglomerative
+// clustering, same as clusterBySimilarity, but only merges two profiles when
+// both their term similarity is at or above threshold and their start times
+// are within window of each other.
+func groupDuplicates(profiles []duplicateProfile, threshold float64, window time.Duration) [][]string {
+	windowMs := window.Milliseconds()
+
+	clusters := make([][]duplicateProfile, len(profiles))
+	for i, p := range profiles {
+		clusters[i] = []duplicateProfile{p}
+	}
+
+	for {
+		bestI, bestJ := -1, -1
+		bestScore := -1.0
+		for i := 0; i < len(clusters); i++ {
+			for j := i + 1; j < len(clusters); j++ {
+				score := duplicateSimilarity(clusters[i], clusters[j], windowMs)
+				if score >= threshold && score > bestScore {
+					bestI, bestJ, bestScore = i, j, score
+				}
+			}
+		}
+		if bestI == -1 {
+			break
+		}
+		clusters[bestI] = append(clusters[bestI], clusters[bestJ]...)
+		clusters = append(clusters[:bestJ], clusters[bestJ+1:]...)
+	}
+
+	var result [][]string
+	for _, cluster := range clusters {
+		if len(cluster) < 2 {
+			continue // a lone session isn't a duplicate of anything
+		}
+		ids := make([]string, len(cluster))
+		for j, p := range cluster {
+			ids[j] = p.blockId
+		}
+		result = append(result, ids)
+	}
+	return result
+}
+
+// duplicateSimilarity returns the maximum pairwise term similarity between
+// any two profiles across clusters a and b whose start times fall within
+// windowMs of each other (single-linkage), or 0 if no pair qualifies on
+// time proximity.
+func duplicateSimilarity(a, b []duplicateProfile, windowMs int64) float64 {
+	best := 0.0
+	for _, pa := range a {
+		for _, pb := range b {
+			diff := pa.startTs - pb.startTs
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff > windowMs {
+				continue
+			}
+			if score := jaccardSimilarity(pa.terms, pb.terms); score > best {
+				best = score
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,56 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupDuplicatesFlagsNearIdenticalSessionsStartedCloseTogether(t *testing.T) {
+	baseTs := int64(1_700_000_000_000)
+	text := "fix the authentication bug in the login flow before the release"
+
+	profiles := []duplicateProfile{
+		{blockId: "block-1", terms: extractTerms(text), startTs: baseTs},
+		{blockId: "block-2", terms: extractTerms(text), startTs: baseTs + time.Minute.Milliseconds()},
+		{blockId: "block-3", terms: extractTerms("refactor the pricing page css layout"), startTs: baseTs},
+	}
+
+	groups := groupDuplicates(profiles, DuplicateSimilarityThreshold, DuplicateTimeWindow)
+
+	if len(groups) != 1 {
+		t.Fatalf("groupDuplicates() returned %d groups, want 1: %v", len(groups), groups)
+	}
+	got := map[string]bool{groups[0][0]: true, groups[0][1]: true}
+	if len(groups[0]) != 2 || !got["block-1"] || !got["block-2"] {
+		t.Errorf("groupDuplicates() group = %v, want [block-1 block-2]", groups[0])
+	}
+}
+
+func TestGroupDuplicatesIgnoresSimilarSessionsStartedFarApart(t *testing.T) {
+	baseTs := int64(1_700_000_000_000)
+	text := "fix the authentication bug in the login flow before the release"
+
+	profiles := []duplicateProfile{
+		{blockId: "block-1", terms: extractTerms(text), startTs: baseTs},
+		{blockId: "block-2", terms: extractTerms(text), startTs: baseTs + 2*DuplicateTimeWindow.Milliseconds()},
+	}
+
+	groups := groupDuplicates(profiles, DuplicateSimilarityThreshold, DuplicateTimeWindow)
+
+	if len(groups) != 0 {
+		t.Errorf("groupDuplicates() = %v, want no groups for sessions started far apart", groups)
+	}
+}
+
+func TestGroupDuplicatesOmitsLoneSessions(t *testing.T) {
+	profiles := []duplicateProfile{
+		{blockId: "block-1", terms: extractTerms("a unique task with no siblings"), startTs: 1000},
+	}
+
+	if groups := groupDuplicates(profiles, DuplicateSimilarityThreshold, DuplicateTimeWindow); len(groups) != 0 {
+		t.Errorf("groupDuplicates() = %v, want no groups for a single session", groups)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// ExtractFailedEdits scans a Claude session's transcript for recognizable
+// file-edit failures (a diff that didn't apply, a file that changed out
+// from under an edit) so the user can be alerted that an intended change
+// silently failed.
+func (s *TermDashService) ExtractFailedEdits(ctx context.Context, blockId string) ([]termdash.FailedEdit, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	return termdash.DetectFailedEdits(reconstructTranscriptText(data)), nil
+}
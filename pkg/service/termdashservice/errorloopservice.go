@@ -0,0 +1,37 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+	"github.com/wavetermdev/waveterm/pkg/wconfig"
+)
+
+// DetectErrorLoop scans a Claude session's transcript for an error message
+// that repeats often enough to indicate Claude is stuck retrying the same
+// failing action, using the configured loop threshold and window (or
+// termdash's defaults if unset).
+func (s *TermDashService) DetectErrorLoop(ctx context.Context, blockId string) (termdash.ErrorLoop, bool, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return termdash.ErrorLoop{}, false, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+
+	threshold, window := errorLoopConfig()
+	loop, found := termdash.DetectErrorLoop(reconstructTranscriptText(data), threshold, window)
+	return loop, found, nil
+}
+
+// errorLoopConfig returns the configured error-loop threshold and window,
+// read fresh from settings.json so a config change takes effect without a
+// restart. Unset values are passed through as 0, which DetectErrorLoop
+// treats as "use the default".
+func errorLoopConfig() (threshold int, window int) {
+	settings := wconfig.GetWatcher().GetFullConfig().Settings
+	return int(floatPtrOrZero(settings.TermDashErrorLoopThreshold)), int(floatPtrOrZero(settings.TermDashErrorLoopWindow))
+}
@@ -0,0 +1,36 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import "errors"
+
+// Sentinel errors returned by TermDashService methods, wrapped with %w so
+// callers (including the frontend, over JSON-RPC) can distinguish them with
+// errors.Is instead of matching on error strings.
+var (
+	// ErrBlockNotFound indicates the requested block does not exist.
+	ErrBlockNotFound = errors.New("block not found")
+
+	// ErrNoTranscript indicates a block has no recorded transcript yet.
+	ErrNoTranscript = errors.New("no transcript found")
+
+	// ErrNoLearnings indicates a block has no extracted learnings yet.
+	ErrNoLearnings = errors.New("no learnings found")
+
+	// ErrGeneratorUnavailable indicates the configured title/learnings
+	// generator (the claude CLI) could not be invoked.
+	ErrGeneratorUnavailable = errors.New("generator unavailable")
+
+	// ErrNoStatusHistory indicates a block has no recorded status history yet.
+	ErrNoStatusHistory = errors.New("no status history found")
+
+	// ErrSummaryCooldown indicates GetOrGenerateSummary was called again for
+	// a block before SummaryRegenerationCooldown elapsed since its last
+	// generation attempt.
+	ErrSummaryCooldown = errors.New("summary regeneration cooldown in effect")
+
+	// ErrEmptyDigestWindow indicates GenerateInsightsDigest found no session
+	// summaries or learnings within the requested time window.
+	ErrEmptyDigestWindow = errors.New("no sessions or learnings found in the given window")
+)
@@ -0,0 +1,43 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// These mirror the exact wrapping each service method performs, so that
+// errors.Is behavior is verified independent of the filestore/DB backends
+// those methods depend on (which have no unit-test seam in this package).
+func TestSentinelErrorsMatchViaErrorsIs(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"transcript not found", fmt.Errorf("%w: %v", ErrNoTranscript, errors.New("read error")), ErrNoTranscript},
+		{"learnings not found", fmt.Errorf("%w: %v", ErrNoLearnings, errors.New("read error")), ErrNoLearnings},
+		{"generator unavailable", fmt.Errorf("%w: %v", ErrGeneratorUnavailable, errors.New("exec: \"claude\": executable file not found in $PATH")), ErrGeneratorUnavailable},
+		{"block not found", fmt.Errorf("%w: %v", ErrBlockNotFound, wstore.ErrNotFound), ErrBlockNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.want) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", tt.err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentinelErrorsDoNotCrossMatch(t *testing.T) {
+	err := fmt.Errorf("%w: %v", ErrNoTranscript, errors.New("read error"))
+	if errors.Is(err, ErrNoLearnings) {
+		t.Errorf("expected ErrNoTranscript wrap to not match ErrNoLearnings")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// MaxExportFilenameSummaryLength caps how much of a block's summary
+// contributes to its exported filename, so a long title doesn't produce an
+// unwieldy path.
+const MaxExportFilenameSummaryLength = 60
+
+// unsafeFilenameChars matches runs of characters not safe to use verbatim
+// in a filename, replaced with "_" by sanitizeFilename.
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// sanitizeFilename replaces characters unsafe for a filename with "_",
+// trims the result, and caps it at MaxExportFilenameSummaryLength.
+func sanitizeFilename(s string) string {
+	s = unsafeFilenameChars.ReplaceAllString(s, "_")
+	s = strings.Trim(s, "_")
+	if len(s) > MaxExportFilenameSummaryLength {
+		s = strings.Trim(s[:MaxExportFilenameSummaryLength], "_")
+	}
+	return s
+}
+
+// exportFilename builds a block's exported transcript filename from its
+// sanitized summary plus its block ID, so the file is both
+// human-identifiable and (since block IDs are unique) collision-resistant
+// on its own; uniqueExportPath still guards against a pre-existing file at
+// that path, e.g. from an earlier export into the same directory.
+func exportFilename(blockId, summary string) string {
+	name := sanitizeFilename(summary)
+	if name == "" {
+		return blockId + ".txt"
+	}
+	return name + "-" + blockId + ".txt"
+}
+
+// uniqueExportPath joins destDir and filename, appending a numeric suffix
+// before the extension if a file already exists at that path.
+func uniqueExportPath(destDir, filename string) string {
+	path := filepath.Join(destDir, filename)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path
+	}
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	for i := 2; ; i++ {
+		candidate := filepath.Join(destDir, fmt.Sprintf("%s-%d%s", base, i, ext))
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// exportEntry is one block's data to write during ExportAllTranscripts,
+// separated from the DB/filestore lookups in ExportAllTranscripts so the
+// actual file-writing and naming logic is testable without a
+// database-backed set of blocks.
+type exportEntry struct {
+	blockId string
+	summary string
+	text    string
+}
+
+// writeTranscriptExports writes each entry's transcript text to a file
+// under destDir, creating destDir if needed, and returns how many files
+// were written.
+func writeTranscriptExports(destDir string, entries []exportEntry) (int, error) {
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return 0, fmt.Errorf("error creating destination directory: %w", err)
+	}
+
+	count := 0
+	for _, e := range entries {
+		path := uniqueExportPath(destDir, exportFilename(e.blockId, e.summary))
+		if err := os.WriteFile(path, []byte(e.text), 0o644); err != nil {
+			return count, fmt.Errorf("error writing transcript for block %s: %w", e.blockId, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// ExportAllTranscripts writes every Claude block's reconstructed transcript
+// to a file under destDir, one per session, for offline backup. Returns the
+// number of transcripts written.
+func (s *TermDashService) ExportAllTranscripts(ctx context.Context, destDir string) (int, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return 0, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	var entries []exportEntry
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+
+		_, data, err := filestore.WFS.ReadFile(ctx, block.OID, "termdash:transcript")
+		if err != nil {
+			continue // no transcript yet
+		}
+
+		entries = append(entries, exportEntry{
+			blockId: block.OID,
+			summary: block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""),
+			text:    reconstructTranscriptText(data),
+		})
+	}
+	return writeTranscriptExports(destDir, entries)
+}
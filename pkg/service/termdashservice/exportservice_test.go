@@ -0,0 +1,96 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeFilenameReplacesUnsafeCharacters(t *testing.T) {
+	got := sanitizeFilename("Fix the / auth: bug? (urgent!)")
+	if strings.ContainsAny(got, "/:?!() ") {
+		t.Errorf("sanitizeFilename() = %q, want unsafe characters replaced", got)
+	}
+}
+
+func TestSanitizeFilenameCapsLength(t *testing.T) {
+	got := sanitizeFilename(strings.Repeat("a", MaxExportFilenameSummaryLength+50))
+	if len(got) > MaxExportFilenameSummaryLength {
+		t.Errorf("sanitizeFilename() length = %d, want <= %d", len(got), MaxExportFilenameSummaryLength)
+	}
+}
+
+func TestExportFilenameFallsBackToBlockIdWhenSummaryEmpty(t *testing.T) {
+	got := exportFilename("block-123", "")
+	if got != "block-123.txt" {
+		t.Errorf("exportFilename() = %q, want %q", got, "block-123.txt")
+	}
+}
+
+func TestWriteTranscriptExportsCreatesExpectedFiles(t *testing.T) {
+	destDir := t.TempDir()
+
+	entries := []exportEntry{
+		{blockId: "block-1", summary: "Fix auth bug", text: "session one transcript"},
+		{blockId: "block-2", summary: "Add dark mode", text: "session two transcript"},
+	}
+
+	count, err := writeTranscriptExports(destDir, entries)
+	if err != nil {
+		t.Fatalf("writeTranscriptExports() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("writeTranscriptExports() count = %d, want 2", count)
+	}
+
+	wantFiles := map[string]string{
+		"Fix_auth_bug-block-1.txt":  "session one transcript",
+		"Add_dark_mode-block-2.txt": "session two transcript",
+	}
+	for name, wantContent := range wantFiles {
+		data, err := os.ReadFile(filepath.Join(destDir, name))
+		if err != nil {
+			t.Fatalf("expected file %q not found: %v", name, err)
+		}
+		if string(data) != wantContent {
+			t.Errorf("file %q content = %q, want %q", name, string(data), wantContent)
+		}
+	}
+}
+
+func TestWriteTranscriptExportsHandlesFilenameCollision(t *testing.T) {
+	destDir := t.TempDir()
+
+	entry := exportEntry{blockId: "block-1", summary: "Fix auth bug", text: "new content"}
+	preexisting := filepath.Join(destDir, exportFilename(entry.blockId, entry.summary))
+	if err := os.WriteFile(preexisting, []byte("old content"), 0o644); err != nil {
+		t.Fatalf("failed to seed pre-existing file: %v", err)
+	}
+
+	count, err := writeTranscriptExports(destDir, []exportEntry{entry})
+	if err != nil {
+		t.Fatalf("writeTranscriptExports() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("writeTranscriptExports() count = %d, want 1", count)
+	}
+
+	// The pre-existing file must be untouched, and the new content written
+	// to a disambiguated path instead of overwriting it.
+	oldData, err := os.ReadFile(preexisting)
+	if err != nil || string(oldData) != "old content" {
+		t.Errorf("pre-existing file was modified: data=%q err=%v", oldData, err)
+	}
+
+	entries, err := os.ReadDir(destDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("destDir has %d files, want 2 (original + disambiguated)", len(entries))
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// GetFileGraph returns a block's session-scoped file-interaction graph:
+// every file Claude read or wrote and in what order, built from the
+// stored transcript's Read/Edit/@file references.
+func (s *TermDashService) GetFileGraph(ctx context.Context, blockId string) (*termdash.FileGraph, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	return termdash.DetectFileGraph(reconstructTranscriptText(data)), nil
+}
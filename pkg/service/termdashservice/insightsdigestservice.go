@@ -0,0 +1,245 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/wavebase"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+const (
+	// DefaultDigestModel is the claude CLI model GenerateInsightsDigest
+	// invokes by default.
+	DefaultDigestModel = "haiku"
+
+	// DefaultDigestPromptTemplate is the text/template rendered into the
+	// prompt sent to the model. It must reference {{.Summaries}} and
+	// {{.Learnings}}.
+	DefaultDigestPromptTemplate = "Write a short, readable digest of recent Claude Code activity, in the style of " +
+		"\"This week you worked on X, Y, Z and learned A, B, C.\" Base it only on the session summaries and " +
+		"learnings below. Keep it to 1-2 short paragraphs.\n\n" +
+		"Session summaries:\n{{.Summaries}}\n\nLearnings:\n{{.Learnings}}"
+
+	// DefaultDigestCacheTTL is how long GenerateInsightsDigest reuses a
+	// previously generated digest for the same window instead of invoking
+	// the generator again.
+	DefaultDigestCacheTTL = 15 * time.Minute
+)
+
+// DigestConfig configures GenerateInsightsDigest's generation call: which
+// model to invoke and the prompt template rendered against the gathered
+// summaries and learnings.
+type DigestConfig struct {
+	Model          string
+	PromptTemplate string // text/template; must reference {{.Summaries}} and {{.Learnings}}
+}
+
+// DefaultDigestConfig holds GenerateInsightsDigest's original hardcoded
+// model and prompt values.
+var DefaultDigestConfig = DigestConfig{
+	Model:          DefaultDigestModel,
+	PromptTemplate: DefaultDigestPromptTemplate,
+}
+
+// ActiveDigestConfig is the configuration GenerateInsightsDigest uses.
+// Defaults to DefaultDigestConfig.
+var ActiveDigestConfig = DefaultDigestConfig
+
+// DigestCacheTTL controls how long GenerateInsightsDigest caches a digest
+// per window. Defaults to DefaultDigestCacheTTL.
+var DigestCacheTTL = DefaultDigestCacheTTL
+
+// digestPromptData is rendered into a DigestConfig's PromptTemplate.
+type digestPromptData struct {
+	Summaries string
+	Learnings string
+}
+
+// renderDigestPrompt parses and executes cfg.PromptTemplate against
+// summaries and learnings, returning an error if the template is invalid or
+// doesn't actually reference both, so a misconfigured template fails loudly
+// instead of silently sending the model a static prompt.
+func renderDigestPrompt(cfg DigestConfig, summaries, learnings string) (string, error) {
+	tmpl, err := template.New("digest-prompt").Parse(cfg.PromptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid digest prompt template: %w", err)
+	}
+	var buf strings.Builder
+	data := digestPromptData{Summaries: summaries, Learnings: learnings}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering digest prompt template: %w", err)
+	}
+	rendered := buf.String()
+	if !strings.Contains(rendered, summaries) || !strings.Contains(rendered, learnings) {
+		return "", fmt.Errorf("digest prompt template must reference {{.Summaries}} and {{.Learnings}}")
+	}
+	return rendered, nil
+}
+
+// generateDigestFromContent renders cfg's prompt template against summaries
+// and learnings and invokes the configured generator, returning the
+// trimmed digest text. Split from GenerateInsightsDigest so the generation
+// path is testable without a filestore/DB-backed set of blocks. Reuses
+// learningsGeneratorFn, the same claude CLI invocation ExtractLearnings
+// uses, rather than introducing a second generator backend.
+func generateDigestFromContent(ctx context.Context, cfg DigestConfig, summaries, learnings []string) (string, error) {
+	if len(summaries) == 0 && len(learnings) == 0 {
+		return "", ErrEmptyDigestWindow
+	}
+
+	prompt, err := renderDigestPrompt(cfg, joinDigestLines(summaries), joinDigestLines(learnings))
+	if err != nil {
+		return "", err
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	output, err := learningsGeneratorFn(execCtx, cfg.Model, prompt)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// joinDigestLines renders lines as a "- "-prefixed bullet list, or "(none)"
+// if there are none, so the prompt always has something readable to show
+// the model for an empty side of the digest.
+func joinDigestLines(lines []string) string {
+	if len(lines) == 0 {
+		return "(none)"
+	}
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString("- ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+type digestCacheEntry struct {
+	text        string
+	generatedAt time.Time
+}
+
+var (
+	digestCacheMu      sync.Mutex
+	digestCacheEntries = map[int64]digestCacheEntry{}
+)
+
+// digestCacheKey canonicalizes since to the enclosing hour, so repeated
+// calls with a "since 7 days ago"-style rolling window still hit the same
+// cache entry instead of missing on sub-second timestamp drift.
+func digestCacheKey(since time.Time) int64 {
+	return since.Truncate(time.Hour).Unix()
+}
+
+// getCachedDigestLocked returns a previously generated digest for since's
+// window if one was cached within DigestCacheTTL.
+func getCachedDigestLocked(since time.Time, now time.Time) (string, bool) {
+	digestCacheMu.Lock()
+	defer digestCacheMu.Unlock()
+	key := digestCacheKey(since)
+	entry, ok := digestCacheEntries[key]
+	if !ok || now.Sub(entry.generatedAt) >= DigestCacheTTL {
+		return "", false
+	}
+	return entry.text, true
+}
+
+// setCachedDigestLocked records digest as the cached result for since's
+// window as of now.
+func setCachedDigestLocked(since time.Time, digest string, now time.Time) {
+	digestCacheMu.Lock()
+	defer digestCacheMu.Unlock()
+	digestCacheEntries[digestCacheKey(since)] = digestCacheEntry{text: digest, generatedAt: now}
+}
+
+// GenerateInsightsDigest gathers Claude session summaries and extracted
+// learnings recorded since the given time and produces a short, readable
+// digest of what was worked on and learned, suitable for a standup or
+// personal review. Results are cached per rolling window (see
+// digestCacheKey) for DigestCacheTTL, so repeated calls within that window
+// don't re-invoke the generator.
+func (s *TermDashService) GenerateInsightsDigest(ctx context.Context, since time.Time) (string, error) {
+	now := time.Now()
+	if cached, ok := getCachedDigestLocked(since, now); ok {
+		return cached, nil
+	}
+
+	summaries, err := s.collectSessionSummariesSince(ctx, since)
+	if err != nil {
+		return "", err
+	}
+	learnings, err := s.collectLearningsSince(ctx, since)
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := generateDigestFromContent(ctx, ActiveDigestConfig, summaries, learnings)
+	if err != nil {
+		return "", err
+	}
+
+	setCachedDigestLocked(since, digest, now)
+	return digest, nil
+}
+
+// collectSessionSummariesSince returns the summaries of Claude sessions
+// whose terminal output has been touched since the given time, determined
+// from the terminal output blockfile's (wavebase.BlockFile_Term)
+// modification time rather than block metadata,
+// since active sessions don't otherwise carry a last-activity timestamp.
+func (s *TermDashService) collectSessionSummariesSince(ctx context.Context, since time.Time) ([]string, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	sinceMs := since.UnixMilli()
+	var summaries []string
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		summary := block.Meta.GetString(waveobj.MetaKey_TermDashSummary, "")
+		if summary == "" {
+			continue
+		}
+		wfile, err := filestore.WFS.Stat(ctx, block.OID, wavebase.BlockFile_Term)
+		if err != nil || wfile.ModTs < sinceMs {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// collectLearningsSince returns the text of learnings recorded (via
+// recordStructuredLearnings) since the given time.
+func (s *TermDashService) collectLearningsSince(ctx context.Context, since time.Time) ([]string, error) {
+	all, err := s.collectStructuredLearnings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sinceMs := since.UnixMilli()
+	var learnings []string
+	for _, l := range all {
+		if l.Timestamp >= sinceMs {
+			learnings = append(learnings, l.Text)
+		}
+	}
+	return learnings, nil
+}
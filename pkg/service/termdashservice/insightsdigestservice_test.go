@@ -0,0 +1,79 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateDigestFromContentIncorporatesSummariesAndLearnings(t *testing.T) {
+	origGenerator := learningsGeneratorFn
+	defer func() { learningsGeneratorFn = origGenerator }()
+
+	var gotModel, gotPrompt string
+	learningsGeneratorFn = func(ctx context.Context, model, prompt string) (string, error) {
+		gotModel = model
+		gotPrompt = prompt
+		return "This week you worked on the auth refactor and learned to prefer early returns.\n", nil
+	}
+
+	cfg := DigestConfig{
+		Model:          "haiku",
+		PromptTemplate: DefaultDigestPromptTemplate,
+	}
+	summaries := []string{"Refactored the auth middleware", "Fixed a flaky test"}
+	learnings := []string{"prefer early returns over deeply nested conditionals"}
+
+	digest, err := generateDigestFromContent(t.Context(), cfg, summaries, learnings)
+	if err != nil {
+		t.Fatalf("generateDigestFromContent() error = %v", err)
+	}
+
+	if gotModel != "haiku" {
+		t.Errorf("generator received model %q, want %q", gotModel, "haiku")
+	}
+	for _, want := range append(append([]string{}, summaries...), learnings...) {
+		if !strings.Contains(gotPrompt, want) {
+			t.Errorf("prompt %q does not mention %q", gotPrompt, want)
+		}
+	}
+	wantDigest := "This week you worked on the auth refactor and learned to prefer early returns."
+	if digest != wantDigest {
+		t.Errorf("generateDigestFromContent() = %q, want %q", digest, wantDigest)
+	}
+}
+
+func TestGenerateDigestFromContentRefusesEmptyWindow(t *testing.T) {
+	_, err := generateDigestFromContent(t.Context(), DefaultDigestConfig, nil, nil)
+	if !errors.Is(err, ErrEmptyDigestWindow) {
+		t.Errorf("generateDigestFromContent() error = %v, want ErrEmptyDigestWindow", err)
+	}
+}
+
+func TestDigestCacheReturnsCachedResultWithinTTL(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	if _, ok := getCachedDigestLocked(since, now); ok {
+		t.Fatal("getCachedDigestLocked() found an entry before any was set")
+	}
+
+	setCachedDigestLocked(since, "cached digest text", now)
+
+	got, ok := getCachedDigestLocked(since, now.Add(DigestCacheTTL-time.Minute))
+	if !ok {
+		t.Fatal("getCachedDigestLocked() = not found within TTL, want found")
+	}
+	if got != "cached digest text" {
+		t.Errorf("getCachedDigestLocked() = %q, want %q", got, "cached digest text")
+	}
+
+	if _, ok := getCachedDigestLocked(since, now.Add(DigestCacheTTL+time.Minute)); ok {
+		t.Error("getCachedDigestLocked() = found after TTL elapsed, want not found")
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// IsInteractiveSession reports whether a Claude block is running an
+// interactive session, as opposed to a one-shot `claude -p` invocation
+// that never presents a prompt. Callers like the status detector and idle
+// sweep use this to skip work that only makes sense for a session that can
+// actually go idle or need input. Defaults to true for blocks that predate
+// MetaKey_TermDashInteractive.
+func (s *TermDashService) IsInteractiveSession(ctx context.Context, blockId string) (bool, error) {
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return block.Meta.GetBool(waveobj.MetaKey_TermDashInteractive, true), nil
+}
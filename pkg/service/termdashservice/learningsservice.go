@@ -4,69 +4,157 @@
 package termdashservice
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os/exec"
+	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/wavetermdev/waveterm/pkg/filestore"
 	"github.com/wavetermdev/waveterm/pkg/waveobj"
 	"github.com/wavetermdev/waveterm/pkg/wstore"
+	"golang.org/x/sync/singleflight"
+	"gopkg.in/yaml.v3"
 )
 
 const (
-	LearningsFile       = "termdash:learnings"
-	LearningsMaxContext = 4000 // max chars to send to Claude for extraction
+	LearningsFile           = "termdash:learnings"
+	LearningsStructuredFile = "termdash:learningsstructured"
+	LearningsMaxContext     = 4000 // max chars to send to Claude for extraction
+
+	// ExportLearningsFormatJSON and ExportLearningsFormatYAML are the formats
+	// ExportLearningsStructured accepts.
+	ExportLearningsFormatJSON = "json"
+	ExportLearningsFormatYAML = "yaml"
+
+	DefaultMinLearnings = 3
+	DefaultMaxLearnings = 7
+
+	// DefaultMinTranscriptLength is the minimum length, in characters of
+	// cleaned transcript text, below which ExtractLearnings refuses to run
+	// so trivial sessions don't waste a CLI call.
+	DefaultMinTranscriptLength = 100
+
+	// DefaultLearningsModel is the claude CLI model ExtractLearnings invokes
+	// by default.
+	DefaultLearningsModel = "haiku"
+
+	// DefaultLearningsPromptTemplate is the text/template rendered into the
+	// prompt sent to the model. It must reference {{.Transcript}}; {{.Min}}
+	// and {{.Max}} are also available.
+	DefaultLearningsPromptTemplate = "Analyze this Claude Code terminal session transcript and extract {{.Min}}-{{.Max}} concise, " +
+		"reusable engineering insights or patterns. Each insight should be a single sentence " +
+		"that would help a developer working on similar code in the future. " +
+		"Return ONLY the insights, one per line, no numbering, no bullet points.\n\n{{.Transcript}}"
 )
 
-// Learning represents a single extracted insight from a Claude session.
-type Learning struct {
-	Text      string `json:"text"`
-	Source    string `json:"source"`    // block ID where this was extracted from
-	Timestamp int64  `json:"timestamp"`
+// LearningsConfig configures ExtractLearnings' generation call: which model
+// to invoke, the prompt template rendered against the transcript, and how
+// many insights to target. Overriding ActiveLearningsConfig lets users tune
+// extraction quality/cost, e.g. a cheaper/faster model or a shorter prompt.
+type LearningsConfig struct {
+	Model               string
+	PromptTemplate      string // text/template; must reference {{.Transcript}}
+	MinLearnings        int
+	MaxLearnings        int
+	MinTranscriptLength int // minimum cleaned transcript length required to attempt extraction
 }
 
-// ExtractLearnings analyzes a Claude session's transcript and extracts reusable
-// engineering insights using Claude Haiku.
-func (s *TermDashService) ExtractLearnings(ctx context.Context, blockId string) ([]string, error) {
-	// Read transcript
-	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+// DefaultLearningsConfig holds ExtractLearnings' original hardcoded model,
+// prompt, and insight-count values.
+var DefaultLearningsConfig = LearningsConfig{
+	Model:               DefaultLearningsModel,
+	PromptTemplate:      DefaultLearningsPromptTemplate,
+	MinLearnings:        DefaultMinLearnings,
+	MaxLearnings:        DefaultMaxLearnings,
+	MinTranscriptLength: DefaultMinTranscriptLength,
+}
+
+// ActiveLearningsConfig is the configuration ExtractLearnings uses.
+// Defaults to DefaultLearningsConfig.
+var ActiveLearningsConfig = DefaultLearningsConfig
+
+// learningsPromptData is rendered into a LearningsConfig's PromptTemplate.
+type learningsPromptData struct {
+	Transcript string
+	Min        int
+	Max        int
+}
+
+// renderLearningsPrompt parses and executes cfg.PromptTemplate against
+// transcript, returning an error if the template is invalid or doesn't
+// actually reference the transcript, so a misconfigured template fails
+// loudly instead of silently sending the model an empty or static prompt.
+func renderLearningsPrompt(cfg LearningsConfig, transcript string) (string, error) {
+	tmpl, err := template.New("learnings-prompt").Parse(cfg.PromptTemplate)
 	if err != nil {
-		return nil, fmt.Errorf("error reading transcript: %w", err)
+		return "", fmt.Errorf("invalid learnings prompt template: %w", err)
 	}
+	var buf strings.Builder
+	data := learningsPromptData{Transcript: transcript, Min: cfg.MinLearnings, Max: cfg.MaxLearnings}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering learnings prompt template: %w", err)
+	}
+	rendered := buf.String()
+	if !strings.Contains(rendered, transcript) {
+		return "", fmt.Errorf("learnings prompt template must reference {{.Transcript}}")
+	}
+	return rendered, nil
+}
 
-	transcript := string(data)
-	if len(transcript) < 100 {
-		return nil, fmt.Errorf("transcript too short to extract learnings")
+// learningsGeneratorFn invokes model on prompt and returns its raw stdout.
+// A package variable so tests can substitute a stub without invoking the
+// real claude CLI, mirroring persistStatusFn in statusattachservice.go.
+var learningsGeneratorFn = runLearningsGenerator
+
+func runLearningsGenerator(ctx context.Context, model, prompt string) (string, error) {
+	cmd := exec.CommandContext(ctx, "claude", "-p", "--model", model)
+	cmd.Stdin = strings.NewReader(prompt)
+
+	output, err := cmd.Output()
+	recordCLIOutcome(err)
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", fmt.Errorf("%w: %v", ErrGeneratorUnavailable, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("claude command error: %w", err)
 	}
+	return string(output), nil
+}
 
-	// Truncate for prompt
+// extractLearningsFromTranscript checks transcript against cfg's configured
+// minimum length, truncates it to LearningsMaxContext, renders cfg's prompt
+// template, invokes the configured generator, and parses/trims the result
+// into a learnings list. Split from ExtractLearnings so the guard and
+// generation path is testable without a filestore-backed block.
+func extractLearningsFromTranscript(ctx context.Context, cfg LearningsConfig, transcript string) ([]string, error) {
+	if len(transcript) < cfg.MinTranscriptLength {
+		return nil, fmt.Errorf("transcript too short to extract learnings")
+	}
 	if len(transcript) > LearningsMaxContext {
 		transcript = transcript[len(transcript)-LearningsMaxContext:]
 	}
 
-	prompt := fmt.Sprintf(
-		"Analyze this Claude Code terminal session transcript and extract 3-7 concise, "+
-			"reusable engineering insights or patterns. Each insight should be a single sentence "+
-			"that would help a developer working on similar code in the future. "+
-			"Return ONLY the insights, one per line, no numbering, no bullet points.\n\n%s",
-		transcript,
-	)
+	prompt, err := renderLearningsPrompt(cfg, transcript)
+	if err != nil {
+		return nil, err
+	}
 
 	execCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(execCtx, "claude", "-p", "--model", "haiku")
-	cmd.Stdin = strings.NewReader(prompt)
-
-	output, err := cmd.Output()
+	output, err := learningsGeneratorFn(execCtx, cfg.Model, prompt)
 	if err != nil {
-		return nil, fmt.Errorf("claude command error: %w", err)
+		return nil, err
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
 	var learnings []string
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -74,6 +162,65 @@ func (s *TermDashService) ExtractLearnings(ctx context.Context, blockId string)
 			learnings = append(learnings, line)
 		}
 	}
+	trimmed := trimLearnings(learnings, cfg.MaxLearnings)
+	recordLearningsExtracted(len(trimmed))
+	return trimmed, nil
+}
+
+// Learning represents a single extracted insight from a Claude session.
+type Learning struct {
+	Text          string `json:"text" yaml:"text"`
+	Category      string `json:"category,omitempty" yaml:"category,omitempty"`
+	Source        string `json:"source" yaml:"source"` // block ID where this was extracted from
+	SourceSummary string `json:"sourcesummary,omitempty" yaml:"sourcesummary,omitempty"`
+	Timestamp     int64  `json:"timestamp" yaml:"timestamp"`
+}
+
+// extractLearningsGroup coalesces concurrent ExtractLearnings calls for the
+// same blockId into a single extraction, so an exit hook and a manual
+// trigger racing on the same session don't both invoke the claude CLI and
+// both write the learnings file. Every coalesced caller receives the one
+// extraction's result.
+var extractLearningsGroup singleflight.Group
+
+// extractLearningsBlockFn performs the work behind ExtractLearnings, called
+// at most once per in-flight blockId. A package variable so tests can
+// substitute a fake and verify singleflight coalescing without a
+// filestore/DB-backed block, mirroring persistStatusFn in
+// statusattachservice.go.
+var extractLearningsBlockFn = defaultExtractLearningsForBlock
+
+// ExtractLearnings analyzes a Claude session's transcript and extracts
+// reusable engineering insights using Claude Haiku. Concurrent calls for the
+// same blockId coalesce into a single extraction.
+func (s *TermDashService) ExtractLearnings(ctx context.Context, blockId string) ([]string, error) {
+	v, err, _ := extractLearningsGroup.Do(blockId, func() (interface{}, error) {
+		return extractLearningsBlockFn(ctx, blockId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+// defaultExtractLearningsForBlock is extractLearningsBlockFn's real
+// implementation.
+func defaultExtractLearningsForBlock(ctx context.Context, blockId string) ([]string, error) {
+	// Read transcript
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+
+	// Use the same cleaned, input+output text other consumers operate on
+	// (rather than raw JSONL bytes) so the minimum-length guard reflects
+	// meaningful content, not incidental JSON framing.
+	transcript := reconstructTranscriptText(data)
+
+	learnings, err := extractLearningsFromTranscript(ctx, ActiveLearningsConfig, transcript)
+	if err != nil {
+		return nil, err
+	}
 
 	// Store learnings in the block's file store
 	if len(learnings) > 0 {
@@ -84,16 +231,63 @@ func (s *TermDashService) ExtractLearnings(ctx context.Context, blockId string)
 		if err != nil {
 			log.Printf("[termdash:learnings] error storing learnings for block %s: %v\n", blockId, err)
 		}
+
+		category := detectLanguage(transcript)
+		sourceSummary := ""
+		if block, blockErr := wstore.DBMustGet[*waveobj.Block](ctx, blockId); blockErr == nil {
+			sourceSummary = block.Meta.GetString(waveobj.MetaKey_TermDashSummary, "")
+		}
+		recordStructuredLearnings(storeCtx, blockId, learnings, category, sourceSummary, time.Now().UnixMilli())
 	}
 
 	return learnings, nil
 }
 
+// recordStructuredLearnings appends newly extracted learnings, tagged with
+// category, sourceSummary, and now, to blockId's structured learnings store
+// (LearningsStructuredFile), which ExportLearningsStructured reads from.
+// This is separate from the plain-text LearningsFile so existing readers of
+// that flat format are unaffected.
+func recordStructuredLearnings(ctx context.Context, blockId string, learnings []string, category, sourceSummary string, now int64) {
+	var buf bytes.Buffer
+	if _, existing, err := filestore.WFS.ReadFile(ctx, blockId, LearningsStructuredFile); err == nil {
+		buf.Write(existing)
+	}
+	for _, text := range learnings {
+		jsonLine, err := json.Marshal(Learning{
+			Text:          text,
+			Category:      category,
+			Source:        blockId,
+			SourceSummary: sourceSummary,
+			Timestamp:     now,
+		})
+		if err != nil {
+			continue
+		}
+		buf.Write(jsonLine)
+		buf.WriteByte('\n')
+	}
+
+	if err := filestore.WFS.WriteFile(ctx, blockId, LearningsStructuredFile, buf.Bytes()); err != nil {
+		log.Printf("[termdash:learnings] error storing structured learnings for block %s: %v\n", blockId, err)
+	}
+}
+
+// trimLearnings caps learnings at maxCount, in case the model over-produces
+// beyond what the prompt asked for. A non-positive maxCount disables the
+// cap.
+func trimLearnings(learnings []string, maxCount int) []string {
+	if maxCount > 0 && len(learnings) > maxCount {
+		return learnings[:maxCount]
+	}
+	return learnings
+}
+
 // GetLearnings retrieves previously extracted learnings for a block.
 func (s *TermDashService) GetLearnings(ctx context.Context, blockId string) ([]string, error) {
 	_, data, err := filestore.WFS.ReadFile(ctx, blockId, LearningsFile)
 	if err != nil {
-		return nil, fmt.Errorf("no learnings found: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrNoLearnings, err)
 	}
 
 	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
@@ -107,6 +301,83 @@ func (s *TermDashService) GetLearnings(ctx context.Context, blockId string) ([]s
 	return learnings, nil
 }
 
+// languageExtensions maps file extensions to their language name, checked
+// in order so the first (most specific) match wins ties in detectLanguage.
+var languageExtensions = []struct {
+	ext  string
+	name string
+}{
+	{".go", "Go"},
+	{".tsx", "TypeScript"},
+	{".ts", "TypeScript"},
+	{".jsx", "JavaScript"},
+	{".js", "JavaScript"},
+	{".py", "Python"},
+	{".rs", "Rust"},
+	{".java", "Java"},
+	{".rb", "Ruby"},
+	{".cpp", "C++"},
+	{".c", "C"},
+	{".sh", "Shell"},
+}
+
+// detectLanguage guesses the primary language a session touched by counting
+// file-extension mentions in its transcript text and returning the most
+// frequent match. Returns "unknown" when no known extension appears.
+func detectLanguage(transcriptText string) string {
+	best := "unknown"
+	bestCount := 0
+	for _, le := range languageExtensions {
+		count := strings.Count(transcriptText, le.ext)
+		if count > bestCount {
+			bestCount = count
+			best = le.name
+		}
+	}
+	return best
+}
+
+// ExportLearningsByLanguage groups every recorded learning by the language
+// detected in its source session's transcript, so a user can browse "Go
+// insights" separately from "Python insights." Sessions with no detected
+// language are grouped under "unknown".
+func (s *TermDashService) ExportLearningsByLanguage(ctx context.Context) (map[string][]string, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	grouped := make(map[string][]string)
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+
+		_, learningsData, err := filestore.WFS.ReadFile(ctx, block.OID, LearningsFile)
+		if err != nil {
+			continue
+		}
+		var learnings []string
+		for _, line := range strings.Split(strings.TrimSpace(string(learningsData)), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" {
+				learnings = append(learnings, line)
+			}
+		}
+		if len(learnings) == 0 {
+			continue
+		}
+
+		language := "unknown"
+		if _, transcriptData, err := filestore.WFS.ReadFile(ctx, block.OID, "termdash:transcript"); err == nil {
+			language = detectLanguage(reconstructTranscriptText(transcriptData))
+		}
+
+		grouped[language] = append(grouped[language], learnings...)
+	}
+	return grouped, nil
+}
+
 // GetAllLearnings retrieves learnings from all Claude sessions.
 func (s *TermDashService) GetAllLearnings(ctx context.Context) ([]string, error) {
 	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
@@ -147,26 +418,222 @@ func (s *TermDashService) GetAllLearnings(ctx context.Context) ([]string, error)
 	return unique, nil
 }
 
+// collectLearnings gathers every recorded Learning, tagged with the block
+// it was extracted from, across Claude sessions matching scope.
+func (s *TermDashService) collectLearnings(ctx context.Context, scope SearchScope) ([]Learning, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	var all []Learning
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		if !matchesScope(block.Meta.GetBool(waveobj.MetaKey_TermDashArchived, false), scope) {
+			continue
+		}
+		_, data, err := filestore.WFS.ReadFile(ctx, block.OID, LearningsFile)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			all = append(all, Learning{Text: line, Source: block.OID})
+		}
+	}
+	return all, nil
+}
+
+// collectStructuredLearnings gathers every recorded Learning from the
+// structured per-session store (LearningsStructuredFile) across all Claude
+// sessions. Unlike collectLearnings, only sessions extracted since
+// recordStructuredLearnings was introduced contribute entries here.
+func (s *TermDashService) collectStructuredLearnings(ctx context.Context) ([]Learning, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	var all []Learning
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		_, data, err := filestore.WFS.ReadFile(ctx, block.OID, LearningsStructuredFile)
+		if err != nil {
+			continue
+		}
+		all = append(all, parseStructuredLearnings(data)...)
+	}
+	return all, nil
+}
+
+// parseStructuredLearnings parses JSONL structured-learnings data (one
+// Learning per line) into a slice, skipping malformed lines.
+func parseStructuredLearnings(data []byte) []Learning {
+	var learnings []Learning
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var l Learning
+		if err := json.Unmarshal([]byte(line), &l); err != nil {
+			continue
+		}
+		learnings = append(learnings, l)
+	}
+	return learnings
+}
+
+// ExportLearningsStructured collects every recorded learning from the
+// structured per-session store and serializes it as JSON or YAML for
+// external tooling (indexing, dashboards), including fields the flat
+// GetAllLearnings output doesn't carry: category, source session ID, source
+// summary, and timestamp. format must be ExportLearningsFormatJSON or
+// ExportLearningsFormatYAML.
+func (s *TermDashService) ExportLearningsStructured(ctx context.Context, format string) ([]byte, error) {
+	learnings, err := s.collectStructuredLearnings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return marshalStructuredLearnings(learnings, format)
+}
+
+// marshalStructuredLearnings validates format and serializes learnings into
+// it. Split from ExportLearningsStructured so the serialization itself is
+// testable without a filestore/DB-backed set of blocks.
+func marshalStructuredLearnings(learnings []Learning, format string) ([]byte, error) {
+	switch format {
+	case ExportLearningsFormatJSON:
+		return json.MarshalIndent(learnings, "", "  ")
+	case ExportLearningsFormatYAML:
+		return yaml.Marshal(learnings)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q, want %q or %q", format, ExportLearningsFormatJSON, ExportLearningsFormatYAML)
+	}
+}
+
+// LearningSearchResult is one learning matching a SearchLearnings query.
+type LearningSearchResult struct {
+	BlockId string `json:"blockid"`
+	Text    string `json:"text"`
+}
+
+// SearchLearnings searches recorded learnings across Claude blocks matching
+// scope (SearchScopeAll searches every block regardless of archive state),
+// mirroring SearchTranscripts but over extracted insights rather than raw
+// transcript text.
+func (s *TermDashService) SearchLearnings(ctx context.Context, query string, scope SearchScope) ([]LearningSearchResult, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+
+	learnings, err := s.collectLearnings(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	lowerQuery := strings.ToLower(query)
+	var results []LearningSearchResult
+	for _, l := range learnings {
+		if strings.Contains(strings.ToLower(l.Text), lowerQuery) {
+			results = append(results, LearningSearchResult{BlockId: l.Source, Text: l.Text})
+		}
+	}
+	return results, nil
+}
+
+// RelevantLearnings scores every recorded learning against query using a
+// term-overlap heuristic and returns the top limit matches ranked by
+// descending relevance. This powers both BuildContextForNewSession and an
+// interactive "related insights" panel.
+func (s *TermDashService) RelevantLearnings(ctx context.Context, query string, limit int) ([]Learning, error) {
+	learnings, err := s.collectLearnings(ctx, SearchScopeAll)
+	if err != nil {
+		return nil, err
+	}
+
+	queryTerms := extractTerms(query)
+	if len(queryTerms) == 0 || len(learnings) == 0 {
+		return nil, nil
+	}
+
+	type scoredLearning struct {
+		learning Learning
+		score    float64
+	}
+	var scored []scoredLearning
+	for _, l := range learnings {
+		score := termOverlapScore(queryTerms, extractTerms(l.Text))
+		if score > 0 {
+			scored = append(scored, scoredLearning{l, score})
+		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	if limit > 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+
+	results := make([]Learning, len(scored))
+	for i, s := range scored {
+		results[i] = s.learning
+	}
+	return results, nil
+}
+
+// extractTerms tokenizes text into a set of lowercase, non-stopword terms.
+func extractTerms(text string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, word := range wordRegex.FindAllString(text, -1) {
+		lower := strings.ToLower(word)
+		if !stopWords[lower] {
+			terms[lower] = true
+		}
+	}
+	return terms
+}
+
+// termOverlapScore returns the fraction of queryTerms present in textTerms.
+func termOverlapScore(queryTerms, textTerms map[string]bool) float64 {
+	if len(queryTerms) == 0 {
+		return 0
+	}
+	matches := 0
+	for term := range queryTerms {
+		if textTerms[term] {
+			matches++
+		}
+	}
+	return float64(matches) / float64(len(queryTerms))
+}
+
 // BuildContextForNewSession builds a system prompt injection from relevant
 // learnings for a new Claude session. This is called when creating new
-// Claude blocks to inject prior engineering insights.
+// Claude blocks to inject prior engineering insights: up to 10 learnings
+// from all sessions, ranked by RelevantLearnings' term overlap with cwd.
+// Learnings are not filtered by the project or session they came from.
 func (s *TermDashService) BuildContextForNewSession(ctx context.Context, cwd string) (string, error) {
-	learnings, err := s.GetAllLearnings(ctx)
+	const maxLearnings = 10
+	learnings, err := s.RelevantLearnings(ctx, cwd, maxLearnings)
 	if err != nil || len(learnings) == 0 {
 		return "", nil
 	}
 
-	// Select most relevant learnings (for now, just take the last N)
-	maxLearnings := 10
-	if len(learnings) > maxLearnings {
-		learnings = learnings[len(learnings)-maxLearnings:]
-	}
-
 	var sb strings.Builder
 	sb.WriteString("Engineering insights from previous sessions:\n")
 	for _, l := range learnings {
 		sb.WriteString("- ")
-		sb.WriteString(l)
+		sb.WriteString(l.Text)
 		sb.WriteString("\n")
 	}
 
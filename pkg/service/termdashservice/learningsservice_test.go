@@ -0,0 +1,308 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestTermOverlapScoreRanksRelevantTextHigher(t *testing.T) {
+	query := extractTerms("database migration rollback")
+	relevant := extractTerms("Always test database migration rollback scripts before deploying")
+	unrelated := extractTerms("Prefer early returns over deeply nested conditionals")
+
+	relevantScore := termOverlapScore(query, relevant)
+	unrelatedScore := termOverlapScore(query, unrelated)
+
+	if relevantScore <= unrelatedScore {
+		t.Errorf("relevant score %v should be greater than unrelated score %v", relevantScore, unrelatedScore)
+	}
+	if relevantScore == 0 {
+		t.Errorf("expected a nonzero score for matching text")
+	}
+}
+
+func TestTermOverlapScoreEmptyQuery(t *testing.T) {
+	if got := termOverlapScore(extractTerms(""), extractTerms("some text")); got != 0 {
+		t.Errorf("termOverlapScore with empty query = %v, want 0", got)
+	}
+}
+
+func TestTrimLearningsRespectsConfiguredMax(t *testing.T) {
+	// Simulate a stub generator that over-produces beyond the requested range.
+	stubbed := []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+
+	got := trimLearnings(stubbed, 3)
+	if len(got) != 3 {
+		t.Fatalf("trimLearnings() returned %d entries, want 3", len(got))
+	}
+	if got[0] != "one" || got[2] != "three" {
+		t.Errorf("trimLearnings() = %v, want the first 3 entries preserved in order", got)
+	}
+}
+
+func TestTrimLearningsNoCapWhenUnderLimit(t *testing.T) {
+	learnings := []string{"one", "two"}
+	got := trimLearnings(learnings, 7)
+	if len(got) != 2 {
+		t.Errorf("trimLearnings() returned %d entries, want 2 (unchanged)", len(got))
+	}
+}
+
+func TestExtractLearningsFromTranscriptUsesConfiguredModelAndTemplate(t *testing.T) {
+	origGenerator := learningsGeneratorFn
+	defer func() { learningsGeneratorFn = origGenerator }()
+
+	var gotModel, gotPrompt string
+	learningsGeneratorFn = func(ctx context.Context, model, prompt string) (string, error) {
+		gotModel = model
+		gotPrompt = prompt
+		return "insight one\ninsight two", nil
+	}
+
+	cfg := LearningsConfig{
+		Model:          "opus",
+		PromptTemplate: "Extract {{.Min}} to {{.Max}} insights from:\n{{.Transcript}}",
+		MinLearnings:   2,
+		MaxLearnings:   4,
+	}
+
+	got, err := extractLearningsFromTranscript(t.Context(), cfg, "ran the test suite and fixed a flaky test")
+	if err != nil {
+		t.Fatalf("extractLearningsFromTranscript() error = %v", err)
+	}
+
+	if gotModel != "opus" {
+		t.Errorf("generator received model %q, want %q", gotModel, "opus")
+	}
+	wantPrompt := "Extract 2 to 4 insights from:\nran the test suite and fixed a flaky test"
+	if gotPrompt != wantPrompt {
+		t.Errorf("generator received prompt %q, want %q", gotPrompt, wantPrompt)
+	}
+	if len(got) != 2 || got[0] != "insight one" || got[1] != "insight two" {
+		t.Errorf("extractLearningsFromTranscript() = %v, want [insight one insight two]", got)
+	}
+}
+
+func TestExtractLearningsFromTranscriptRefusesBelowConfiguredMinimum(t *testing.T) {
+	origGenerator := learningsGeneratorFn
+	defer func() { learningsGeneratorFn = origGenerator }()
+
+	called := false
+	learningsGeneratorFn = func(ctx context.Context, model, prompt string) (string, error) {
+		called = true
+		return "insight", nil
+	}
+
+	cfg := LearningsConfig{
+		Model:               "haiku",
+		PromptTemplate:      DefaultLearningsPromptTemplate,
+		MinLearnings:        3,
+		MaxLearnings:        7,
+		MinTranscriptLength: 50,
+	}
+
+	if _, err := extractLearningsFromTranscript(t.Context(), cfg, "too short"); err == nil {
+		t.Fatal("extractLearningsFromTranscript() with a transcript below MinTranscriptLength = nil error, want an error")
+	}
+	if called {
+		t.Error("extractLearningsFromTranscript() invoked the generator despite the transcript being too short")
+	}
+}
+
+func TestExtractLearningsFromTranscriptProceedsAboveConfiguredMinimum(t *testing.T) {
+	origGenerator := learningsGeneratorFn
+	defer func() { learningsGeneratorFn = origGenerator }()
+
+	learningsGeneratorFn = func(ctx context.Context, model, prompt string) (string, error) {
+		return "insight one", nil
+	}
+
+	cfg := LearningsConfig{
+		Model:               "haiku",
+		PromptTemplate:      DefaultLearningsPromptTemplate,
+		MinLearnings:        3,
+		MaxLearnings:        7,
+		MinTranscriptLength: 10,
+	}
+
+	got, err := extractLearningsFromTranscript(t.Context(), cfg, "a transcript long enough to pass the minimum")
+	if err != nil {
+		t.Fatalf("extractLearningsFromTranscript() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "insight one" {
+		t.Errorf("extractLearningsFromTranscript() = %v, want [insight one]", got)
+	}
+}
+
+func TestRenderLearningsPromptRejectsTemplateMissingTranscript(t *testing.T) {
+	cfg := LearningsConfig{
+		Model:          "haiku",
+		PromptTemplate: "Extract {{.Min}}-{{.Max}} insights, no transcript reference here",
+		MinLearnings:   3,
+		MaxLearnings:   7,
+	}
+
+	if _, err := renderLearningsPrompt(cfg, "some transcript text"); err == nil {
+		t.Fatal("renderLearningsPrompt() with a template missing {{.Transcript}} = nil error, want an error")
+	}
+}
+
+func TestRenderLearningsPromptRejectsInvalidTemplateSyntax(t *testing.T) {
+	cfg := LearningsConfig{
+		Model:          "haiku",
+		PromptTemplate: "{{.Transcript",
+		MinLearnings:   3,
+		MaxLearnings:   7,
+	}
+
+	if _, err := renderLearningsPrompt(cfg, "some transcript text"); err == nil {
+		t.Fatal("renderLearningsPrompt() with malformed template syntax = nil error, want an error")
+	}
+}
+
+func TestRenderLearningsPromptUsesDefaultTemplate(t *testing.T) {
+	prompt, err := renderLearningsPrompt(DefaultLearningsConfig, "sample transcript")
+	if err != nil {
+		t.Fatalf("renderLearningsPrompt() error = %v", err)
+	}
+	if !strings.Contains(prompt, "sample transcript") {
+		t.Errorf("rendered prompt = %q, want it to contain the transcript", prompt)
+	}
+}
+
+func TestDetectLanguagePicksMostFrequentExtension(t *testing.T) {
+	goSession := "Editing main.go and utils.go, ran go build ./..."
+	pythonSession := "Editing app.py and tests/test_app.py, ran pytest"
+
+	if got := detectLanguage(goSession); got != "Go" {
+		t.Errorf("detectLanguage(goSession) = %q, want %q", got, "Go")
+	}
+	if got := detectLanguage(pythonSession); got != "Python" {
+		t.Errorf("detectLanguage(pythonSession) = %q, want %q", got, "Python")
+	}
+}
+
+func TestDetectLanguageUnknownWhenNoExtensionMatches(t *testing.T) {
+	if got := detectLanguage("just some plain chat with no file references"); got != "unknown" {
+		t.Errorf("detectLanguage() = %q, want %q", got, "unknown")
+	}
+}
+
+func TestMarshalStructuredLearningsJSONContainsStructuredFields(t *testing.T) {
+	learnings := []Learning{
+		{Text: "prefer table-driven tests", Category: "Go", Source: "block-1", SourceSummary: "fix flaky test", Timestamp: 1700000000000},
+	}
+
+	data, err := marshalStructuredLearnings(learnings, ExportLearningsFormatJSON)
+	if err != nil {
+		t.Fatalf("marshalStructuredLearnings() error = %v", err)
+	}
+
+	var got []Learning
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal exported JSON: %v", err)
+	}
+	if len(got) != 1 || got[0] != learnings[0] {
+		t.Errorf("exported JSON = %+v, want %+v", got, learnings)
+	}
+
+	for _, field := range []string{`"text"`, `"category"`, `"source"`, `"sourcesummary"`, `"timestamp"`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("exported JSON missing field %s: %s", field, data)
+		}
+	}
+}
+
+func TestMarshalStructuredLearningsYAMLContainsStructuredFields(t *testing.T) {
+	learnings := []Learning{
+		{Text: "check for nil before dereferencing", Category: "Python", Source: "block-2", SourceSummary: "debug crash", Timestamp: 1700000001000},
+	}
+
+	data, err := marshalStructuredLearnings(learnings, ExportLearningsFormatYAML)
+	if err != nil {
+		t.Fatalf("marshalStructuredLearnings() error = %v", err)
+	}
+
+	var got []Learning
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal exported YAML: %v", err)
+	}
+	if len(got) != 1 || got[0] != learnings[0] {
+		t.Errorf("exported YAML = %+v, want %+v", got, learnings)
+	}
+}
+
+func TestMarshalStructuredLearningsRejectsUnknownFormat(t *testing.T) {
+	_, err := marshalStructuredLearnings(nil, "xml")
+	if err == nil {
+		t.Fatal("marshalStructuredLearnings(format=xml) = nil error, want a validation error")
+	}
+}
+
+func TestParseStructuredLearningsSkipsMalformedLines(t *testing.T) {
+	data := []byte(`{"text":"ok one","source":"block-1","timestamp":1000}` + "\n" +
+		"not json\n" +
+		`{"text":"ok two","source":"block-1","timestamp":2000}` + "\n")
+
+	got := parseStructuredLearnings(data)
+	if len(got) != 2 {
+		t.Fatalf("parseStructuredLearnings() returned %d entries, want 2: %+v", len(got), got)
+	}
+	if got[0].Text != "ok one" || got[1].Text != "ok two" {
+		t.Errorf("parseStructuredLearnings() = %+v, want texts %q and %q", got, "ok one", "ok two")
+	}
+}
+
+func TestExtractLearningsCoalescesConcurrentCallsForSameBlock(t *testing.T) {
+	origFn := extractLearningsBlockFn
+	defer func() { extractLearningsBlockFn = origFn }()
+
+	var calls int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	extractLearningsBlockFn = func(ctx context.Context, blockId string) ([]string, error) {
+		atomic.AddInt32(&calls, 1)
+		started <- struct{}{}
+		<-release
+		return []string{"reuse this pattern"}, nil
+	}
+
+	s := &TermDashService{}
+	var wg sync.WaitGroup
+	results := make([][]string, 2)
+	errs := make([]error, 2)
+	for i := range 2 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = s.ExtractLearnings(t.Context(), "shared-block")
+		}(i)
+	}
+
+	<-started // first call has entered the singleflight-guarded work
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("extractLearningsBlockFn invoked %d times, want 1", got)
+	}
+	for i := range results {
+		if errs[i] != nil {
+			t.Errorf("ExtractLearnings()[%d] error = %v", i, errs[i])
+		}
+		if len(results[i]) != 1 || results[i][0] != "reuse this pattern" {
+			t.Errorf("ExtractLearnings()[%d] = %v, want [reuse this pattern]", i, results[i])
+		}
+	}
+}
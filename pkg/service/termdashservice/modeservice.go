@@ -0,0 +1,45 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// GetClaudeMode returns the block's current plan/accept-edits mode, as last
+// reported by a mode footer in the session's output. It returns
+// termdash.ClaudeModeDefault if no non-default mode has been detected.
+func (s *TermDashService) GetClaudeMode(ctx context.Context, blockId string) (string, error) {
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return block.Meta.GetString(waveobj.MetaKey_TermDashClaudeMode, ""), nil
+}
+
+// GetSessionModel returns the block's currently active Claude model (e.g.
+// "opus", "sonnet", "haiku"), as last detected from the session's startup
+// banner or status line. Returns "" if no model has been detected yet.
+func (s *TermDashService) GetSessionModel(ctx context.Context, blockId string) (string, error) {
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return block.Meta.GetString(waveobj.MetaKey_TermDashClaudeModel, ""), nil
+}
+
+// GetSessionShell returns the block's detected shell (e.g. "bash", "zsh",
+// "fish"), as last detected from the session's early output. Returns "" if
+// no shell has been detected yet.
+func (s *TermDashService) GetSessionShell(ctx context.Context, blockId string) (string, error) {
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return block.Meta.GetString(waveobj.MetaKey_TermDashShell, ""), nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// GetOutputScript scans a Claude session's transcript for its most recent
+// output and returns the dominant right-to-left or CJK script detected in
+// it (termdash.ScriptRTL, termdash.ScriptCJK, or "" if neither dominates),
+// so the frontend can adjust text direction for that session.
+func (s *TermDashService) GetOutputScript(ctx context.Context, blockId string) (string, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	script, _ := termdash.DetectScript(reconstructTranscriptText(data))
+	return script, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// ProductivityWeights configures how RankSessionsByProductivity's score
+// weighs each signal. The score is:
+//
+//	Score = Learnings*LearningsCount + FilesTouched*FilesTouchedCount +
+//	        CommandsRun*CommandsRunCount + DurationPerMinute*(DurationMs/60000)
+//
+// Duration is normalized to minutes before weighing so it's comparable in
+// magnitude to the count-based signals; a deployment that considers long
+// sessions unproductive rather than productive can set DurationPerMinute
+// negative or to 0.
+type ProductivityWeights struct {
+	Learnings         float64
+	FilesTouched      float64
+	CommandsRun       float64
+	DurationPerMinute float64
+}
+
+// DefaultProductivityWeights weighs learnings extracted most heavily, since
+// they're the most direct evidence a session produced a lasting result,
+// followed by files touched and commands run, with raw duration contributing
+// only a small amount (a long session isn't necessarily a productive one).
+var DefaultProductivityWeights = ProductivityWeights{
+	Learnings:         10,
+	FilesTouched:      3,
+	CommandsRun:       2,
+	DurationPerMinute: 0.1,
+}
+
+// ActiveProductivityWeights is the configuration RankSessionsByProductivity
+// uses. Defaults to DefaultProductivityWeights.
+var ActiveProductivityWeights = DefaultProductivityWeights
+
+// ScoredSession is one session's productivity score alongside the raw
+// signals that produced it, so a UI can show why a session ranked where it
+// did rather than just a bare number.
+type ScoredSession struct {
+	BlockId           string  `json:"blockid"`
+	Score             float64 `json:"score"`
+	LearningsCount    int     `json:"learningscount"`
+	FilesTouchedCount int     `json:"filestouchedcount"`
+	CommandsRunCount  int     `json:"commandsruncount"`
+	DurationMs        int64   `json:"durationms"`
+}
+
+// sessionProductivityMetrics is the raw, already-gathered signals for one
+// session, before weights are applied.
+type sessionProductivityMetrics struct {
+	blockId           string
+	learningsCount    int
+	filesTouchedCount int
+	commandsRunCount  int
+	durationMs        int64
+}
+
+// scoreSession applies weights to metrics, producing the ScoredSession
+// RankSessionsByProductivity ranks by. Split out so the scoring formula is
+// testable without a DB/filestore-backed set of blocks.
+func scoreSession(metrics sessionProductivityMetrics, weights ProductivityWeights) ScoredSession {
+	durationMinutes := float64(metrics.durationMs) / float64(time.Minute/time.Millisecond)
+	score := weights.Learnings*float64(metrics.learningsCount) +
+		weights.FilesTouched*float64(metrics.filesTouchedCount) +
+		weights.CommandsRun*float64(metrics.commandsRunCount) +
+		weights.DurationPerMinute*durationMinutes
+
+	return ScoredSession{
+		BlockId:           metrics.blockId,
+		Score:             score,
+		LearningsCount:    metrics.learningsCount,
+		FilesTouchedCount: metrics.filesTouchedCount,
+		CommandsRunCount:  metrics.commandsRunCount,
+		DurationMs:        metrics.durationMs,
+	}
+}
+
+// rankSessions scores every session in metrics with weights and returns the
+// top limit, highest score first. limit <= 0 means no limit.
+func rankSessions(metrics []sessionProductivityMetrics, weights ProductivityWeights, limit int) []ScoredSession {
+	scored := make([]ScoredSession, len(metrics))
+	for i, m := range metrics {
+		scored[i] = scoreSession(m, weights)
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+	if limit > 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+	return scored
+}
+
+// RankSessionsByProductivity scores every Claude session by a combination of
+// learnings extracted, files touched, commands run, and session duration
+// (see ProductivityWeights for the formula), returning the top limit
+// sessions highest score first, for self-review of which sessions produced
+// the most value. limit <= 0 returns every session.
+func (s *TermDashService) RankSessionsByProductivity(ctx context.Context, limit int) ([]ScoredSession, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	var metrics []sessionProductivityMetrics
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		metrics = append(metrics, s.gatherProductivityMetrics(ctx, block.OID))
+	}
+
+	return rankSessions(metrics, ActiveProductivityWeights, limit), nil
+}
+
+// gatherProductivityMetrics reads one session's learnings, touched files,
+// commands run, and status-timeline duration, leaving fields at their zero
+// value when a given piece isn't available.
+func (s *TermDashService) gatherProductivityMetrics(ctx context.Context, blockId string) sessionProductivityMetrics {
+	metrics := sessionProductivityMetrics{blockId: blockId}
+
+	if learnings, err := s.GetLearnings(ctx, blockId); err == nil {
+		metrics.learningsCount = len(learnings)
+	}
+
+	if timeline, err := s.GetStatusTimeline(ctx, blockId); err == nil {
+		_, total := statusBreakdown(timeline)
+		metrics.durationMs = total
+	}
+
+	if _, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript"); err == nil {
+		text := reconstructTranscriptText(data)
+		metrics.filesTouchedCount = len(termdash.ExtractTouchedFiles(text))
+		metrics.commandsRunCount = len(termdash.DetectCommandResults(text))
+	}
+
+	return metrics
+}
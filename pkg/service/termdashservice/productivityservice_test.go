@@ -0,0 +1,63 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+)
+
+func TestRankSessionsByProductivityRanksHigherActivityFirst(t *testing.T) {
+	metrics := []sessionProductivityMetrics{
+		{blockId: "idle", learningsCount: 0, filesTouchedCount: 0, commandsRunCount: 1, durationMs: 60_000},
+		{blockId: "productive", learningsCount: 3, filesTouchedCount: 5, commandsRunCount: 10, durationMs: 600_000},
+		{blockId: "middling", learningsCount: 1, filesTouchedCount: 1, commandsRunCount: 2, durationMs: 120_000},
+	}
+
+	ranked := rankSessions(metrics, DefaultProductivityWeights, 0)
+	if len(ranked) != 3 {
+		t.Fatalf("expected 3 ranked sessions, got %d: %+v", len(ranked), ranked)
+	}
+	wantOrder := []string{"productive", "middling", "idle"}
+	for i, want := range wantOrder {
+		if ranked[i].BlockId != want {
+			t.Errorf("ranked[%d].BlockId = %q, want %q (order: %+v)", i, ranked[i].BlockId, want, ranked)
+		}
+	}
+	if ranked[0].Score <= ranked[1].Score || ranked[1].Score <= ranked[2].Score {
+		t.Errorf("expected strictly descending scores, got %+v", ranked)
+	}
+}
+
+func TestRankSessionsByProductivityRespectsLimit(t *testing.T) {
+	metrics := []sessionProductivityMetrics{
+		{blockId: "a", learningsCount: 3},
+		{blockId: "b", learningsCount: 2},
+		{blockId: "c", learningsCount: 1},
+	}
+
+	ranked := rankSessions(metrics, DefaultProductivityWeights, 2)
+	if len(ranked) != 2 {
+		t.Fatalf("expected 2 ranked sessions, got %d: %+v", len(ranked), ranked)
+	}
+	if ranked[0].BlockId != "a" || ranked[1].BlockId != "b" {
+		t.Errorf("ranked = %+v, want top 2 by learnings", ranked)
+	}
+}
+
+func TestScoreSessionAppliesWeightsToEachSignal(t *testing.T) {
+	weights := ProductivityWeights{Learnings: 1, FilesTouched: 1, CommandsRun: 1, DurationPerMinute: 1}
+	metrics := sessionProductivityMetrics{
+		blockId:           "x",
+		learningsCount:    2,
+		filesTouchedCount: 3,
+		commandsRunCount:  4,
+		durationMs:        120_000, // 2 minutes
+	}
+
+	got := scoreSession(metrics, weights)
+	want := 2.0 + 3.0 + 4.0 + 2.0
+	if got.Score != want {
+		t.Errorf("scoreSession().Score = %v, want %v", got.Score, want)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// QuietHours configures a local time-of-day window during which attention
+// notifications (needs-input, rate-limited) are held back rather than
+// delivered immediately.
+type QuietHours struct {
+	Enabled bool
+	// StartHour and EndHour are local-time hours in [0, 23]. A window that
+	// wraps past midnight (StartHour > EndHour, e.g. 22 to 7) is supported.
+	StartHour int
+	EndHour   int
+	// Digest, when true, delivers every notification suppressed during the
+	// window as a single AttentionNotice batch once the window ends,
+	// instead of replaying them one at a time.
+	Digest bool
+}
+
+// AttentionNotice is one attention-worthy status change that was held back
+// by an active quiet-hours window.
+type AttentionNotice struct {
+	BlockId   string
+	OldStatus string
+	NewStatus string
+	At        time.Time
+}
+
+var (
+	quietHoursMu      sync.Mutex
+	quietHoursCfg     QuietHours
+	quietHoursQueue   []AttentionNotice
+	attentionDigestMu sync.Mutex
+	attentionDigest   []func([]AttentionNotice)
+)
+
+// nowFn returns the current time. A package variable so tests can fake
+// quiet-hours boundaries without waiting on the clock.
+var nowFn = time.Now
+
+// SetQuietHours installs the active quiet-hours configuration used by
+// fireStatusChanged to decide whether an attention notification should be
+// queued instead of delivered. Passing QuietHours{} disables suppression.
+func SetQuietHours(cfg QuietHours) {
+	quietHoursMu.Lock()
+	defer quietHoursMu.Unlock()
+	quietHoursCfg = cfg
+}
+
+// OnAttentionDigest registers fn to be called with every AttentionNotice
+// queued during a quiet-hours window, once that window ends and cfg.Digest
+// is true. Multiple registrations are all invoked, in registration order.
+func OnAttentionDigest(fn func([]AttentionNotice)) {
+	attentionDigestMu.Lock()
+	defer attentionDigestMu.Unlock()
+	attentionDigest = append(attentionDigest, fn)
+}
+
+// isAttentionStatus reports whether status is one worth suppressing during
+// quiet hours -- the same "worth a notification" statuses the rest of the
+// package's idle/prompt detection already treats as attention-worthy.
+func isAttentionStatus(status string) bool {
+	return status == termdash.StatusNeedsInput || status == termdash.StatusRateLimited
+}
+
+// inQuietHours reports whether t's local hour falls within cfg's window.
+func inQuietHours(cfg QuietHours, t time.Time) bool {
+	if !cfg.Enabled {
+		return false
+	}
+	hour := t.Local().Hour()
+	if cfg.StartHour == cfg.EndHour {
+		return true
+	}
+	if cfg.StartHour < cfg.EndHour {
+		return hour >= cfg.StartHour && hour < cfg.EndHour
+	}
+	return hour >= cfg.StartHour || hour < cfg.EndHour
+}
+
+// FlushQuietHoursQueue delivers any notices queued during a quiet-hours
+// window, if that window has since ended. A caller can invoke this
+// directly when quiet hours end (e.g. from a scheduler); fireStatusChanged
+// also calls it before every status change so a queue never lingers past
+// its window solely because no external flush was triggered.
+func FlushQuietHoursQueue() {
+	quietHoursMu.Lock()
+	cfg := quietHoursCfg
+	if len(quietHoursQueue) == 0 || inQuietHours(cfg, nowFn()) {
+		quietHoursMu.Unlock()
+		return
+	}
+	notices := quietHoursQueue
+	quietHoursQueue = nil
+	quietHoursMu.Unlock()
+
+	if cfg.Digest {
+		attentionDigestMu.Lock()
+		hooks := make([]func([]AttentionNotice), len(attentionDigest))
+		copy(hooks, attentionDigest)
+		attentionDigestMu.Unlock()
+		for _, fn := range hooks {
+			fn(notices)
+		}
+		return
+	}
+	for _, n := range notices {
+		deliverStatusChanged(n.BlockId, n.OldStatus, n.NewStatus)
+	}
+}
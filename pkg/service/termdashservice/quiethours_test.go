@@ -0,0 +1,141 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func resetQuietHoursState(t *testing.T) {
+	t.Helper()
+	origNow := nowFn
+	quietHoursMu.Lock()
+	quietHoursCfg = QuietHours{}
+	quietHoursQueue = nil
+	quietHoursMu.Unlock()
+	attentionDigestMu.Lock()
+	attentionDigest = nil
+	attentionDigestMu.Unlock()
+	statusChangedMu.Lock()
+	statusChangedHooks = nil
+	statusChangedMu.Unlock()
+	t.Cleanup(func() {
+		nowFn = origNow
+		quietHoursMu.Lock()
+		quietHoursCfg = QuietHours{}
+		quietHoursQueue = nil
+		quietHoursMu.Unlock()
+		attentionDigestMu.Lock()
+		attentionDigest = nil
+		attentionDigestMu.Unlock()
+		statusChangedMu.Lock()
+		statusChangedHooks = nil
+		statusChangedMu.Unlock()
+	})
+}
+
+func TestInQuietHoursWithinSameDayWindow(t *testing.T) {
+	cfg := QuietHours{Enabled: true, StartHour: 9, EndHour: 17}
+	at := func(hour int) time.Time { return time.Date(2026, 1, 1, hour, 0, 0, 0, time.Local) }
+	if !inQuietHours(cfg, at(12)) {
+		t.Error("expected 12:00 to be within 09:00-17:00 quiet hours")
+	}
+	if inQuietHours(cfg, at(8)) || inQuietHours(cfg, at(17)) {
+		t.Error("expected 08:00 and 17:00 to fall outside 09:00-17:00 quiet hours")
+	}
+}
+
+func TestInQuietHoursWrappingPastMidnight(t *testing.T) {
+	cfg := QuietHours{Enabled: true, StartHour: 22, EndHour: 7}
+	at := func(hour int) time.Time { return time.Date(2026, 1, 1, hour, 0, 0, 0, time.Local) }
+	if !inQuietHours(cfg, at(23)) || !inQuietHours(cfg, at(3)) {
+		t.Error("expected 23:00 and 03:00 to be within a 22:00-07:00 wrapping window")
+	}
+	if inQuietHours(cfg, at(12)) {
+		t.Error("expected 12:00 to fall outside a 22:00-07:00 wrapping window")
+	}
+}
+
+func TestInQuietHoursDisabledAlwaysFalse(t *testing.T) {
+	cfg := QuietHours{Enabled: false, StartHour: 0, EndHour: 23}
+	if inQuietHours(cfg, time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)) {
+		t.Error("expected disabled quiet hours to never suppress")
+	}
+}
+
+func TestFireStatusChangedSuppressesAttentionDuringQuietHoursAndSurfacesAfterward(t *testing.T) {
+	resetQuietHoursState(t)
+
+	SetQuietHours(QuietHours{Enabled: true, StartHour: 22, EndHour: 7})
+
+	var got []string
+	OnStatusChanged(func(blockId, oldStatus, newStatus string) {
+		got = append(got, blockId+":"+oldStatus+"->"+newStatus)
+	})
+
+	nowFn = func() time.Time { return time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local) }
+	fireStatusChanged("block-1", termdash.StatusActive, termdash.StatusNeedsInput)
+
+	if len(got) != 0 {
+		t.Fatalf("hook fired during quiet hours: got %v, want none", got)
+	}
+
+	nowFn = func() time.Time { return time.Date(2026, 1, 2, 8, 0, 0, 0, time.Local) }
+	FlushQuietHoursQueue()
+
+	want := []string{"block-1:active->needs-input"}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("hook after quiet hours = %v, want %v", got, want)
+	}
+}
+
+func TestFireStatusChangedNonAttentionStatusIgnoresQuietHours(t *testing.T) {
+	resetQuietHoursState(t)
+
+	SetQuietHours(QuietHours{Enabled: true, StartHour: 0, EndHour: 23})
+	nowFn = func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local) }
+
+	var got string
+	OnStatusChanged(func(blockId, oldStatus, newStatus string) {
+		got = newStatus
+	})
+
+	fireStatusChanged("block-1", termdash.StatusNeedsInput, termdash.StatusActive)
+
+	if got != termdash.StatusActive {
+		t.Errorf("hook did not fire for non-attention status during quiet hours: got %q", got)
+	}
+}
+
+func TestFireStatusChangedDeliversDigestWhenConfigured(t *testing.T) {
+	resetQuietHoursState(t)
+
+	SetQuietHours(QuietHours{Enabled: true, StartHour: 22, EndHour: 7, Digest: true})
+
+	var digest []AttentionNotice
+	OnAttentionDigest(func(notices []AttentionNotice) {
+		digest = notices
+	})
+	var individual []string
+	OnStatusChanged(func(blockId, oldStatus, newStatus string) {
+		individual = append(individual, blockId)
+	})
+
+	nowFn = func() time.Time { return time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local) }
+	fireStatusChanged("block-1", termdash.StatusActive, termdash.StatusNeedsInput)
+	fireStatusChanged("block-2", termdash.StatusActive, termdash.StatusRateLimited)
+
+	nowFn = func() time.Time { return time.Date(2026, 1, 2, 8, 0, 0, 0, time.Local) }
+	FlushQuietHoursQueue()
+
+	if len(individual) != 0 {
+		t.Errorf("expected no individual delivery when Digest is set, got %v", individual)
+	}
+	if len(digest) != 2 || digest[0].BlockId != "block-1" || digest[1].BlockId != "block-2" {
+		t.Errorf("digest = %+v, want notices for block-1 then block-2", digest)
+	}
+}
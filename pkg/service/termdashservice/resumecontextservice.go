@@ -0,0 +1,180 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// ResumeContextTailChars is how much of the end of a transcript
+// GenerateResumeContext feeds to the generator when building a recap.
+const ResumeContextTailChars = 3000
+
+// MaxResumeActionItems caps how many pending action items
+// extractPendingActionItems returns, so a session with many checkboxes
+// doesn't blow out the recap prompt.
+const MaxResumeActionItems = 10
+
+var (
+	actionItemCheckboxRegex = regexp.MustCompile(`(?im)^\s*[-*]\s*\[ \]\s*(.+)$`)
+	actionItemTodoRegex     = regexp.MustCompile(`(?im)^.*\bTODO\b:?\s*(.+)$`)
+)
+
+// extractPendingActionItems scans transcript text for unchecked markdown
+// checkboxes ("- [ ] ..." or "* [ ] ...") and TODO-prefixed lines,
+// returning up to MaxResumeActionItems items in the order they appear,
+// deduplicated.
+func extractPendingActionItems(transcript string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	addItem := func(item string) bool {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			return false
+		}
+		seen[item] = true
+		items = append(items, item)
+		return len(items) >= MaxResumeActionItems
+	}
+
+	for _, m := range actionItemCheckboxRegex.FindAllStringSubmatch(transcript, -1) {
+		if addItem(m[1]) {
+			return items
+		}
+	}
+	for _, m := range actionItemTodoRegex.FindAllStringSubmatch(transcript, -1) {
+		if addItem(m[1]) {
+			return items
+		}
+	}
+	return items
+}
+
+// transcriptTail returns the last n characters of text, so a recap prompt
+// covers recent context without growing unbounded on long sessions.
+func transcriptTail(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	return text[len(text)-n:]
+}
+
+// buildResumeContextPrompt composes the prompt sent to the generator from a
+// transcript tail and its extracted pending action items.
+func buildResumeContextPrompt(tail string, items []string) string {
+	var sb strings.Builder
+	sb.WriteString("Below is the tail of a Claude Code terminal session that the user is resuming after being away. ")
+	sb.WriteString("Write a short recap (2-4 sentences) of what was being worked on and what's left to do, so the ")
+	sb.WriteString("user can pick up where they left off. Return ONLY the recap.\n\n")
+	if len(items) > 0 {
+		sb.WriteString("Pending action items noticed in the transcript:\n")
+		for _, item := range items {
+			sb.WriteString("- ")
+			sb.WriteString(item)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("Transcript:\n")
+	sb.WriteString(tail)
+	return sb.String()
+}
+
+// ResumeContextGenerator produces a short recap from a prompt combining a
+// transcript tail and pending action items. It mirrors TitleGenerator's
+// shape so generateResumeContext is testable with a stub, without invoking
+// the real claude CLI.
+type ResumeContextGenerator interface {
+	GenerateResumeContext(ctx context.Context, prompt string) (string, error)
+}
+
+// cliResumeContextGenerator calls the claude CLI in print mode, the same
+// backend generateTitle uses for summaries, to produce a recap.
+type cliResumeContextGenerator struct{}
+
+func (cliResumeContextGenerator) GenerateResumeContext(ctx context.Context, prompt string) (string, error) {
+	cmd := exec.CommandContext(ctx, "claude", "-p", "--model", "haiku")
+	cmd.Stdin = strings.NewReader(prompt)
+
+	output, err := cmd.Output()
+	recordCLIOutcome(err)
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", fmt.Errorf("%w: %v", ErrGeneratorUnavailable, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("claude command error: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// activeResumeContextGenerator is the ResumeContextGenerator used by
+// GenerateResumeContext. A package variable so tests can substitute a stub,
+// mirroring learningsGeneratorFn.
+var activeResumeContextGenerator ResumeContextGenerator = cliResumeContextGenerator{}
+
+// generateResumeContext extracts pending action items and the recent tail
+// from transcript, builds a recap prompt, and invokes gen to produce it.
+// Split from GenerateResumeContext so it's testable without a
+// filestore-backed block.
+func generateResumeContext(ctx context.Context, gen ResumeContextGenerator, transcript string) (string, error) {
+	items := extractPendingActionItems(transcript)
+	tail := transcriptTail(transcript, ResumeContextTailChars)
+	prompt := buildResumeContextPrompt(tail, items)
+	return gen.GenerateResumeContext(ctx, prompt)
+}
+
+// GenerateResumeContext returns a short "where was I" recap of a block's
+// session, generated from the tail of its transcript plus any pending
+// action items noticed within it, for a user returning to an idle or
+// archived session. The recap is cached in block meta alongside the
+// transcript length it was generated from, and is regenerated whenever the
+// transcript's length differs from that cached length.
+func (s *TermDashService) GenerateResumeContext(ctx context.Context, blockId string) (string, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	transcript := reconstructTranscriptText(data)
+
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+
+	cached := block.Meta.GetString(waveobj.MetaKey_TermDashResumeContext, "")
+	cachedLen := block.Meta.GetInt(waveobj.MetaKey_TermDashResumeContextLen, -1)
+	if cached != "" && cachedLen == len(transcript) {
+		return cached, nil
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	recap, err := generateResumeContext(execCtx, activeResumeContextGenerator, transcript)
+	if err != nil {
+		return "", err
+	}
+
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashResumeContext:    recap,
+		waveobj.MetaKey_TermDashResumeContextLen: len(transcript),
+	}
+	updateCtx, updateCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer updateCancel()
+	if err := wstore.UpdateObjectMeta(updateCtx, waveobj.MakeORef(waveobj.OType_Block, blockId), metaUpdate, false); err != nil {
+		return "", fmt.Errorf("error saving resume context for block %s: %w", blockId, err)
+	}
+
+	return recap, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// stubResumeContextGenerator is a ResumeContextGenerator that records the
+// prompt it was called with and returns a fixed recap, so tests can assert
+// on generateResumeContext's behavior without invoking the claude CLI.
+type stubResumeContextGenerator struct {
+	recap      string
+	err        error
+	gotPrompts []string
+}
+
+func (s *stubResumeContextGenerator) GenerateResumeContext(ctx context.Context, prompt string) (string, error) {
+	s.gotPrompts = append(s.gotPrompts, prompt)
+	return s.recap, s.err
+}
+
+func TestExtractPendingActionItemsFindsCheckboxesAndTodos(t *testing.T) {
+	transcript := "Progress so far:\n" +
+		"- [x] set up project\n" +
+		"- [ ] write the parser\n" +
+		"* [ ] add tests\n" +
+		"TODO: update the README\n"
+
+	items := extractPendingActionItems(transcript)
+
+	want := []string{"write the parser", "add tests", "update the README"}
+	if len(items) != len(want) {
+		t.Fatalf("extractPendingActionItems() = %v, want %v", items, want)
+	}
+	for i, w := range want {
+		if items[i] != w {
+			t.Errorf("item %d = %q, want %q", i, items[i], w)
+		}
+	}
+}
+
+func TestExtractPendingActionItemsDeduplicates(t *testing.T) {
+	transcript := "- [ ] fix the bug\n- [ ] fix the bug\n"
+	items := extractPendingActionItems(transcript)
+	if len(items) != 1 {
+		t.Errorf("extractPendingActionItems() returned %d items, want 1 (deduplicated)", len(items))
+	}
+}
+
+func TestGenerateResumeContextProducesRecapFromStubbedGenerator(t *testing.T) {
+	stub := &stubResumeContextGenerator{recap: "You were mid-way through fixing the parser; the README update is still pending."}
+
+	transcript := "wrote the initial parser\nran into a bug in tokenizer\n- [ ] update the README\n"
+	got, err := generateResumeContext(context.Background(), stub, transcript)
+	if err != nil {
+		t.Fatalf("generateResumeContext() error = %v", err)
+	}
+	if got != stub.recap {
+		t.Errorf("generateResumeContext() = %q, want %q", got, stub.recap)
+	}
+	if len(stub.gotPrompts) != 1 {
+		t.Fatalf("generator called %d times, want 1", len(stub.gotPrompts))
+	}
+	if !containsAll(stub.gotPrompts[0], "update the README", "wrote the initial parser") {
+		t.Errorf("prompt %q missing expected transcript/action-item content", stub.gotPrompts[0])
+	}
+}
+
+// containsAll reports whether s contains every substr in substrs.
+func containsAll(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// GetSessionChain returns the resume lineage for a block's Claude session,
+// ordered oldest-first, by following MetaKey_TermDashResumedFrom links
+// back to the original session. The returned IDs are Claude session IDs
+// (not block IDs), starting with the earliest ancestor and ending with
+// blockId's own session ID.
+func (s *TermDashService) GetSessionChain(ctx context.Context, blockId string) ([]string, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	blocksBySessionId := make(map[string]*waveobj.Block)
+	for _, block := range blocks {
+		sessionId := block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, "")
+		if sessionId != "" {
+			blocksBySessionId[sessionId] = block
+		}
+	}
+
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting block: %w", err)
+	}
+
+	var chain []string
+	seen := make(map[string]bool)
+	for block != nil {
+		sessionId := block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, "")
+		if sessionId == "" || seen[sessionId] {
+			break
+		}
+		seen[sessionId] = true
+		chain = append([]string{sessionId}, chain...)
+
+		resumedFrom := block.Meta.GetString(waveobj.MetaKey_TermDashResumedFrom, "")
+		if resumedFrom == "" || seen[resumedFrom] {
+			break
+		}
+		next, ok := blocksBySessionId[resumedFrom]
+		if !ok {
+			// Ancestor session has no live block (e.g. deleted), but we
+			// still know it's the root of the chain.
+			chain = append([]string{resumedFrom}, chain...)
+			break
+		}
+		block = next
+	}
+
+	return chain, nil
+}
+
+// GetMergedTranscript walks the resume chain for blockId (via
+// GetSessionChain) and concatenates each link's transcript (via
+// GetTranscript), in chronological order, so a user viewing a resumed
+// session sees the full history rather than just the latest link. Links
+// with no live block or no recorded transcript are skipped.
+func (s *TermDashService) GetMergedTranscript(ctx context.Context, blockId string) (string, error) {
+	chain, err := s.GetSessionChain(ctx, blockId)
+	if err != nil {
+		return "", err
+	}
+
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return "", fmt.Errorf("error listing blocks: %w", err)
+	}
+	blockIdBySessionId := make(map[string]string)
+	for _, block := range blocks {
+		sessionId := block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, "")
+		if sessionId != "" {
+			blockIdBySessionId[sessionId] = block.OID
+		}
+	}
+
+	var texts []string
+	for _, sessionId := range chain {
+		linkBlockId, ok := blockIdBySessionId[sessionId]
+		if !ok {
+			continue // ancestor session has no live block
+		}
+		text, err := s.GetTranscript(ctx, linkBlockId, termdash.ANSIModeStrip)
+		if err != nil {
+			continue // e.g. no transcript recorded yet for this link
+		}
+		texts = append(texts, text)
+	}
+
+	return mergeTranscriptTexts(texts), nil
+}
+
+// mergeTranscriptTexts concatenates a resume chain's transcript texts, in
+// chronological order, skipping any empty entries (a link with no
+// transcript).
+func mergeTranscriptTexts(texts []string) string {
+	var sb strings.Builder
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
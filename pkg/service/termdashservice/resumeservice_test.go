@@ -0,0 +1,22 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import "testing"
+
+func TestMergeTranscriptTextsOrdersTwoLinksChronologically(t *testing.T) {
+	got := mergeTranscriptTexts([]string{"first session output", "second session output"})
+	want := "first session output\nsecond session output"
+	if got != want {
+		t.Errorf("mergeTranscriptTexts() = %q, want %q", got, want)
+	}
+}
+
+func TestMergeTranscriptTextsSkipsMissingLinks(t *testing.T) {
+	got := mergeTranscriptTexts([]string{"first session output", "", "third session output"})
+	want := "first session output\nthird session output"
+	if got != want {
+		t.Errorf("mergeTranscriptTexts() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/panichandler"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wconfig"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+const (
+	RetentionSweepInterval = 10 * time.Minute
+)
+
+// RetentionPolicy bounds how much transcript data archived Claude sessions
+// are allowed to hold, enforced by ApplyRetention rather than by separate
+// rotation/purge features the user would otherwise have to configure one by
+// one. Each field is a distinct limit; zero means that limit is not
+// enforced. Only archived sessions are ever touched (an active session is
+// still in use and isn't a retention candidate), and a session with
+// TermDashPinned or TermDashNoRecord set is always skipped regardless of
+// how badly it violates the policy.
+type RetentionPolicy struct {
+	// MaxTranscriptBytes, if set, rotates a session's transcript down to its
+	// most recent entries once the encoded transcript exceeds this size.
+	MaxTranscriptBytes int64
+
+	// MaxAge, if set, purges a session's transcript entirely once it has
+	// been archived for longer than this.
+	MaxAge time.Duration
+
+	// MaxEntriesPerSession, if set, rotates a session's transcript down to
+	// its most recent entries once it holds more than this many.
+	MaxEntriesPerSession int
+
+	// MaxSessions, if set, purges the transcripts of the oldest archived
+	// sessions (by ArchivedAt) once the number of archived sessions with a
+	// transcript exceeds this count.
+	MaxSessions int
+}
+
+// RetentionReport summarizes what ApplyRetention did, so a manual
+// invocation (or a log line from the background sweep) can say what
+// happened rather than just "done".
+type RetentionReport struct {
+	SessionsScanned int   `json:"sessionsscanned"`
+	SessionsSkipped int   `json:"sessionsskipped"` // pinned or recording-disabled
+	SessionsRotated int   `json:"sessionsrotated"` // trimmed to the most recent entries/bytes
+	SessionsPurged  int   `json:"sessionspurged"`  // transcript deleted entirely
+	BytesReclaimed  int64 `json:"bytesreclaimed"`
+}
+
+// ApplyRetention runs the retention policy configured in settings.json once,
+// synchronously, and reports what it did. It's the same sweep
+// StartRetentionLoop runs periodically, exposed for on-demand invocation
+// (e.g. a user wanting to reclaim space immediately after lowering a
+// limit).
+func (s *TermDashService) ApplyRetention(ctx context.Context) (*RetentionReport, error) {
+	return runRetentionSweep(ctx, configuredRetentionPolicy())
+}
+
+// StartRetentionLoop starts the background sweep that enforces the
+// retention policy configured in settings.json (the termdash:retention*
+// keys) against archived Claude sessions. The sweep interval is read from
+// termdash:retentionsweepms, falling back to RetentionSweepInterval when
+// unset. A configured interval of 0 disables the sweep entirely, leaving
+// retention to be applied on demand only.
+func StartRetentionLoop() {
+	go func() {
+		defer func() {
+			panichandler.PanicHandler("termdash:retentionLoop", recover())
+		}()
+		for {
+			interval := retentionSweepInterval()
+			if interval <= 0 {
+				return
+			}
+			time.Sleep(interval)
+			runRetentionSweepLogged()
+		}
+	}()
+}
+
+// retentionSweepInterval returns the configured retention sweep interval,
+// or RetentionSweepInterval if unset. Read fresh on every loop iteration so
+// a config change takes effect without a restart.
+func retentionSweepInterval() time.Duration {
+	settings := wconfig.GetWatcher().GetFullConfig().Settings
+	return resolveConfiguredDuration(settings.TermDashRetentionSweepMs, RetentionSweepInterval)
+}
+
+// configuredRetentionPolicy builds a RetentionPolicy from settings.json,
+// read fresh so a config change takes effect on the next sweep without a
+// restart. Every limit defaults to 0 (unenforced) when unset.
+func configuredRetentionPolicy() RetentionPolicy {
+	settings := wconfig.GetWatcher().GetFullConfig().Settings
+	return RetentionPolicy{
+		MaxTranscriptBytes:   int64(floatPtrOrZero(settings.TermDashRetentionMaxBytes)),
+		MaxAge:               time.Duration(floatPtrOrZero(settings.TermDashRetentionMaxAgeMs)) * time.Millisecond,
+		MaxEntriesPerSession: int(floatPtrOrZero(settings.TermDashRetentionMaxEntries)),
+		MaxSessions:          int(floatPtrOrZero(settings.TermDashRetentionMaxSessions)),
+	}
+}
+
+// floatPtrOrZero returns *p, or 0 if p is nil.
+func floatPtrOrZero(p *float64) float64 {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+// runRetentionSweepLogged runs one retention sweep and logs the result,
+// for the background loop (ApplyRetention returns the report directly for
+// on-demand callers instead).
+func runRetentionSweepLogged() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	report, err := runRetentionSweep(ctx, configuredRetentionPolicy())
+	if err != nil {
+		log.Printf("[termdash:retention] error applying retention: %v\n", err)
+		return
+	}
+	if report.SessionsRotated > 0 || report.SessionsPurged > 0 {
+		log.Printf("[termdash:retention] scanned %d, rotated %d, purged %d, reclaimed %d bytes\n",
+			report.SessionsScanned, report.SessionsRotated, report.SessionsPurged, report.BytesReclaimed)
+	}
+}
+
+// retentionCandidate is the subset of an archived block's state the sweep
+// needs to decide what to do with it.
+type retentionCandidate struct {
+	blockId    string
+	archivedAt int64
+}
+
+func runRetentionSweep(ctx context.Context, policy RetentionPolicy) (*RetentionReport, error) {
+	report := &RetentionReport{}
+
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	var candidates []retentionCandidate
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		if !block.Meta.GetBool(waveobj.MetaKey_TermDashArchived, false) {
+			continue
+		}
+		report.SessionsScanned++
+		if block.Meta.GetBool(waveobj.MetaKey_TermDashPinned, false) || block.Meta.GetBool(waveobj.MetaKey_TermDashNoRecord, false) {
+			report.SessionsSkipped++
+			continue
+		}
+		candidates = append(candidates, retentionCandidate{
+			blockId:    block.OID,
+			archivedAt: int64(block.Meta.GetFloat(waveobj.MetaKey_TermDashArchivedAt, 0)),
+		})
+	}
+
+	purged := map[string]bool{}
+	for _, c := range candidates {
+		reclaimed, didPurge, didRotate, err := applyPerSessionRetention(ctx, c, policy)
+		if err != nil {
+			log.Printf("[termdash:retention] error applying retention to block %s: %v\n", c.blockId, err)
+			continue
+		}
+		report.BytesReclaimed += reclaimed
+		if didPurge {
+			report.SessionsPurged++
+			purged[c.blockId] = true
+		} else if didRotate {
+			report.SessionsRotated++
+		}
+	}
+
+	if policy.MaxSessions > 0 {
+		reclaimed, purgedCount := enforceMaxSessions(ctx, candidates, purged, policy.MaxSessions)
+		report.BytesReclaimed += reclaimed
+		report.SessionsPurged += purgedCount
+	}
+
+	return report, nil
+}
+
+// applyPerSessionRetention enforces MaxAge, MaxEntriesPerSession, and
+// MaxTranscriptBytes against a single session's transcript.
+func applyPerSessionRetention(ctx context.Context, c retentionCandidate, policy RetentionPolicy) (bytesReclaimed int64, didPurge bool, didRotate bool, err error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, c.blockId, "termdash:transcript")
+	if err != nil {
+		return 0, false, false, nil // no transcript yet; nothing to do
+	}
+	if len(data) == 0 {
+		return 0, false, false, nil
+	}
+
+	if isSessionExpired(c.archivedAt, policy.MaxAge, time.Now()) {
+		if err := purgeTranscript(ctx, c.blockId); err != nil {
+			return 0, false, false, err
+		}
+		return int64(len(data)), true, false, nil
+	}
+
+	format := termdash.TranscriptFormatJSONL
+	if termdash.IsBinaryTranscript(data) {
+		format = termdash.TranscriptFormatBinary
+	}
+	entries := termdash.DecodeTranscriptEntries(data)
+	rotated := rotateEntries(entries, format, policy.MaxEntriesPerSession, policy.MaxTranscriptBytes)
+	if len(rotated) == len(entries) {
+		return 0, false, false, nil
+	}
+
+	encoded := termdash.EncodeTranscriptEntries(rotated, format)
+	if err := filestore.WFS.WriteFile(ctx, c.blockId, "termdash:transcript", encoded); err != nil {
+		return 0, false, false, fmt.Errorf("error writing rotated transcript: %w", err)
+	}
+	return int64(len(data)) - int64(len(encoded)), false, true, nil
+}
+
+// isSessionExpired reports whether a session archived at archivedAt is
+// older than maxAge as of now. A maxAge or archivedAt of 0 means the limit
+// doesn't apply.
+func isSessionExpired(archivedAt int64, maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 || archivedAt <= 0 {
+		return false
+	}
+	return now.Sub(time.UnixMilli(archivedAt)) > maxAge
+}
+
+// rotateEntries drops the oldest entries first to satisfy maxEntries (a
+// count limit), then further drops the oldest to satisfy maxBytes (a size
+// limit on the resulting encoding), always keeping the most recent entries.
+// Either limit of 0 is skipped.
+func rotateEntries(entries []termdash.TranscriptEntry, format string, maxEntries int, maxBytes int64) []termdash.TranscriptEntry {
+	rotated := entries
+	if maxEntries > 0 && len(rotated) > maxEntries {
+		rotated = rotated[len(rotated)-maxEntries:]
+	}
+	if maxBytes > 0 {
+		for len(rotated) > 0 && int64(len(termdash.EncodeTranscriptEntries(rotated, format))) > maxBytes {
+			rotated = rotated[1:]
+		}
+	}
+	return rotated
+}
+
+// enforceMaxSessions purges the transcripts of the oldest archived sessions
+// (by ArchivedAt) not already purged, until at most maxSessions remain.
+func enforceMaxSessions(ctx context.Context, candidates []retentionCandidate, alreadyPurged map[string]bool, maxSessions int) (bytesReclaimed int64, purgedCount int) {
+	var remaining []retentionCandidate
+	for _, c := range candidates {
+		if !alreadyPurged[c.blockId] {
+			remaining = append(remaining, c)
+		}
+	}
+
+	for _, c := range selectExcessSessions(remaining, maxSessions) {
+		_, data, err := filestore.WFS.ReadFile(ctx, c.blockId, "termdash:transcript")
+		if err != nil {
+			continue
+		}
+		if err := purgeTranscript(ctx, c.blockId); err != nil {
+			log.Printf("[termdash:retention] error purging block %s over max session count: %v\n", c.blockId, err)
+			continue
+		}
+		bytesReclaimed += int64(len(data))
+		purgedCount++
+	}
+	return bytesReclaimed, purgedCount
+}
+
+// selectExcessSessions returns the oldest candidates (by archivedAt) beyond
+// the first maxSessions, i.e. the ones a max-session-count limit should
+// purge. Returns nil if there are maxSessions or fewer, or maxSessions is 0
+// (unenforced).
+func selectExcessSessions(candidates []retentionCandidate, maxSessions int) []retentionCandidate {
+	if maxSessions <= 0 || len(candidates) <= maxSessions {
+		return nil
+	}
+
+	sorted := make([]retentionCandidate, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].archivedAt < sorted[j].archivedAt
+	})
+
+	return sorted[:len(sorted)-maxSessions]
+}
+
+// purgeTranscript deletes a block's transcript file entirely, leaving its
+// summary/status metadata (and the block itself) intact.
+func purgeTranscript(ctx context.Context, blockId string) error {
+	err := filestore.WFS.DeleteFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return fmt.Errorf("error deleting transcript: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestIsSessionExpired(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	archivedAt := now.Add(-2 * time.Hour).UnixMilli()
+
+	if isSessionExpired(archivedAt, time.Hour, now) != true {
+		t.Error("expected a session archived 2h ago to be expired against a 1h max age")
+	}
+	if isSessionExpired(archivedAt, 3*time.Hour, now) != false {
+		t.Error("expected a session archived 2h ago to not be expired against a 3h max age")
+	}
+	if isSessionExpired(archivedAt, 0, now) != false {
+		t.Error("expected max age of 0 to disable the limit")
+	}
+	if isSessionExpired(0, time.Hour, now) != false {
+		t.Error("expected an unset archivedAt to never be treated as expired")
+	}
+}
+
+func TestRotateEntriesEnforcesMaxEntries(t *testing.T) {
+	entries := []termdash.TranscriptEntry{
+		{Timestamp: 1, Type: "output", Text: "one"},
+		{Timestamp: 2, Type: "output", Text: "two"},
+		{Timestamp: 3, Type: "output", Text: "three"},
+	}
+
+	got := rotateEntries(entries, termdash.TranscriptFormatJSONL, 2, 0)
+	if len(got) != 2 || got[0].Text != "two" || got[1].Text != "three" {
+		t.Errorf("rotateEntries() = %v, want the 2 most recent entries", got)
+	}
+}
+
+func TestRotateEntriesEnforcesMaxBytes(t *testing.T) {
+	entries := []termdash.TranscriptEntry{
+		{Timestamp: 1, Type: "output", Text: "aaaaaaaaaa"},
+		{Timestamp: 2, Type: "output", Text: "bbbbbbbbbb"},
+		{Timestamp: 3, Type: "output", Text: "c"},
+	}
+	full := termdash.EncodeTranscriptEntries(entries, termdash.TranscriptFormatJSONL)
+	budget := int64(len(full)) - 1 // force at least one entry to be dropped
+
+	got := rotateEntries(entries, termdash.TranscriptFormatJSONL, 0, budget)
+	if len(got) == 0 || len(got) >= len(entries) {
+		t.Fatalf("rotateEntries() = %v, want fewer than %d entries", got, len(entries))
+	}
+	if got[len(got)-1].Text != "c" {
+		t.Errorf("expected the most recent entry to survive, got %v", got)
+	}
+	if int64(len(termdash.EncodeTranscriptEntries(got, termdash.TranscriptFormatJSONL))) > budget {
+		t.Errorf("rotated result still exceeds the %d byte budget", budget)
+	}
+}
+
+func TestRotateEntriesUnlimitedReturnsUnchanged(t *testing.T) {
+	entries := []termdash.TranscriptEntry{
+		{Timestamp: 1, Type: "output", Text: "one"},
+		{Timestamp: 2, Type: "output", Text: "two"},
+	}
+	got := rotateEntries(entries, termdash.TranscriptFormatJSONL, 0, 0)
+	if len(got) != len(entries) {
+		t.Errorf("rotateEntries() with no limits = %v, want unchanged %v", got, entries)
+	}
+}
+
+func TestSelectExcessSessionsReturnsOldestBeyondMax(t *testing.T) {
+	candidates := []retentionCandidate{
+		{blockId: "newest", archivedAt: 3000},
+		{blockId: "oldest", archivedAt: 1000},
+		{blockId: "middle", archivedAt: 2000},
+	}
+
+	excess := selectExcessSessions(candidates, 2)
+	if len(excess) != 1 || excess[0].blockId != "oldest" {
+		t.Errorf("selectExcessSessions() = %v, want just the oldest session", excess)
+	}
+}
+
+func TestSelectExcessSessionsWithinLimitReturnsNil(t *testing.T) {
+	candidates := []retentionCandidate{
+		{blockId: "a", archivedAt: 1000},
+		{blockId: "b", archivedAt: 2000},
+	}
+	if got := selectExcessSessions(candidates, 5); got != nil {
+		t.Errorf("selectExcessSessions() = %v, want nil when under the limit", got)
+	}
+	if got := selectExcessSessions(candidates, 0); got != nil {
+		t.Errorf("selectExcessSessions() = %v, want nil when maxSessions is 0 (unenforced)", got)
+	}
+}
@@ -4,11 +4,17 @@
 package termdashservice
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
 	"github.com/wavetermdev/waveterm/pkg/waveobj"
 	"github.com/wavetermdev/waveterm/pkg/wstore"
 )
@@ -21,8 +27,33 @@ type TranscriptSearchResult struct {
 	Offset    int    `json:"offset"`  // character offset of the match
 }
 
-// SearchTranscripts searches transcript files across all Claude blocks.
-func (s *TermDashService) SearchTranscripts(ctx context.Context, query string) ([]TranscriptSearchResult, error) {
+// SearchScope narrows a search to active or archived blocks, or leaves it
+// unrestricted. The zero value SearchScope("") behaves like SearchScopeAll.
+type SearchScope string
+
+const (
+	SearchScopeAll      SearchScope = "all"
+	SearchScopeActive   SearchScope = "active"
+	SearchScopeArchived SearchScope = "archived"
+)
+
+// matchesScope reports whether a block with the given archived flag should
+// be included under scope. Extracted from the search loops so it can be
+// unit-tested without a filestore- or DB-backed block.
+func matchesScope(archived bool, scope SearchScope) bool {
+	switch scope {
+	case SearchScopeActive:
+		return !archived
+	case SearchScopeArchived:
+		return archived
+	default:
+		return true
+	}
+}
+
+// SearchTranscripts searches transcript files across Claude blocks matching
+// scope (SearchScopeAll searches every block regardless of archive state).
+func (s *TermDashService) SearchTranscripts(ctx context.Context, query string, scope SearchScope) ([]TranscriptSearchResult, error) {
 	if query == "" {
 		return nil, fmt.Errorf("query cannot be empty")
 	}
@@ -39,6 +70,9 @@ func (s *TermDashService) SearchTranscripts(ctx context.Context, query string) (
 		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
 			continue
 		}
+		if !matchesScope(block.Meta.GetBool(waveobj.MetaKey_TermDashArchived, false), scope) {
+			continue
+		}
 
 		// Read transcript file for this block
 		_, data, err := filestore.WFS.ReadFile(ctx, block.OID, "termdash:transcript")
@@ -46,14 +80,18 @@ func (s *TermDashService) SearchTranscripts(ctx context.Context, query string) (
 			continue // no transcript yet
 		}
 
-		content := strings.ToLower(string(data))
+		// Search against the same reconstructed text GetTranscript and
+		// GetTranscriptContext operate on, so the returned offset lines up
+		// with what's displayed rather than pointing into the raw JSONL.
+		text := reconstructTranscriptText(data)
+		content := strings.ToLower(text)
 		idx := strings.Index(content, query)
 		if idx == -1 {
 			continue
 		}
 
 		// Extract snippet around match
-		snippet := extractSnippet(string(data), idx, len(query), 100)
+		snippet := extractSnippet(text, idx, len(query), 100)
 
 		results = append(results, TranscriptSearchResult{
 			BlockId:   block.OID,
@@ -66,40 +104,396 @@ func (s *TermDashService) SearchTranscripts(ctx context.Context, query string) (
 	return results, nil
 }
 
-// GetTranscript reads the full transcript for a block, cleaned.
-func (s *TermDashService) GetTranscript(ctx context.Context, blockId string) (string, error) {
+// searchEntry is one block's search-relevant data, separated from the
+// DB/filestore lookups in SearchTranscriptsStream so the actual matching and
+// early-stop logic is testable without a database- or filestore-backed
+// block.
+type searchEntry struct {
+	blockId   string
+	sessionId string
+	summary   string
+	text      string
+}
+
+// streamSearchMatches scans entries in order, invoking onResult for each one
+// whose text contains query (case-insensitively), and stops scanning as soon
+// as onResult returns false.
+func streamSearchMatches(entries []searchEntry, query string, onResult func(TranscriptSearchResult) bool) {
+	query = strings.ToLower(query)
+	for _, e := range entries {
+		content := strings.ToLower(e.text)
+		idx := strings.Index(content, query)
+		if idx == -1 {
+			continue
+		}
+		result := TranscriptSearchResult{
+			BlockId:   e.blockId,
+			SessionId: e.sessionId,
+			Summary:   e.summary,
+			Snippet:   extractSnippet(e.text, idx, len(query), 100),
+			Offset:    idx,
+		}
+		if !onResult(result) {
+			return
+		}
+	}
+}
+
+// SearchTranscriptsStream searches transcript files across every Claude
+// block, same as SearchTranscripts, but invokes onResult as each match is
+// found instead of collecting them into a slice first. This lets a caller
+// render results incrementally against a very large corpus, or cancel the
+// scan early by returning false from onResult, rather than waiting for the
+// full scan to finish before seeing anything.
+func (s *TermDashService) SearchTranscriptsStream(ctx context.Context, query string, onResult func(TranscriptSearchResult) bool) error {
+	if query == "" {
+		return fmt.Errorf("query cannot be empty")
+	}
+
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	var entries []searchEntry
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		_, data, err := filestore.WFS.ReadFile(ctx, block.OID, "termdash:transcript")
+		if err != nil {
+			continue // no transcript yet
+		}
+		entries = append(entries, searchEntry{
+			blockId:   block.OID,
+			sessionId: block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, ""),
+			summary:   block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""),
+			text:      reconstructTranscriptText(data),
+		})
+	}
+
+	var stopErr error
+	streamSearchMatches(entries, query, func(result TranscriptSearchResult) bool {
+		if ctx.Err() != nil {
+			stopErr = ctx.Err()
+			return false
+		}
+		return onResult(result)
+	})
+	return stopErr
+}
+
+// SearchCursor encodes where a page of SearchTranscriptsPage left off: the
+// last block scanned and the offset within its reconstructed text to resume
+// from, so paging through a huge result set doesn't rescan every earlier
+// block on each page the way offset-based paging would.
+type SearchCursor struct {
+	BlockId string
+	Offset  int
+}
+
+// encodeCursor renders a SearchCursor as the opaque string
+// SearchTranscriptsPage returns as nextCursor and accepts back as cursor.
+// The zero SearchCursor encodes to "", matching the "start from the
+// beginning" input.
+func encodeCursor(c SearchCursor) string {
+	if c.BlockId == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.BlockId, c.Offset)
+}
+
+// decodeCursor parses a cursor string produced by encodeCursor. An empty or
+// malformed cursor decodes to the zero SearchCursor (start from the
+// beginning): a stale or tampered cursor should restart the scan rather
+// than fail the caller's request.
+func decodeCursor(cursor string) SearchCursor {
+	idx := strings.LastIndex(cursor, ":")
+	if idx == -1 {
+		return SearchCursor{}
+	}
+	offset, err := strconv.Atoi(cursor[idx+1:])
+	if err != nil {
+		return SearchCursor{}
+	}
+	return SearchCursor{BlockId: cursor[:idx], Offset: offset}
+}
+
+// pageSearchMatches scans entries, which must be in a stable order across
+// calls (SearchTranscriptsPage sorts by blockId), for query starting at
+// cursor and returns up to limit matches (limit <= 0 means no limit) plus
+// the cursor to resume from for the next page. The zero SearchCursor
+// returned means the scan reached the end of entries with no more matches.
+// Extracted from SearchTranscriptsPage so paging is testable without a
+// DB/filestore-backed set of blocks.
+func pageSearchMatches(entries []searchEntry, query string, cursor SearchCursor, limit int) ([]TranscriptSearchResult, SearchCursor) {
+	query = strings.ToLower(query)
+	startIdx := 0
+	if cursor.BlockId != "" {
+		for i, e := range entries {
+			if e.blockId == cursor.BlockId {
+				startIdx = i
+				break
+			}
+		}
+	}
+
+	var results []TranscriptSearchResult
+	for i := startIdx; i < len(entries); i++ {
+		e := entries[i]
+		offset := 0
+		if e.blockId == cursor.BlockId {
+			offset = cursor.Offset
+		}
+		content := strings.ToLower(e.text)
+		for offset <= len(content) {
+			idx := strings.Index(content[offset:], query)
+			if idx == -1 {
+				break
+			}
+			matchStart := offset + idx
+			results = append(results, TranscriptSearchResult{
+				BlockId:   e.blockId,
+				SessionId: e.sessionId,
+				Summary:   e.summary,
+				Snippet:   extractSnippet(e.text, matchStart, len(query), 100),
+				Offset:    matchStart,
+			})
+			offset = matchStart + len(query)
+			if limit > 0 && len(results) == limit {
+				return results, SearchCursor{BlockId: e.blockId, Offset: offset}
+			}
+		}
+	}
+	return results, SearchCursor{}
+}
+
+// SearchTranscriptsPage searches transcript files across every Claude block
+// for query, same as SearchTranscripts, but returns at most limit results
+// (limit <= 0 means no limit) plus a cursor to resume from, so a UI paging
+// through a huge result set doesn't rescan already-returned blocks the way
+// offset-based paging would. Pass "" as cursor for the first page; an empty
+// nextCursor means there are no more results.
+func (s *TermDashService) SearchTranscriptsPage(ctx context.Context, query string, cursor string, limit int) (results []TranscriptSearchResult, nextCursor string, err error) {
+	if query == "" {
+		return nil, "", fmt.Errorf("query cannot be empty")
+	}
+
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing blocks: %w", err)
+	}
+	// pageSearchMatches relies on a stable order across calls to make the
+	// cursor's blockId meaningful; DBGetAllObjsByType makes no ordering
+	// guarantee, so sort explicitly.
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].OID < blocks[j].OID })
+
+	var entries []searchEntry
+	for _, block := range blocks {
+		if block.Meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+			continue
+		}
+		_, data, err := filestore.WFS.ReadFile(ctx, block.OID, "termdash:transcript")
+		if err != nil {
+			continue // no transcript yet
+		}
+		entries = append(entries, searchEntry{
+			blockId:   block.OID,
+			sessionId: block.Meta.GetString(waveobj.MetaKey_TermDashClaudeSession, ""),
+			summary:   block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""),
+			text:      reconstructTranscriptText(data),
+		})
+	}
+
+	pageResults, next := pageSearchMatches(entries, query, decodeCursor(cursor), limit)
+	return pageResults, encodeCursor(next), nil
+}
+
+// GetTranscript reads the full transcript for a block, with mode
+// controlling how any ANSI escape codes in the text are handled: stripped
+// (the default, ANSIModeStrip or ""), left as-is for a raw capture
+// (ANSIModePreserve), or converted to HTML markup for display
+// (ANSIModeConvertHTML). One stored transcript can be rendered all three
+// ways without re-recording it.
+func (s *TermDashService) GetTranscript(ctx context.Context, blockId string, mode termdash.ANSIMode) (string, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	return termdash.ApplyANSIMode(reconstructTranscriptText(data), mode), nil
+}
+
+// Range is a [Start, End) byte range within a text, e.g. one match location
+// for a UI to highlight.
+type Range struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// GetTranscriptWithHighlights reads a block's transcript and returns the
+// reconstructed text alongside every range where query matches, so a UI
+// opening a session from a search result can highlight all occurrences
+// (not just the first).
+func (s *TermDashService) GetTranscriptWithHighlights(ctx context.Context, blockId string, query string) (string, []Range, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	text := reconstructTranscriptText(data)
+	return text, findMatchRanges(text, query), nil
+}
+
+// findMatchRanges returns every non-overlapping range in text where query
+// matches, case-insensitively.
+func findMatchRanges(text, query string) []Range {
+	if query == "" {
+		return nil
+	}
+	content := strings.ToLower(text)
+	query = strings.ToLower(query)
+
+	var ranges []Range
+	offset := 0
+	for {
+		idx := strings.Index(content[offset:], query)
+		if idx == -1 {
+			break
+		}
+		start := offset + idx
+		end := start + len(query)
+		ranges = append(ranges, Range{Start: start, End: end})
+		offset = end
+	}
+	return ranges
+}
+
+// transcriptTextEntry is the subset of a JSONL transcript line reconstructTranscriptText cares about.
+type transcriptTextEntry struct {
+	Text string `json:"text"`
+}
+
+// reconstructTranscriptText parses JSONL transcript data into the same
+// concatenated, cleaned text across all callers (GetTranscript,
+// SearchTranscripts, GetTranscriptContext), so a byte offset computed
+// against one lines up with the others rather than pointing into the raw
+// JSONL file.
+//
+// Streams the decode with json.Decoder directly over data rather than
+// splitting into lines and hand-extracting the "text" field per line: the
+// old approach allocated a []string for every line up front and its
+// ReplaceAll-based unescaping didn't handle the full range of JSON escapes
+// (e.g. \uXXXX), so it could silently produce different text than a real
+// JSON parse for content containing one. json.Decoder also lets the output
+// strings.Builder be sized once from len(data) instead of growing
+// per-WriteString call.
+func reconstructTranscriptText(data []byte) string {
+	if termdash.IsBinaryTranscript(data) {
+		var output strings.Builder
+		for _, entry := range termdash.DecodeTranscriptEntriesBinary(data) {
+			output.WriteString(entry.Text)
+		}
+		return output.String()
+	}
+
+	var output strings.Builder
+	output.Grow(len(data))
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var entry transcriptTextEntry
+		if err := dec.Decode(&entry); err != nil {
+			break
+		}
+		output.WriteString(entry.Text)
+	}
+	return output.String()
+}
+
+// GetTranscriptContext returns the transcript text surrounding offset, for
+// jumping directly to a match found by SearchTranscripts. offset is in the
+// same reconstructed-text space as SearchTranscripts' Offset field, so a
+// search result's Offset can be passed straight through.
+func (s *TermDashService) GetTranscriptContext(ctx context.Context, blockId string, offset int, contextLen int) (string, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	return transcriptContext(reconstructTranscriptText(data), offset, contextLen)
+}
+
+// transcriptContext returns the window of text within contextLen bytes of
+// offset. Extracted from GetTranscriptContext so it can be tested without a
+// filestore-backed block.
+func transcriptContext(text string, offset, contextLen int) (string, error) {
+	if offset < 0 || offset > len(text) {
+		return "", fmt.Errorf("offset %d out of range for transcript of length %d", offset, len(text))
+	}
+	return extractSnippet(text, offset, 0, contextLen), nil
+}
+
+// GetTranscriptRange reads the transcript for a block and returns the text
+// of only the entries whose timestamp falls within [startMs, endMs], for
+// viewing a window of a long session. mode controls ANSI handling exactly
+// as in GetTranscript.
+func (s *TermDashService) GetTranscriptRange(ctx context.Context, blockId string, startMs, endMs int64, mode termdash.ANSIMode) (string, error) {
 	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
 	if err != nil {
-		return "", fmt.Errorf("error reading transcript: %w", err)
+		return "", fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	return termdash.ApplyANSIMode(filterTranscriptRange(data, startMs, endMs), mode), nil
+}
+
+// filterTranscriptRange streams JSONL (or binary) transcript data and
+// concatenates the text of entries whose timestamp falls within
+// [startMs, endMs].
+func filterTranscriptRange(data []byte, startMs, endMs int64) string {
+	if termdash.IsBinaryTranscript(data) {
+		var output strings.Builder
+		for _, entry := range termdash.DecodeTranscriptEntriesBinary(data) {
+			if entry.Timestamp < startMs || entry.Timestamp > endMs {
+				continue
+			}
+			output.WriteString(entry.Text)
+		}
+		return output.String()
 	}
 
-	// Parse JSONL and return cleaned text
-	lines := strings.Split(string(data), "\n")
 	var output strings.Builder
-	for _, line := range lines {
+	for _, line := range strings.Split(string(data), "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
-		// Simple extraction: each line is JSON with "text" field
-		// For performance, do simple string extraction rather than full JSON parse
-		textIdx := strings.Index(line, `"text":"`)
-		if textIdx == -1 {
+		var entry termdash.TranscriptEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
 			continue
 		}
-		start := textIdx + 8
-		end := strings.LastIndex(line, `"`)
-		if end > start {
-			text := line[start:end]
-			// Unescape basic JSON escapes
-			text = strings.ReplaceAll(text, `\"`, `"`)
-			text = strings.ReplaceAll(text, `\\`, `\`)
-			text = strings.ReplaceAll(text, `\n`, "\n")
-			text = strings.ReplaceAll(text, `\t`, "\t")
-			output.WriteString(text)
+		if entry.Timestamp < startMs || entry.Timestamp > endMs {
+			continue
 		}
+		output.WriteString(entry.Text)
 	}
-	return output.String(), nil
+	return output.String()
+}
+
+// GetSessionUsage returns the parsed token/cost usage recorded for a block
+// from its Claude Code exit banner, or nil if the session hasn't exited
+// with a summary yet (e.g. it's still running, or it was killed).
+func (s *TermDashService) GetSessionUsage(ctx context.Context, blockId string) (*termdash.Usage, error) {
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+
+	raw := block.Meta.GetString(waveobj.MetaKey_TermDashUsage, "")
+	if raw == "" {
+		return nil, nil
+	}
+
+	var usage termdash.Usage
+	if err := json.Unmarshal([]byte(raw), &usage); err != nil {
+		return nil, fmt.Errorf("error parsing stored usage: %w", err)
+	}
+	return &usage, nil
 }
 
 // extractSnippet returns a substring centered around the match position.
@@ -112,9 +506,35 @@ func extractSnippet(text string, matchIdx, matchLen, contextLen int) string {
 	if end > len(text) {
 		end = len(text)
 	}
+	start = alignToRuneStart(text, start)
+	end = alignToRuneStart(text, end)
 	snippet := text[start:end]
 	// Clean up for display
+	snippet = stripControlBytes(snippet)
 	snippet = strings.ReplaceAll(snippet, "\n", " ")
 	snippet = strings.Join(strings.Fields(snippet), " ")
 	return snippet
 }
+
+// alignToRuneStart advances idx to the next UTF-8 rune boundary, so slicing
+// text[start:end] never splits a multi-byte rune (e.g. an emoji) in half.
+func alignToRuneStart(text string, idx int) int {
+	for idx > 0 && idx < len(text) && !utf8.RuneStart(text[idx]) {
+		idx++
+	}
+	return idx
+}
+
+// stripControlBytes removes ANSI escape sequences and non-printable control
+// bytes from a snippet, so stray escape sequences that can survive into a
+// snippet near a match don't render as garbage in the UI.
+func stripControlBytes(s string) string {
+	s = termdash.StripAnsi(s)
+	var sb strings.Builder
+	for _, r := range s {
+		if r == '\n' || r == '\t' || r == ' ' || (r >= 0x20 && r != 0x7f) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,312 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestFilterTranscriptRangeIncludesOnlyInRangeEntries(t *testing.T) {
+	data := []byte(
+		`{"ts":1000,"type":"output","text":"before"}` + "\n" +
+			`{"ts":2000,"type":"output","text":"during1"}` + "\n" +
+			`{"ts":2500,"type":"input","text":"during2"}` + "\n" +
+			`{"ts":9000,"type":"output","text":"after"}` + "\n",
+	)
+
+	got := filterTranscriptRange(data, 2000, 3000)
+	want := "during1during2"
+	if got != want {
+		t.Errorf("filterTranscriptRange() = %q, want %q", got, want)
+	}
+}
+
+func TestFilterTranscriptRangeSkipsMalformedLines(t *testing.T) {
+	data := []byte(
+		`{"ts":1000,"type":"output","text":"ok"}` + "\n" +
+			"not json\n" +
+			`{"ts":1500,"type":"output","text":"also ok"}` + "\n",
+	)
+
+	got := filterTranscriptRange(data, 0, 2000)
+	want := "okalso ok"
+	if got != want {
+		t.Errorf("filterTranscriptRange() = %q, want %q", got, want)
+	}
+}
+
+func TestFilterTranscriptRangeHandlesBinaryFormat(t *testing.T) {
+	data := termdash.EncodeTranscriptEntriesBinary([]termdash.TranscriptEntry{
+		{Timestamp: 1000, Type: "output", Text: "before"},
+		{Timestamp: 2000, Type: "output", Text: "during1"},
+		{Timestamp: 2500, Type: "input", Text: "during2"},
+		{Timestamp: 9000, Type: "output", Text: "after"},
+	})
+
+	got := filterTranscriptRange(data, 2000, 3000)
+	want := "during1during2"
+	if got != want {
+		t.Errorf("filterTranscriptRange() = %q, want %q", got, want)
+	}
+}
+
+func TestSearchOffsetLocatesMatchViaTranscriptContext(t *testing.T) {
+	data := []byte(
+		`{"ts":1000,"type":"output","text":"some setup output "}` + "\n" +
+			`{"ts":2000,"type":"output","text":"the needle phrase is here"}` + "\n",
+	)
+
+	// Mirrors what SearchTranscripts does: search the reconstructed text and
+	// record an offset into it.
+	text := reconstructTranscriptText(data)
+	query := "needle phrase"
+	idx := strings.Index(strings.ToLower(text), query)
+	if idx == -1 {
+		t.Fatalf("query %q not found in reconstructed text %q", query, text)
+	}
+
+	snippet, err := transcriptContext(text, idx, 20)
+	if err != nil {
+		t.Fatalf("transcriptContext() error = %v", err)
+	}
+	if !strings.Contains(snippet, "needle phrase") {
+		t.Errorf("transcriptContext() = %q, want it to contain %q", snippet, query)
+	}
+}
+
+func TestTranscriptContextRejectsOutOfRangeOffset(t *testing.T) {
+	if _, err := transcriptContext("short text", 100, 10); err == nil {
+		t.Errorf("transcriptContext() with out-of-range offset expected an error, got nil")
+	}
+}
+
+func TestFindMatchRangesLocatesAllOccurrences(t *testing.T) {
+	text := "the needle is here, another needle is there"
+
+	ranges := findMatchRanges(text, "needle")
+	if len(ranges) != 2 {
+		t.Fatalf("expected 2 matches, got %d: %v", len(ranges), ranges)
+	}
+	for _, r := range ranges {
+		if text[r.Start:r.End] != "needle" {
+			t.Errorf("range %v = %q, want %q", r, text[r.Start:r.End], "needle")
+		}
+	}
+	if ranges[0].Start >= ranges[1].Start {
+		t.Errorf("ranges = %v, want them in ascending order", ranges)
+	}
+}
+
+func TestFindMatchRangesCaseInsensitive(t *testing.T) {
+	ranges := findMatchRanges("Needle in a haystack", "needle")
+	if len(ranges) != 1 || ranges[0].Start != 0 {
+		t.Errorf("findMatchRanges() = %v, want a single match at offset 0", ranges)
+	}
+}
+
+func TestReconstructTranscriptTextHandlesFullJSONEscapeRange(t *testing.T) {
+	data := []byte(
+		`{"ts":1000,"type":"output","text":"line one\nline \"two\"\ttabbed"}` + "\n" +
+			`{"ts":2000,"type":"output","text":"unicode: é中文"}` + "\n" +
+			`{"ts":3000,"type":"output","text":"backslash: \\path\\to\\file"}` + "\n",
+	)
+
+	got := reconstructTranscriptText(data)
+	want := "line one\nline \"two\"\ttabbed" + "unicode: é中文" + `backslash: \path\to\file`
+	if got != want {
+		t.Errorf("reconstructTranscriptText() = %q, want %q", got, want)
+	}
+}
+
+func TestReconstructTranscriptTextLargeSyntheticTranscript(t *testing.T) {
+	var raw bytes.Buffer
+	var want strings.Builder
+	const numEntries = 5000
+	for i := 0; i < numEntries; i++ {
+		text := "entry " + strconv.Itoa(i) + " with a \"quote\" and a\ttab and a newline\n"
+		line, err := json.Marshal(transcriptTextEntry{Text: text})
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		raw.Write(line)
+		raw.WriteByte('\n')
+		want.WriteString(text)
+	}
+
+	got := reconstructTranscriptText(raw.Bytes())
+	if got != want.String() {
+		t.Errorf("reconstructTranscriptText() on a %d-entry synthetic transcript did not match the expected concatenation (got %d bytes, want %d)", numEntries, len(got), want.Len())
+	}
+}
+
+func BenchmarkReconstructTranscriptText(b *testing.B) {
+	var raw bytes.Buffer
+	const numEntries = 2000
+	for i := 0; i < numEntries; i++ {
+		line, _ := json.Marshal(transcriptTextEntry{
+			Text: "some representative output line " + strconv.Itoa(i) + " with \"quotes\" and\ttabs\n",
+		})
+		raw.Write(line)
+		raw.WriteByte('\n')
+	}
+	data := raw.Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reconstructTranscriptText(data)
+	}
+}
+
+func TestFindMatchRangesEmptyQueryReturnsNoMatches(t *testing.T) {
+	if got := findMatchRanges("some text", ""); got != nil {
+		t.Errorf("findMatchRanges() with empty query = %v, want nil", got)
+	}
+}
+
+func TestMatchesScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		archived bool
+		scope    SearchScope
+		want     bool
+	}{
+		{"all includes active", false, SearchScopeAll, true},
+		{"all includes archived", true, SearchScopeAll, true},
+		{"empty scope behaves like all", true, SearchScope(""), true},
+		{"active excludes archived", true, SearchScopeActive, false},
+		{"active includes active", false, SearchScopeActive, true},
+		{"archived excludes active", false, SearchScopeArchived, false},
+		{"archived includes archived", true, SearchScopeArchived, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesScope(tt.archived, tt.scope); got != tt.want {
+				t.Errorf("matchesScope(%v, %q) = %v, want %v", tt.archived, tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamSearchMatchesDeliversResultsIncrementally(t *testing.T) {
+	entries := []searchEntry{
+		{blockId: "block-1", summary: "one", text: "found the needle here"},
+		{blockId: "block-2", summary: "two", text: "no match in this one"},
+		{blockId: "block-3", summary: "three", text: "another needle over here"},
+	}
+
+	var seen []string
+	streamSearchMatches(entries, "needle", func(result TranscriptSearchResult) bool {
+		seen = append(seen, result.BlockId)
+		return true
+	})
+
+	want := []string{"block-1", "block-3"}
+	if len(seen) != len(want) || seen[0] != want[0] || seen[1] != want[1] {
+		t.Errorf("streamSearchMatches() delivered %v, want %v", seen, want)
+	}
+}
+
+func TestStreamSearchMatchesStopsEarlyWhenCallbackReturnsFalse(t *testing.T) {
+	entries := []searchEntry{
+		{blockId: "block-1", text: "needle one"},
+		{blockId: "block-2", text: "needle two"},
+		{blockId: "block-3", text: "needle three"},
+	}
+
+	var seen []string
+	streamSearchMatches(entries, "needle", func(result TranscriptSearchResult) bool {
+		seen = append(seen, result.BlockId)
+		return false
+	})
+
+	if len(seen) != 1 || seen[0] != "block-1" {
+		t.Errorf("streamSearchMatches() with early stop delivered %v, want [block-1]", seen)
+	}
+}
+
+func TestPageSearchMatchesPagesAcrossBlocksWithoutDuplicatesOrGaps(t *testing.T) {
+	entries := []searchEntry{
+		{blockId: "block-1", text: "needle one, needle two"},
+		{blockId: "block-2", text: "no match in this one"},
+		{blockId: "block-3", text: "needle three"},
+		{blockId: "block-4", text: "needle four, needle five, needle six"},
+	}
+
+	var all []TranscriptSearchResult
+	cursor := SearchCursor{}
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("too many pages, cursor likely isn't advancing")
+		}
+		var page []TranscriptSearchResult
+		page, cursor = pageSearchMatches(entries, "needle", cursor, 2)
+		all = append(all, page...)
+		if cursor == (SearchCursor{}) {
+			break
+		}
+	}
+
+	if len(all) != 6 {
+		t.Fatalf("expected 6 total matches across all pages, got %d: %+v", len(all), all)
+	}
+	seen := map[string]bool{}
+	for _, r := range all {
+		key := r.BlockId + ":" + strconv.Itoa(r.Offset)
+		if seen[key] {
+			t.Errorf("duplicate result across pages: %+v", r)
+		}
+		seen[key] = true
+	}
+	wantBlocks := []string{"block-1", "block-1", "block-3", "block-4", "block-4", "block-4"}
+	for i, want := range wantBlocks {
+		if all[i].BlockId != want {
+			t.Errorf("all[%d].BlockId = %q, want %q (order: %+v)", i, all[i].BlockId, want, all)
+		}
+	}
+}
+
+func TestPageSearchMatchesEmptyEntriesReturnsZeroCursor(t *testing.T) {
+	results, next := pageSearchMatches(nil, "needle", SearchCursor{}, 10)
+	if results != nil {
+		t.Errorf("expected no results, got %+v", results)
+	}
+	if next != (SearchCursor{}) {
+		t.Errorf("expected zero cursor, got %+v", next)
+	}
+}
+
+func TestEncodeDecodeCursorRoundTrips(t *testing.T) {
+	c := SearchCursor{BlockId: "block-1", Offset: 42}
+	got := decodeCursor(encodeCursor(c))
+	if got != c {
+		t.Errorf("decodeCursor(encodeCursor(c)) = %+v, want %+v", got, c)
+	}
+}
+
+func TestDecodeCursorMalformedReturnsZeroValue(t *testing.T) {
+	if got := decodeCursor("not-a-cursor"); got != (SearchCursor{}) {
+		t.Errorf("decodeCursor() = %+v, want zero value", got)
+	}
+}
+
+func TestExtractSnippetStripsControlBytesAndAnsi(t *testing.T) {
+	text := "before \x1b[31mfound\x1b[0m \x00\x01needle\x07 after"
+	idx := strings.Index(text, "needle")
+
+	got := extractSnippet(text, idx, len("needle"), 20)
+
+	if strings.ContainsAny(got, "\x1b\x00\x01\x07") {
+		t.Errorf("extractSnippet() = %q, want control bytes and ANSI codes stripped", got)
+	}
+	if !strings.Contains(got, "needle") {
+		t.Errorf("extractSnippet() = %q, want it to still contain the match", got)
+	}
+}
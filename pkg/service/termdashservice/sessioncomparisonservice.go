@@ -0,0 +1,143 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// SessionComparison holds two Claude sessions' analytics side by side, for
+// A/B comparing how each tackled the same task.
+type SessionComparison struct {
+	BlockIdA string `json:"blockida"`
+	BlockIdB string `json:"blockidb"`
+
+	TotalDurationMsA int64 `json:"totaldurationmsa"`
+	TotalDurationMsB int64 `json:"totaldurationmsb"`
+
+	StatusBreakdownMsA map[string]int64 `json:"statusbreakdownmsa"`
+	StatusBreakdownMsB map[string]int64 `json:"statusbreakdownmsb"`
+
+	UsageA *termdash.Usage `json:"usagea,omitempty"`
+	UsageB *termdash.Usage `json:"usageb,omitempty"`
+
+	LearningsA      []string `json:"learningsa"`
+	LearningsB      []string `json:"learningsb"`
+	SharedLearnings []string `json:"sharedlearnings"`
+
+	TouchedFilesA      []string `json:"touchedfilesa"`
+	TouchedFilesB      []string `json:"touchedfilesb"`
+	SharedTouchedFiles []string `json:"sharedtouchedfiles"`
+}
+
+// CompareSessions builds a side-by-side analytics report for two Claude
+// sessions, so a user can see how two attempts at the same task differ in
+// duration, token usage, insights extracted, and files touched. Each
+// session's data is gathered independently and missing pieces (no status
+// history, no learnings yet) are left as zero values rather than failing
+// the whole comparison.
+func (s *TermDashService) CompareSessions(ctx context.Context, blockIdA string, blockIdB string) (*SessionComparison, error) {
+	sideA := s.gatherComparisonSide(ctx, blockIdA)
+	sideB := s.gatherComparisonSide(ctx, blockIdB)
+	return buildSessionComparison(blockIdA, blockIdB, sideA, sideB), nil
+}
+
+// comparisonSide holds the raw per-session data CompareSessions gathers
+// before buildSessionComparison assembles it into the final report.
+type comparisonSide struct {
+	timeline     []termdash.StatusTransition
+	usage        *termdash.Usage
+	learnings    []string
+	touchedFiles []string
+}
+
+// gatherComparisonSide reads one session's status timeline, usage summary,
+// learnings, and touched files, leaving fields at their zero value when a
+// given piece isn't available (e.g. learnings never extracted).
+func (s *TermDashService) gatherComparisonSide(ctx context.Context, blockId string) comparisonSide {
+	var side comparisonSide
+
+	if timeline, err := s.GetStatusTimeline(ctx, blockId); err == nil {
+		side.timeline = timeline
+	}
+
+	if learnings, err := s.GetLearnings(ctx, blockId); err == nil {
+		side.learnings = learnings
+	}
+
+	if _, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript"); err == nil {
+		text := reconstructTranscriptText(data)
+		side.touchedFiles = termdash.ExtractTouchedFiles(text)
+		if usage, ok := termdash.ParseUsageSummary(text); ok {
+			side.usage = usage
+		}
+	}
+
+	return side
+}
+
+// buildSessionComparison assembles a SessionComparison from each session's
+// already-gathered data. Pure and DB/filestore-independent so it can be
+// tested directly.
+func buildSessionComparison(blockIdA, blockIdB string, sideA, sideB comparisonSide) *SessionComparison {
+	breakdownA, totalA := statusBreakdown(sideA.timeline)
+	breakdownB, totalB := statusBreakdown(sideB.timeline)
+
+	return &SessionComparison{
+		BlockIdA: blockIdA,
+		BlockIdB: blockIdB,
+
+		TotalDurationMsA: totalA,
+		TotalDurationMsB: totalB,
+
+		StatusBreakdownMsA: breakdownA,
+		StatusBreakdownMsB: breakdownB,
+
+		UsageA: sideA.usage,
+		UsageB: sideB.usage,
+
+		LearningsA:      sideA.learnings,
+		LearningsB:      sideB.learnings,
+		SharedLearnings: stringOverlap(sideA.learnings, sideB.learnings),
+
+		TouchedFilesA:      sideA.touchedFiles,
+		TouchedFilesB:      sideB.touchedFiles,
+		SharedTouchedFiles: stringOverlap(sideA.touchedFiles, sideB.touchedFiles),
+	}
+}
+
+// statusBreakdown sums a status timeline's segment durations by status,
+// returning both the per-status breakdown and the total duration across all
+// segments.
+func statusBreakdown(timeline []termdash.StatusTransition) (map[string]int64, int64) {
+	breakdown := make(map[string]int64)
+	var total int64
+	for _, t := range timeline {
+		breakdown[t.Status] += t.DurationMs
+		total += t.DurationMs
+	}
+	return breakdown, total
+}
+
+// stringOverlap returns the elements of a that also appear in b, in a's
+// order, deduplicated.
+func stringOverlap(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, x := range b {
+		inB[x] = true
+	}
+
+	var shared []string
+	seen := make(map[string]bool)
+	for _, x := range a {
+		if inB[x] && !seen[x] {
+			seen[x] = true
+			shared = append(shared, x)
+		}
+	}
+	return shared
+}
@@ -0,0 +1,84 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestBuildSessionComparisonPopulatesAllFields(t *testing.T) {
+	sideA := comparisonSide{
+		timeline: []termdash.StatusTransition{
+			{Status: termdash.StatusActive, DurationMs: 1000},
+			{Status: termdash.StatusNeedsInput, DurationMs: 500},
+		},
+		usage:        &termdash.Usage{TokensIn: 100, TokensOut: 200, CostUSD: 0.5},
+		learnings:    []string{"use context timeouts", "prefer sentinel errors"},
+		touchedFiles: []string{"pkg/foo/bar.go", "main.go"},
+	}
+	sideB := comparisonSide{
+		timeline: []termdash.StatusTransition{
+			{Status: termdash.StatusActive, DurationMs: 2000},
+		},
+		usage:        &termdash.Usage{TokensIn: 300, TokensOut: 100, CostUSD: 0.8},
+		learnings:    []string{"prefer sentinel errors", "add table-driven tests"},
+		touchedFiles: []string{"main.go", "pkg/baz/qux.go"},
+	}
+
+	got := buildSessionComparison("block-a", "block-b", sideA, sideB)
+
+	if got.BlockIdA != "block-a" || got.BlockIdB != "block-b" {
+		t.Errorf("BlockIdA/B = %q/%q, want block-a/block-b", got.BlockIdA, got.BlockIdB)
+	}
+	if got.TotalDurationMsA != 1500 || got.TotalDurationMsB != 2000 {
+		t.Errorf("TotalDurationMsA/B = %d/%d, want 1500/2000", got.TotalDurationMsA, got.TotalDurationMsB)
+	}
+	if got.StatusBreakdownMsA[termdash.StatusActive] != 1000 || got.StatusBreakdownMsA[termdash.StatusNeedsInput] != 500 {
+		t.Errorf("StatusBreakdownMsA = %v, want active=1000 needs-input=500", got.StatusBreakdownMsA)
+	}
+	if got.UsageA.CostUSD != 0.5 || got.UsageB.CostUSD != 0.8 {
+		t.Errorf("UsageA/B costs = %v/%v, want 0.5/0.8", got.UsageA.CostUSD, got.UsageB.CostUSD)
+	}
+	if !reflect.DeepEqual(got.SharedLearnings, []string{"prefer sentinel errors"}) {
+		t.Errorf("SharedLearnings = %v, want [prefer sentinel errors]", got.SharedLearnings)
+	}
+	if !reflect.DeepEqual(got.SharedTouchedFiles, []string{"main.go"}) {
+		t.Errorf("SharedTouchedFiles = %v, want [main.go]", got.SharedTouchedFiles)
+	}
+}
+
+func TestBuildSessionComparisonHandlesMissingData(t *testing.T) {
+	got := buildSessionComparison("block-a", "block-b", comparisonSide{}, comparisonSide{})
+
+	if got.TotalDurationMsA != 0 || got.TotalDurationMsB != 0 {
+		t.Errorf("TotalDurationMsA/B = %d/%d, want 0/0 with no timeline data", got.TotalDurationMsA, got.TotalDurationMsB)
+	}
+	if got.UsageA != nil || got.UsageB != nil {
+		t.Errorf("UsageA/B = %v/%v, want nil with no usage data", got.UsageA, got.UsageB)
+	}
+	if len(got.SharedLearnings) != 0 || len(got.SharedTouchedFiles) != 0 {
+		t.Errorf("expected no overlap when both sides are empty, got SharedLearnings=%v SharedTouchedFiles=%v", got.SharedLearnings, got.SharedTouchedFiles)
+	}
+}
+
+func TestStringOverlapDeduplicatesAndPreservesOrder(t *testing.T) {
+	a := []string{"x", "y", "x", "z"}
+	b := []string{"z", "x"}
+
+	got := stringOverlap(a, b)
+	want := []string{"x", "z"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stringOverlap() = %v, want %v", got, want)
+	}
+}
+
+func TestStringOverlapReturnsNilForNoMatches(t *testing.T) {
+	got := stringOverlap([]string{"a"}, []string{"b"})
+	if got != nil {
+		t.Errorf("stringOverlap() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import "sync"
+
+var (
+	sessionCreatedMu    sync.Mutex
+	sessionCreatedHooks []func(blockId string)
+)
+
+// OnSessionCreated registers fn to be called whenever a new Claude block is
+// created. Integrations use this to inject context, start recording, or
+// register observers without the block-creation path needing to know about
+// them. Multiple registrations are all invoked, in registration order.
+func OnSessionCreated(fn func(blockId string)) {
+	sessionCreatedMu.Lock()
+	defer sessionCreatedMu.Unlock()
+	sessionCreatedHooks = append(sessionCreatedHooks, fn)
+}
+
+// FireSessionCreated invokes all hooks registered via OnSessionCreated for
+// blockId. Called from the block-creation path when a new Claude block
+// starts running.
+func FireSessionCreated(blockId string) {
+	sessionCreatedMu.Lock()
+	hooks := make([]func(string), len(sessionCreatedHooks))
+	copy(hooks, sessionCreatedHooks)
+	sessionCreatedMu.Unlock()
+
+	for _, fn := range hooks {
+		fn(blockId)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import "testing"
+
+func TestFireSessionCreatedInvokesRegisteredHook(t *testing.T) {
+	sessionCreatedMu.Lock()
+	sessionCreatedHooks = nil
+	sessionCreatedMu.Unlock()
+
+	var got string
+	OnSessionCreated(func(blockId string) {
+		got = blockId
+	})
+
+	FireSessionCreated("block-123")
+
+	if got != "block-123" {
+		t.Errorf("hook received blockId = %q, want %q", got, "block-123")
+	}
+}
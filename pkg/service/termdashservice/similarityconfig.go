@@ -0,0 +1,40 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+// SimilarityConfig centralizes the similarity thresholds used across this
+// package's dedup/relevance features (session clustering, duplicate
+// detection, and any future feature built on the same term-similarity
+// scoring, e.g. semantic learnings dedup or related-sessions surfacing), so
+// a deployment tunes them consistently in one place instead of several
+// independent knobs.
+type SimilarityConfig struct {
+	// Cluster is the minimum similarity between two sessions' summary/
+	// learnings terms for ClusterSessions to group them together.
+	Cluster float64
+
+	// Duplicate is the minimum similarity between two sessions' terms,
+	// within DuplicateTimeWindow of each other, for FindDuplicateSessions
+	// to flag them as likely duplicates.
+	Duplicate float64
+}
+
+// DefaultSimilarityConfig holds this package's original, independently
+// tuned thresholds.
+var DefaultSimilarityConfig = SimilarityConfig{
+	Cluster:   DefaultClusterSimilarityThreshold,
+	Duplicate: DuplicateSimilarityThreshold,
+}
+
+// ActiveSimilarityConfig is the configuration ClusterSessions and
+// FindDuplicateSessions use. Defaults to DefaultSimilarityConfig.
+var ActiveSimilarityConfig = DefaultSimilarityConfig
+
+// similarity scores how alike two pieces of text are as the Jaccard
+// similarity of their extracted term sets, the same token-overlap scoring
+// clusterSimilarity and duplicateSimilarity already used, now available
+// directly on raw text for callers that haven't already extracted terms.
+func similarity(a, b string) float64 {
+	return jaccardSimilarity(extractTerms(a), extractTerms(b))
+}
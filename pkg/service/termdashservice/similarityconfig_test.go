@@ -0,0 +1,41 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import "testing"
+
+func TestSimilarityIdenticalTextScoresOne(t *testing.T) {
+	got := similarity("fix the flaky database migration test", "fix the flaky database migration test")
+	if got != 1 {
+		t.Errorf("similarity(identical) = %v, want 1", got)
+	}
+}
+
+func TestSimilarityParaphrasedTextScoresHigherThanUnrelated(t *testing.T) {
+	original := "refactor the auth middleware to fix session token storage"
+	paraphrased := "refactor auth middleware to fix session token storage issues"
+	unrelated := "prefer early returns over deeply nested conditionals"
+
+	paraphrasedScore := similarity(original, paraphrased)
+	unrelatedScore := similarity(original, unrelated)
+
+	if paraphrasedScore < 0.5 {
+		t.Errorf("similarity(paraphrased) = %v, want a high score (>= 0.5)", paraphrasedScore)
+	}
+	if unrelatedScore > 0.2 {
+		t.Errorf("similarity(unrelated) = %v, want a low score (<= 0.2)", unrelatedScore)
+	}
+	if paraphrasedScore <= unrelatedScore {
+		t.Errorf("paraphrased score %v should be greater than unrelated score %v", paraphrasedScore, unrelatedScore)
+	}
+}
+
+func TestSimilarityEmptyStringsScoreZero(t *testing.T) {
+	if got := similarity("", "some text"); got != 0 {
+		t.Errorf("similarity(\"\", text) = %v, want 0", got)
+	}
+	if got := similarity("", ""); got != 0 {
+		t.Errorf("similarity(\"\", \"\") = %v, want 0", got)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+var (
+	statusChangedMu    sync.Mutex
+	statusChangedHooks []func(blockId, oldStatus, newStatus string)
+)
+
+// OnStatusChanged registers fn to be called whenever a status change from a
+// detector attached via AttachDetector has been persisted to block meta.
+// Multiple registrations are all invoked, in registration order.
+func OnStatusChanged(fn func(blockId, oldStatus, newStatus string)) {
+	statusChangedMu.Lock()
+	defer statusChangedMu.Unlock()
+	statusChangedHooks = append(statusChangedHooks, fn)
+}
+
+// fireStatusChanged delivers a status change to hooks registered via
+// OnStatusChanged, unless newStatus is attention-worthy and an active
+// quiet-hours window (see SetQuietHours) is suppressing it, in which case
+// it's queued for later delivery instead. See FlushQuietHoursQueue.
+func fireStatusChanged(blockId, oldStatus, newStatus string) {
+	FlushQuietHoursQueue()
+
+	quietHoursMu.Lock()
+	suppress := isAttentionStatus(newStatus) && inQuietHours(quietHoursCfg, nowFn())
+	if suppress {
+		quietHoursQueue = append(quietHoursQueue, AttentionNotice{
+			BlockId:   blockId,
+			OldStatus: oldStatus,
+			NewStatus: newStatus,
+			At:        nowFn(),
+		})
+	}
+	quietHoursMu.Unlock()
+
+	if suppress {
+		return
+	}
+	deliverStatusChanged(blockId, oldStatus, newStatus)
+}
+
+// deliverStatusChanged invokes all hooks registered via OnStatusChanged.
+func deliverStatusChanged(blockId, oldStatus, newStatus string) {
+	statusChangedMu.Lock()
+	hooks := make([]func(string, string, string), len(statusChangedHooks))
+	copy(hooks, statusChangedHooks)
+	statusChangedMu.Unlock()
+
+	for _, fn := range hooks {
+		fn(blockId, oldStatus, newStatus)
+	}
+}
+
+// persistStatusFn writes a block's detected status to meta. A package
+// variable so tests can substitute a fake and verify AttachDetector's
+// persistence without a real DB-backed block.
+var persistStatusFn = defaultPersistStatus
+
+func defaultPersistStatus(ctx context.Context, blockId string, status string) error {
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashStatus: status,
+	}
+	return wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, blockId), metaUpdate, false)
+}
+
+// AttachDetector creates a StatusDetector for blockId whose callback
+// persists every status change to MetaKey_TermDashStatus and fires any
+// hooks registered via OnStatusChanged, before invoking callback (which may
+// be nil) with any additional caller-specific handling. Persisting status
+// here, rather than leaving it to each caller, removes a whole class of
+// integration bugs where a caller forgets to persist and the summary
+// loop's status filtering silently breaks.
+//
+// AttachDetector is idempotent: if a detector is already registered for
+// blockId, it's returned as-is and callback/opts are ignored, so a caller
+// that reattaches (e.g. across a shell restart) without first calling
+// DetachDetector doesn't end up running two detectors against the same
+// block.
+func AttachDetector(blockId string, callback termdash.StatusChangeCallback, opts ...termdash.StatusDetectorOption) *termdash.StatusDetector {
+	detectorRegistryMu.Lock()
+	defer detectorRegistryMu.Unlock()
+	if existing, ok := detectorRegistry[blockId]; ok {
+		return existing
+	}
+	sd := termdash.NewStatusDetector(func(oldStatus, newStatus string) {
+		ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancelFn()
+		if err := persistStatusFn(ctx, blockId, newStatus); err != nil {
+			log.Printf("[termdash] error persisting status for block %s: %v\n", blockId, err)
+		}
+		fireStatusChanged(blockId, oldStatus, newStatus)
+		if callback != nil {
+			callback(oldStatus, newStatus)
+		}
+	}, opts...)
+	detectorRegistry[blockId] = sd
+	return sd
+}
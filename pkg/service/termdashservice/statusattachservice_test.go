@@ -0,0 +1,108 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestOnStatusChangedInvokesRegisteredHook(t *testing.T) {
+	statusChangedMu.Lock()
+	statusChangedHooks = nil
+	statusChangedMu.Unlock()
+
+	var got string
+	OnStatusChanged(func(blockId, oldStatus, newStatus string) {
+		got = blockId + ":" + oldStatus + "->" + newStatus
+	})
+
+	fireStatusChanged("block-123", termdash.StatusActive, termdash.StatusNeedsInput)
+
+	want := "block-123:active->needs-input"
+	if got != want {
+		t.Errorf("hook received %q, want %q", got, want)
+	}
+}
+
+func TestAttachDetectorPersistsStatusChange(t *testing.T) {
+	origPersist := persistStatusFn
+	defer func() { persistStatusFn = origPersist }()
+	statusChangedMu.Lock()
+	statusChangedHooks = nil
+	statusChangedMu.Unlock()
+
+	var mu sync.Mutex
+	var persisted []string
+	persistStatusFn = func(ctx context.Context, blockId string, status string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		persisted = append(persisted, blockId+":"+status)
+		return nil
+	}
+
+	sd := AttachDetector("block-1", nil)
+	defer sd.Stop()
+
+	sd.ProcessOutput([]byte("\n\xe2\x9d\xaf ")) // "❯ ", a strong prompt pattern
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(persisted)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := "block-1:" + termdash.StatusNeedsInput
+	if len(persisted) == 0 || persisted[0] != want {
+		t.Errorf("persisted = %v, want [%q]", persisted, want)
+	}
+}
+
+func TestAttachDetectorInvokesCallbackAfterPersisting(t *testing.T) {
+	origPersist := persistStatusFn
+	defer func() { persistStatusFn = origPersist }()
+
+	var order []string
+	var mu sync.Mutex
+	persistStatusFn = func(ctx context.Context, blockId string, status string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, "persist")
+		return nil
+	}
+
+	done := make(chan struct{})
+	sd := AttachDetector("block-2", func(oldStatus, newStatus string) {
+		mu.Lock()
+		order = append(order, "callback")
+		mu.Unlock()
+		close(done)
+	})
+	defer sd.Stop()
+
+	sd.ProcessOutput([]byte("\n\xe2\x9d\xaf "))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for callback")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 2 || order[0] != "persist" || order[1] != "callback" {
+		t.Errorf("order = %v, want [persist callback]", order)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// statusInferenceLines is how many trailing transcript lines
+// InferStatusFromTranscript classifies to seed a detector's initial status.
+const statusInferenceLines = 3
+
+// InferStatusFromTranscript reads a block's transcript and classifies its
+// tail to seed a freshly created StatusDetector with the correct status,
+// instead of starting in the unknown (empty) status. This makes a session
+// reattached after an app restart show the correct state immediately.
+func (s *TermDashService) InferStatusFromTranscript(ctx context.Context, blockId string) (string, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	text := reconstructTranscriptText(data)
+	return termdash.ClassifyPromptStatus(lastNLines(text, statusInferenceLines)), nil
+}
+
+// lastNLines returns the last n lines of text, joined back with newlines.
+func lastNLines(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) <= n {
+		return text
+	}
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
+
+// StatusHistoryFile is the filestore file each status change is appended to,
+// powering GetStatusTimeline.
+const StatusHistoryFile = "termdash:statushistory"
+
+// GetStatusTimeline returns the chronological list of status transitions
+// recorded for a block's Claude session, each annotated with how long that
+// segment lasted. The final segment is marked ongoing since the session
+// hasn't left that status yet.
+func (s *TermDashService) GetStatusTimeline(ctx context.Context, blockId string) ([]termdash.StatusTransition, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, StatusHistoryFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoStatusHistory, err)
+	}
+	entries := termdash.ParseStatusHistory(data)
+	return termdash.BuildStatusTimeline(entries, time.Now().UnixMilli()), nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestLastNLinesReturnsTrailingLinesOnly(t *testing.T) {
+	got := lastNLines("one\ntwo\nthree\nfour", 2)
+	want := "three\nfour"
+	if got != want {
+		t.Errorf("lastNLines() = %q, want %q", got, want)
+	}
+}
+
+func TestInferStatusFromTranscriptSeedsNeedsInputOnPromptTail(t *testing.T) {
+	data := []byte(
+		`{"ts":1000,"type":"output","text":"Building project..."}` + "\n" +
+			`{"ts":2000,"type":"output","text":"Do you want to proceed?"}` + "\n",
+	)
+
+	text := reconstructTranscriptText(data)
+	got := termdash.ClassifyPromptStatus(lastNLines(text, statusInferenceLines))
+	if got != termdash.StatusNeedsInput {
+		t.Errorf("ClassifyPromptStatus(lastNLines(...)) = %q, want %q", got, termdash.StatusNeedsInput)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// extractSubagentTasks pulls every Type: "task" entry out of entries and
+// returns it as a termdash.TaskInfo, in transcript order. Split out from
+// GetSubagentTasks so it's testable without a filestore-backed transcript.
+func extractSubagentTasks(entries []termdash.TranscriptEntry) []termdash.TaskInfo {
+	var tasks []termdash.TaskInfo
+	for _, entry := range entries {
+		if entry.Type != "task" {
+			continue
+		}
+		tasks = append(tasks, termdash.TaskInfo{Description: entry.Text, Timestamp: entry.Timestamp})
+	}
+	return tasks
+}
+
+// GetSubagentTasks returns every Claude subagent/task invocation recorded in
+// a block's transcript, in the order Claude spawned them, so a UI can let a
+// user follow a complex multi-agent session's task boundaries.
+func (s *TermDashService) GetSubagentTasks(ctx context.Context, blockId string) ([]termdash.TaskInfo, error) {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoTranscript, err)
+	}
+	return extractSubagentTasks(termdash.DecodeTranscriptEntries(data)), nil
+}
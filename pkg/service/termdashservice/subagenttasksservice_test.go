@@ -0,0 +1,42 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestExtractSubagentTasksReturnsOnlyTaskEntries(t *testing.T) {
+	entries := []termdash.TranscriptEntry{
+		{Timestamp: 1000, Type: "output", Text: "⏺ Task(Run the test suite)"},
+		{Timestamp: 1000, Type: "task", Text: "Run the test suite"},
+		{Timestamp: 2000, Type: "output", Text: "some other output"},
+		{Timestamp: 3000, Type: "task", Text: "Fix the lint errors"},
+	}
+
+	got := extractSubagentTasks(entries)
+	want := []termdash.TaskInfo{
+		{Description: "Run the test suite", Timestamp: 1000},
+		{Description: "Fix the lint errors", Timestamp: 3000},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("extractSubagentTasks() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("extractSubagentTasks()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExtractSubagentTasksNoTaskEntriesReturnsNil(t *testing.T) {
+	entries := []termdash.TranscriptEntry{
+		{Timestamp: 1000, Type: "output", Text: "just regular output"},
+	}
+	if got := extractSubagentTasks(entries); got != nil {
+		t.Errorf("extractSubagentTasks() = %v, want nil", got)
+	}
+}
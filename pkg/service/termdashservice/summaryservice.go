@@ -5,11 +5,13 @@ package termdashservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os/exec"
-	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/wavetermdev/waveterm/pkg/filestore"
@@ -17,6 +19,7 @@ import (
 	"github.com/wavetermdev/waveterm/pkg/termdash"
 	"github.com/wavetermdev/waveterm/pkg/wavebase"
 	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wconfig"
 	"github.com/wavetermdev/waveterm/pkg/wstore"
 )
 
@@ -25,27 +28,160 @@ const (
 	SummaryStartDelay   = 5 * time.Second
 	MaxTermOutputBytes  = 4096
 	SummaryTimeout      = 10 * time.Second
+
+	DefaultMinSummaryOutputBytes = 50
 )
 
-var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]|\x1b\][^\x07]*\x07|\x1b\[[0-9;]*m`)
+// MinSummaryOutputBytes is the minimum amount of cleaned terminal output
+// required before a summary/title is generated for a block. Sessions with
+// less output than this are skipped as not yet meaningful. Configurable
+// so deployments with very short or very chatty sessions can tune it.
+var MinSummaryOutputBytes = DefaultMinSummaryOutputBytes
+
+// SummaryLanguage, when set to a code in summaryLanguageNames, causes
+// generated titles to be requested in that language instead of the default
+// English. Unset or unrecognized codes are ignored, leaving the prompt
+// unchanged.
+var SummaryLanguage = ""
+
+// summaryLanguageNames is the allow-list of locale codes SummaryLanguage
+// accepts, mapped to the language name used in the prompt instruction.
+var summaryLanguageNames = map[string]string{
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"ja": "Japanese",
+	"pt": "Portuguese",
+	"zh": "Chinese",
+}
+
+// summaryLanguageInstruction returns a prompt suffix requesting the title in
+// SummaryLanguage's language, or "" when SummaryLanguage is unset or not in
+// the allow-list.
+func summaryLanguageInstruction() string {
+	name, ok := summaryLanguageNames[strings.ToLower(SummaryLanguage)]
+	if !ok {
+		return ""
+	}
+	return " Respond in " + name + "."
+}
+
+// validSummaryStatuses is the allow-list of statuses SummaryEligibleStatuses
+// may contain.
+var validSummaryStatuses = map[string]bool{
+	termdash.StatusActive:     true,
+	termdash.StatusNeedsInput: true,
+	termdash.StatusIdle:       true,
+	termdash.StatusExited:     true,
+}
+
+// DefaultSummaryEligibleStatuses is the set of statuses pollClaudeBlocks
+// generates summaries for by default: sessions currently doing something or
+// waiting on the user, but not idle or exited ones.
+var DefaultSummaryEligibleStatuses = []string{termdash.StatusActive, termdash.StatusNeedsInput}
+
+// SummaryEligibleStatuses controls which session statuses pollClaudeBlocks
+// generates a title for. Defaults to DefaultSummaryEligibleStatuses; set to
+// include termdash.StatusIdle to also title sessions the user has paused on
+// but consider meaningful. Unrecognized statuses are ignored.
+var SummaryEligibleStatuses = DefaultSummaryEligibleStatuses
+
+// isSummaryEligibleStatus reports whether status is in SummaryEligibleStatuses.
+func isSummaryEligibleStatus(status string) bool {
+	for _, eligible := range SummaryEligibleStatuses {
+		if !validSummaryStatuses[eligible] {
+			continue
+		}
+		if status == eligible {
+			return true
+		}
+	}
+	return false
+}
+
+// backgroundProcessingPaused gates all automatic (poll-loop-driven) claude
+// CLI activity. It does not affect on-demand calls made directly through
+// the service API (e.g. a user explicitly requesting a summary).
+var backgroundProcessingPaused atomic.Bool
+
+// PauseBackgroundProcessing stops the summary poll loop from generating any
+// new titles until ResumeBackgroundProcessing is called. Useful on
+// constrained machines or during demos to halt background claude CLI
+// activity without restarting the app.
+func PauseBackgroundProcessing() {
+	backgroundProcessingPaused.Store(true)
+}
+
+// ResumeBackgroundProcessing re-enables automatic summary generation
+// stopped by PauseBackgroundProcessing.
+func ResumeBackgroundProcessing() {
+	backgroundProcessingPaused.Store(false)
+}
+
+// IsBackgroundProcessingPaused reports whether automatic summary generation
+// is currently paused.
+func IsBackgroundProcessingPaused() bool {
+	return backgroundProcessingPaused.Load()
+}
 
 // StartSummaryLoop starts the background polling loop that generates
-// titles for active Claude Code sessions.
+// titles for active Claude Code sessions. The poll interval and start delay
+// are read from settings.json (termdash:summarypollms and
+// termdash:summarystartdelayms), falling back to SummaryPollInterval and
+// SummaryStartDelay when unset. A configured poll interval of 0 disables
+// polling entirely, leaving summaries to be generated on demand only.
 func StartSummaryLoop() {
 	go func() {
 		defer func() {
 			panichandler.PanicHandler("termdash:summaryLoop", recover())
 		}()
-		time.Sleep(SummaryStartDelay)
+		time.Sleep(summaryStartDelay())
 		for {
+			interval := summaryPollInterval()
+			if interval <= 0 {
+				return
+			}
 			pollClaudeBlocks()
-			time.Sleep(SummaryPollInterval)
+			time.Sleep(interval)
 		}
 	}()
 }
 
+// summaryPollInterval returns the configured summary poll interval, or
+// SummaryPollInterval if unset. A configured value of 0 means "disabled"
+// and is returned as-is; the caller treats a non-positive interval as a
+// signal to stop polling. Read fresh on every loop iteration so a config
+// change takes effect without a restart.
+func summaryPollInterval() time.Duration {
+	settings := wconfig.GetWatcher().GetFullConfig().Settings
+	return resolveConfiguredDuration(settings.TermDashSummaryPollMs, SummaryPollInterval)
+}
+
+// summaryStartDelay returns the configured summary loop start delay, or
+// SummaryStartDelay if unset.
+func summaryStartDelay() time.Duration {
+	settings := wconfig.GetWatcher().GetFullConfig().Settings
+	return resolveConfiguredDuration(settings.TermDashSummaryStartDelayMs, SummaryStartDelay)
+}
+
+// resolveConfiguredDuration converts an optional millisecond setting into a
+// duration, falling back to fallback when unset. A configured value of 0 is
+// returned as a zero duration rather than being treated as unset, so callers
+// can distinguish "disabled" from "use the default".
+func resolveConfiguredDuration(configuredMs *float64, fallback time.Duration) time.Duration {
+	if configuredMs == nil {
+		return fallback
+	}
+	return time.Duration(*configuredMs) * time.Millisecond
+}
+
 // pollClaudeBlocks finds all Claude blocks that need a summary generated.
 func pollClaudeBlocks() {
+	recordPollCycle()
+	if IsBackgroundProcessingPaused() {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -56,30 +192,189 @@ func pollClaudeBlocks() {
 	}
 
 	for _, block := range blocks {
-		tdType := block.Meta.GetString(waveobj.MetaKey_TermDashType, "")
-		if tdType != "claude" {
+		if !blockNeedsGeneratedSummary(block.Meta) {
 			continue
 		}
+		go generateSummary(block.OID)
+	}
 
-		// Skip archived blocks
-		if block.Meta.GetBool(waveobj.MetaKey_TermDashArchived, false) {
-			continue
-		}
+	pollAutoLearnings(ctx, blocks)
+}
 
-		// Skip blocks that already have a summary
-		existing := block.Meta.GetString(waveobj.MetaKey_TermDashSummary, "")
-		if existing != "" {
+// blockNeedsGeneratedSummary reports whether a block is due for an
+// automatically generated summary: a Claude block, not archived, without a
+// manually-set title, without an existing summary, and in a
+// summary-eligible status.
+func blockNeedsGeneratedSummary(meta waveobj.MetaMapType) bool {
+	if meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+		return false
+	}
+	if meta.GetBool(waveobj.MetaKey_TermDashArchived, false) {
+		return false
+	}
+	if meta.GetBool(waveobj.MetaKey_TermDashSummaryManual, false) {
+		return false
+	}
+	if meta.GetString(waveobj.MetaKey_TermDashSummary, "") != "" {
+		return false
+	}
+	return isSummaryEligibleStatus(meta.GetString(waveobj.MetaKey_TermDashStatus, ""))
+}
+
+var (
+	summaryGeneratedMu    sync.Mutex
+	summaryGeneratedHooks []func(blockId, title string)
+)
+
+// OnSummaryGenerated registers fn to be called whenever generateSummary
+// successfully writes a generated title to a block's meta. Integrations use
+// this to react to new titles (e.g. updating a search index or notifying the
+// UI) without generateSummary needing to know about them. Multiple
+// registrations are all invoked, in registration order.
+func OnSummaryGenerated(fn func(blockId, title string)) {
+	summaryGeneratedMu.Lock()
+	defer summaryGeneratedMu.Unlock()
+	summaryGeneratedHooks = append(summaryGeneratedHooks, fn)
+}
+
+// fireSummaryGenerated invokes all hooks registered via OnSummaryGenerated.
+func fireSummaryGenerated(blockId, title string) {
+	summaryGeneratedMu.Lock()
+	hooks := make([]func(string, string), len(summaryGeneratedHooks))
+	copy(hooks, summaryGeneratedHooks)
+	summaryGeneratedMu.Unlock()
+
+	for _, fn := range hooks {
+		fn(blockId, title)
+	}
+}
+
+// MaxManualSummaryLength is the maximum length, in runes, of a user-provided
+// title accepted by SetSummary. Longer titles are rejected rather than
+// silently truncated, since (unlike a generated title) the user should know
+// their exact wording wasn't saved.
+const MaxManualSummaryLength = 200
+
+// SetSummary stores a user-provided title for a block and marks it manual,
+// so pollClaudeBlocks and RegenerateAllSummaries never overwrite it with a
+// generated one.
+func (s *TermDashService) SetSummary(ctx context.Context, blockId string, title string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("title cannot be empty")
+	}
+	if len([]rune(title)) > MaxManualSummaryLength {
+		return fmt.Errorf("title cannot exceed %d characters", MaxManualSummaryLength)
+	}
+
+	metaUpdate := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashSummary:       title,
+		waveobj.MetaKey_TermDashSummaryManual: true,
+	}
+	err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, blockId), metaUpdate, false)
+	if errors.Is(err, wstore.ErrNotFound) {
+		return fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return err
+}
+
+// RegenerateAllSummaries clears the stored summary for every non-archived
+// Claude block so pollClaudeBlocks generates a fresh one on its next pass,
+// skipping blocks with a manually-set title unless force is true.
+func (s *TermDashService) RegenerateAllSummaries(ctx context.Context, force bool) error {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	for _, block := range blocks {
+		if !blockEligibleForRegeneration(block.Meta, force) {
 			continue
 		}
 
-		// Only generate summaries for active/needs-input sessions (not idle/exited)
-		status := block.Meta.GetString(waveobj.MetaKey_TermDashStatus, "")
-		if status != termdash.StatusActive && status != termdash.StatusNeedsInput {
-			continue
+		metaUpdate := waveobj.MetaMapType{
+			waveobj.MetaKey_TermDashSummary:       nil,
+			waveobj.MetaKey_TermDashSummaryManual: nil,
 		}
+		err := wstore.UpdateObjectMeta(ctx, waveobj.MakeORef(waveobj.OType_Block, block.OID), metaUpdate, false)
+		if err != nil {
+			log.Printf("[termdash:summary] error clearing summary for block %s: %v\n", block.OID, err)
+		}
+	}
+	return nil
+}
 
-		go generateSummary(block.OID)
+// blockEligibleForRegeneration reports whether a block's summary should be
+// cleared by RegenerateAllSummaries: a non-archived Claude block, and
+// (unless force is true) one without a manually-set title.
+func blockEligibleForRegeneration(meta waveobj.MetaMapType, force bool) bool {
+	if meta.GetString(waveobj.MetaKey_TermDashType, "") != "claude" {
+		return false
+	}
+	if meta.GetBool(waveobj.MetaKey_TermDashArchived, false) {
+		return false
+	}
+	if !force && meta.GetBool(waveobj.MetaKey_TermDashSummaryManual, false) {
+		return false
 	}
+	return true
+}
+
+// DefaultSummaryRegenerationCooldown is the minimum time between on-demand
+// summary generation attempts for a single block, enforced by
+// GetOrGenerateSummary.
+const DefaultSummaryRegenerationCooldown = 30 * time.Second
+
+// SummaryRegenerationCooldown controls the cooldown enforced by
+// GetOrGenerateSummary. Defaults to DefaultSummaryRegenerationCooldown.
+var SummaryRegenerationCooldown = DefaultSummaryRegenerationCooldown
+
+var (
+	lastSummaryGenMu sync.Mutex
+	lastSummaryGenAt = map[string]time.Time{}
+)
+
+// allowSummaryGenerationLocked reports whether enough time has passed since
+// the last recorded generation attempt for blockId to allow another as of
+// now, recording now as the new attempt time when it does. blockId's last
+// attempt lives in a package-level map (not the block's own meta) since a
+// cooldown miss shouldn't itself count as a durable, DB-persisted fact.
+func allowSummaryGenerationLocked(blockId string, now time.Time) bool {
+	lastSummaryGenMu.Lock()
+	defer lastSummaryGenMu.Unlock()
+	if last, ok := lastSummaryGenAt[blockId]; ok && now.Sub(last) < SummaryRegenerationCooldown {
+		return false
+	}
+	lastSummaryGenAt[blockId] = now
+	return true
+}
+
+// GetOrGenerateSummary returns a block's existing summary if one is already
+// stored, or generates one on demand otherwise. Generation attempts are
+// subject to SummaryRegenerationCooldown per block, so a UI that calls this
+// repeatedly (e.g. on every render before a summary exists) can't spawn a
+// new claude CLI invocation for each call; a call inside the cooldown
+// returns ErrSummaryCooldown instead.
+func (s *TermDashService) GetOrGenerateSummary(ctx context.Context, blockId string) (string, error) {
+	block, err := wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	if summary := block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""); summary != "" {
+		return summary, nil
+	}
+
+	if !allowSummaryGenerationLocked(blockId, time.Now()) {
+		return "", ErrSummaryCooldown
+	}
+
+	generateSummary(blockId)
+
+	block, err = wstore.DBMustGet[*waveobj.Block](ctx, blockId)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBlockNotFound, err)
+	}
+	return block.Meta.GetString(waveobj.MetaKey_TermDashSummary, ""), nil
 }
 
 // generateSummary reads terminal output from a block and generates a title.
@@ -93,20 +388,28 @@ func generateSummary(blockId string) {
 
 	// Read terminal output from block file store
 	termOutput, err := readTerminalOutput(ctx, blockId)
+	if err == errInsufficientOutput {
+		// Brand new block with nothing written yet; nothing to log or generate.
+		return
+	}
 	if err != nil {
 		log.Printf("[termdash:summary] error reading terminal output for block %s: %v\n", blockId, err)
+		recordSummaryOutcome(err)
 		return
 	}
 
-	if len(termOutput) < 50 {
+	if len(termOutput) < MinSummaryOutputBytes {
 		// Not enough output to generate a meaningful title
 		return
 	}
 
-	// Generate title using claude CLI in non-interactive mode
-	title, err := generateTitle(ctx, termOutput)
+	// Generate title using the configured TitleGenerator (claude CLI by
+	// default, or a heuristic generator when TitleGeneratorMode is set to
+	// "heuristic")
+	title, err := activeTitleGenerator().GenerateTitle(ctx, termOutput)
 	if err != nil {
 		log.Printf("[termdash:summary] error generating title for block %s: %v\n", blockId, err)
+		recordSummaryOutcome(err)
 		return
 	}
 
@@ -123,12 +426,19 @@ func generateSummary(blockId string) {
 	err = wstore.UpdateObjectMeta(updateCtx, waveobj.MakeORef(waveobj.OType_Block, blockId), metaUpdate, false)
 	if err != nil {
 		log.Printf("[termdash:summary] error saving summary for block %s: %v\n", blockId, err)
+		recordSummaryOutcome(err)
 		return
 	}
 
+	recordSummaryOutcome(nil)
+	fireSummaryGenerated(blockId, title)
 	log.Printf("[termdash:summary] generated title for block %s: %q\n", blockId, title)
 }
 
+// errInsufficientOutput signals that a block has no (or too little)
+// terminal output yet to bother reading, e.g. a brand new block.
+var errInsufficientOutput = errors.New("insufficient terminal output")
+
 // readTerminalOutput reads the last N bytes from a block's terminal file,
 // strips ANSI codes, and returns clean text.
 func readTerminalOutput(ctx context.Context, blockId string) (string, error) {
@@ -137,6 +447,9 @@ func readTerminalOutput(ctx context.Context, blockId string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("stat error: %w", err)
 	}
+	if wfile.Size <= 0 {
+		return "", errInsufficientOutput
+	}
 
 	readSize := int64(MaxTermOutputBytes)
 	offset := int64(0)
@@ -152,29 +465,38 @@ func readTerminalOutput(ctx context.Context, blockId string) (string, error) {
 	}
 
 	// Strip ANSI escape codes
-	cleaned := ansiRegex.ReplaceAllString(string(data), "")
+	cleaned := termdash.StripAnsi(string(data))
 	// Collapse whitespace
 	cleaned = strings.Join(strings.Fields(cleaned), " ")
 	return cleaned, nil
 }
 
-// generateTitle calls claude CLI in print mode to generate a concise session title.
-func generateTitle(ctx context.Context, termOutput string) (string, error) {
+// buildTitlePrompt constructs the prompt sent to the claude CLI to generate
+// a session title, appending a language instruction when SummaryLanguage is
+// configured.
+func buildTitlePrompt(termOutput string) string {
 	// Truncate for the prompt if needed
 	if len(termOutput) > 2000 {
 		termOutput = termOutput[:2000]
 	}
 
-	prompt := fmt.Sprintf(
-		"Generate a concise 3-8 word title for this Claude Code terminal session based on the output below. "+
+	return fmt.Sprintf(
+		"Generate a concise 3-8 word title for this Claude Code terminal session based on the output below.%s "+
 			"Return ONLY the title, no quotes, no explanation.\n\n%s",
-		termOutput,
+		summaryLanguageInstruction(), termOutput,
 	)
+}
 
+// generateTitle calls claude CLI in print mode to generate a concise session title.
+func generateTitle(ctx context.Context, termOutput string) (string, error) {
 	cmd := exec.CommandContext(ctx, "claude", "-p", "--model", "haiku")
-	cmd.Stdin = strings.NewReader(prompt)
+	cmd.Stdin = strings.NewReader(buildTitlePrompt(termOutput))
 
 	output, err := cmd.Output()
+	recordCLIOutcome(err)
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", fmt.Errorf("%w: %v", ErrGeneratorUnavailable, err)
+	}
 	if err != nil {
 		return "", fmt.Errorf("claude command error: %w", err)
 	}
@@ -187,10 +509,5 @@ func generateTitle(ctx context.Context, termOutput string) (string, error) {
 		title = strings.TrimSpace(lines[0])
 	}
 
-	// Cap at reasonable length
-	if len(title) > 80 {
-		title = title[:80]
-	}
-
-	return title, nil
+	return truncateTitle(title), nil
 }
@@ -0,0 +1,227 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+)
+
+func TestResolveConfiguredDurationUnsetUsesFallback(t *testing.T) {
+	got := resolveConfiguredDuration(nil, SummaryPollInterval)
+	if got != SummaryPollInterval {
+		t.Errorf("resolveConfiguredDuration(nil) = %v, want %v", got, SummaryPollInterval)
+	}
+}
+
+func TestResolveConfiguredDurationZeroDisables(t *testing.T) {
+	zero := 0.0
+	got := resolveConfiguredDuration(&zero, SummaryPollInterval)
+	if got != 0 {
+		t.Errorf("resolveConfiguredDuration(0) = %v, want 0", got)
+	}
+}
+
+func TestResolveConfiguredDurationUsesConfiguredValue(t *testing.T) {
+	ms := 2500.0
+	got := resolveConfiguredDuration(&ms, SummaryPollInterval)
+	want := 2500 * time.Millisecond
+	if got != want {
+		t.Errorf("resolveConfiguredDuration(2500) = %v, want %v", got, want)
+	}
+}
+
+func TestPollClaudeBlocksSkipsAllGenerationWhilePaused(t *testing.T) {
+	PauseBackgroundProcessing()
+	defer ResumeBackgroundProcessing()
+
+	if !IsBackgroundProcessingPaused() {
+		t.Fatalf("IsBackgroundProcessingPaused() = false after PauseBackgroundProcessing()")
+	}
+
+	// pollClaudeBlocks would otherwise query the object store, which isn't
+	// configured in this test's environment; reaching that call without
+	// paused short-circuiting first would panic/fail this test.
+	pollClaudeBlocks()
+}
+
+func TestBuildTitlePromptIncludesLanguageInstructionWhenConfigured(t *testing.T) {
+	SummaryLanguage = "es"
+	defer func() { SummaryLanguage = "" }()
+
+	prompt := buildTitlePrompt("some session output")
+	if !strings.Contains(prompt, "Respond in Spanish.") {
+		t.Errorf("buildTitlePrompt() = %q, want it to contain the Spanish language instruction", prompt)
+	}
+}
+
+func TestBuildTitlePromptOmitsInstructionByDefault(t *testing.T) {
+	prompt := buildTitlePrompt("some session output")
+	if strings.Contains(prompt, "Respond in") {
+		t.Errorf("buildTitlePrompt() = %q, want no language instruction by default", prompt)
+	}
+}
+
+func TestBuildTitlePromptIgnoresUnknownLanguageCode(t *testing.T) {
+	SummaryLanguage = "xx"
+	defer func() { SummaryLanguage = "" }()
+
+	prompt := buildTitlePrompt("some session output")
+	if strings.Contains(prompt, "Respond in") {
+		t.Errorf("buildTitlePrompt() = %q, want no language instruction for an unrecognized code", prompt)
+	}
+}
+
+func TestTruncateTitlePreservesNonASCIIRunes(t *testing.T) {
+	title := strings.Repeat("日", 90)
+	got := truncateTitle(title)
+	if got != strings.Repeat("日", 80) {
+		t.Errorf("truncateTitle() truncated mid-rune or to the wrong length, got %d runes", len([]rune(got)))
+	}
+}
+
+func TestIsSummaryEligibleStatusDefaultsExcludeIdle(t *testing.T) {
+	if isSummaryEligibleStatus(termdash.StatusIdle) {
+		t.Error("idle should not be eligible by default")
+	}
+	if !isSummaryEligibleStatus(termdash.StatusActive) {
+		t.Error("active should be eligible by default")
+	}
+}
+
+func TestIsSummaryEligibleStatusHonorsConfiguredIdle(t *testing.T) {
+	SummaryEligibleStatuses = []string{termdash.StatusActive, termdash.StatusNeedsInput, termdash.StatusIdle}
+	defer func() { SummaryEligibleStatuses = DefaultSummaryEligibleStatuses }()
+
+	if !isSummaryEligibleStatus(termdash.StatusIdle) {
+		t.Error("idle should be eligible once added to SummaryEligibleStatuses")
+	}
+}
+
+func TestIsSummaryEligibleStatusIgnoresUnrecognizedEntries(t *testing.T) {
+	SummaryEligibleStatuses = []string{"bogus-status"}
+	defer func() { SummaryEligibleStatuses = DefaultSummaryEligibleStatuses }()
+
+	if isSummaryEligibleStatus("bogus-status") {
+		t.Error("an unrecognized status should never be eligible, even if configured")
+	}
+}
+
+func TestBlockNeedsGeneratedSummarySkipsManualTitle(t *testing.T) {
+	meta := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashType:          "claude",
+		waveobj.MetaKey_TermDashStatus:        termdash.StatusActive,
+		waveobj.MetaKey_TermDashSummaryManual: true,
+	}
+	if blockNeedsGeneratedSummary(meta) {
+		t.Error("blockNeedsGeneratedSummary() = true, want a manually-titled block to survive a poll cycle")
+	}
+}
+
+func TestBlockNeedsGeneratedSummaryAllowsUntitledEligibleBlock(t *testing.T) {
+	meta := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashType:   "claude",
+		waveobj.MetaKey_TermDashStatus: termdash.StatusActive,
+	}
+	if !blockNeedsGeneratedSummary(meta) {
+		t.Error("blockNeedsGeneratedSummary() = false, want an untitled eligible block to need a summary")
+	}
+}
+
+func TestBlockEligibleForRegenerationBlocksManualUnlessForced(t *testing.T) {
+	meta := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashType:          "claude",
+		waveobj.MetaKey_TermDashSummaryManual: true,
+	}
+	if blockEligibleForRegeneration(meta, false) {
+		t.Error("blockEligibleForRegeneration(force=false) = true, want the manual flag to block regeneration")
+	}
+	if !blockEligibleForRegeneration(meta, true) {
+		t.Error("blockEligibleForRegeneration(force=true) = false, want force to override the manual flag")
+	}
+}
+
+func TestBlockEligibleForRegenerationSkipsArchivedAndNonClaudeBlocks(t *testing.T) {
+	archived := waveobj.MetaMapType{
+		waveobj.MetaKey_TermDashType:     "claude",
+		waveobj.MetaKey_TermDashArchived: true,
+	}
+	if blockEligibleForRegeneration(archived, true) {
+		t.Error("blockEligibleForRegeneration() = true for an archived block, want false")
+	}
+
+	notClaude := waveobj.MetaMapType{}
+	if blockEligibleForRegeneration(notClaude, true) {
+		t.Error("blockEligibleForRegeneration() = true for a non-Claude block, want false")
+	}
+}
+
+func TestSetSummaryRejectsEmptyTitle(t *testing.T) {
+	s := &TermDashService{}
+	err := s.SetSummary(t.Context(), "some-block-id", "   ")
+	if err == nil {
+		t.Fatal("SetSummary(blank title) = nil error, want a validation error")
+	}
+}
+
+func TestSetSummaryRejectsOverlongTitle(t *testing.T) {
+	s := &TermDashService{}
+	err := s.SetSummary(t.Context(), "some-block-id", strings.Repeat("x", MaxManualSummaryLength+1))
+	if err == nil {
+		t.Fatal("SetSummary(overlong title) = nil error, want a validation error")
+	}
+}
+
+func TestAllowSummaryGenerationLockedEnforcesCooldown(t *testing.T) {
+	blockId := "cooldown-block-" + t.Name()
+	now := time.Now()
+
+	if !allowSummaryGenerationLocked(blockId, now) {
+		t.Fatal("allowSummaryGenerationLocked() = false on first call, want true")
+	}
+	// A second call within the cooldown window (and, by extension, a
+	// GetOrGenerateSummary call landing in that window) must be refused
+	// without recording a new attempt time, so the generator is never
+	// invoked twice for the same request burst.
+	if allowSummaryGenerationLocked(blockId, now.Add(1*time.Millisecond)) {
+		t.Error("allowSummaryGenerationLocked() = true within cooldown, want false")
+	}
+	if !allowSummaryGenerationLocked(blockId, now.Add(SummaryRegenerationCooldown+time.Millisecond)) {
+		t.Error("allowSummaryGenerationLocked() = false after cooldown elapsed, want true")
+	}
+}
+
+func TestFireSummaryGeneratedInvokesRegisteredHook(t *testing.T) {
+	summaryGeneratedMu.Lock()
+	summaryGeneratedHooks = nil
+	summaryGeneratedMu.Unlock()
+
+	var gotBlockId, gotTitle string
+	OnSummaryGenerated(func(blockId, title string) {
+		gotBlockId = blockId
+		gotTitle = title
+	})
+
+	fireSummaryGenerated("block-123", "Fix the login bug")
+
+	if gotBlockId != "block-123" {
+		t.Errorf("hook received blockId = %q, want %q", gotBlockId, "block-123")
+	}
+	if gotTitle != "Fix the login bug" {
+		t.Errorf("hook received title = %q, want %q", gotTitle, "Fix the login bug")
+	}
+}
+
+func TestResumeBackgroundProcessingClearsPause(t *testing.T) {
+	PauseBackgroundProcessing()
+	ResumeBackgroundProcessing()
+
+	if IsBackgroundProcessingPaused() {
+		t.Errorf("IsBackgroundProcessingPaused() = true after ResumeBackgroundProcessing()")
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+const (
+	TitleGeneratorModeCLI       = "cli"
+	TitleGeneratorModeHeuristic = "heuristic"
+)
+
+// TitleGeneratorMode selects which TitleGenerator is used by the summary
+// loop. Defaults to the claude CLI, which produces the best titles but
+// requires the claude binary and a network round-trip.
+var TitleGeneratorMode = TitleGeneratorModeCLI
+
+// TitleGenerator produces a short session title from a block's cleaned
+// terminal output.
+type TitleGenerator interface {
+	GenerateTitle(ctx context.Context, termOutput string) (string, error)
+}
+
+// activeTitleGenerator returns the TitleGenerator selected by TitleGeneratorMode.
+func activeTitleGenerator() TitleGenerator {
+	if TitleGeneratorMode == TitleGeneratorModeHeuristic {
+		return heuristicTitleGenerator{}
+	}
+	return cliTitleGenerator{}
+}
+
+// cliTitleGenerator calls the claude CLI in print mode to generate a title.
+type cliTitleGenerator struct{}
+
+func (cliTitleGenerator) GenerateTitle(ctx context.Context, termOutput string) (string, error) {
+	return generateTitle(ctx, termOutput)
+}
+
+var stopWords = map[string]bool{
+	"the": true, "a": true, "an": true, "and": true, "or": true, "but": true,
+	"is": true, "are": true, "was": true, "were": true, "be": true, "been": true,
+	"to": true, "of": true, "in": true, "on": true, "at": true, "for": true,
+	"with": true, "this": true, "that": true, "it": true, "as": true, "by": true,
+	"from": true, "has": true, "have": true, "had": true, "not": true, "no": true,
+	"you": true, "your": true, "i": true, "we": true, "will": true, "can": true,
+}
+
+var wordRegex = regexp.MustCompile(`[A-Za-z][A-Za-z0-9_.\-/]{2,}`)
+
+// commandPromptRegex matches a leading shell/tool prompt on a line, e.g.
+// "$ npm test" or "❯ git status", capturing the command that follows.
+var commandPromptRegex = regexp.MustCompile(`^\s*(?:\$|❯|>)\s*(\S.*)$`)
+
+// heuristicTitleGenerator derives a title from the transcript using simple
+// term-frequency and command-detection heuristics, without any external
+// call. It's used when the claude CLI isn't available or when an instant
+// title is preferred over a higher-quality one.
+type heuristicTitleGenerator struct{}
+
+func (heuristicTitleGenerator) GenerateTitle(ctx context.Context, termOutput string) (string, error) {
+	if strings.TrimSpace(termOutput) == "" {
+		return "", nil
+	}
+
+	if cmd := firstMeaningfulCommand(termOutput); cmd != "" {
+		return truncateTitle(cmd), nil
+	}
+
+	terms := topTerms(termOutput, 5)
+	if len(terms) == 0 {
+		return "", nil
+	}
+	return truncateTitle(strings.Join(terms, " ")), nil
+}
+
+// firstMeaningfulCommand scans for the first line that looks like a shell
+// prompt followed by a command and returns the command portion.
+func firstMeaningfulCommand(termOutput string) string {
+	for _, line := range strings.Split(termOutput, "\n") {
+		m := commandPromptRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		cmd := strings.TrimSpace(m[1])
+		if cmd != "" {
+			return cmd
+		}
+	}
+	return ""
+}
+
+// topTerms returns the n most frequent non-stopword terms in text, in
+// descending order of frequency.
+func topTerms(text string, n int) []string {
+	counts := make(map[string]int)
+	var order []string
+	for _, word := range wordRegex.FindAllString(text, -1) {
+		lower := strings.ToLower(word)
+		if stopWords[lower] {
+			continue
+		}
+		if counts[lower] == 0 {
+			order = append(order, lower)
+		}
+		counts[lower]++
+	}
+
+	// Simple selection sort for the top n terms; term counts are small
+	// enough that this is fine and keeps first-seen order as a tiebreaker.
+	var top []string
+	for len(top) < n && len(order) > 0 {
+		bestIdx := 0
+		for i, term := range order {
+			if counts[term] > counts[order[bestIdx]] {
+				bestIdx = i
+			}
+		}
+		top = append(top, order[bestIdx])
+		order = append(order[:bestIdx], order[bestIdx+1:]...)
+	}
+	return top
+}
+
+// truncateTitle caps title at 80 runes, truncating on rune boundaries so
+// multi-byte characters (e.g. non-ASCII titles from a configured
+// SummaryLanguage) aren't split mid-character.
+func truncateTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) > 80 {
+		title = string(runes[:80])
+	}
+	return title
+}
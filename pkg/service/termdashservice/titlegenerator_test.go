@@ -0,0 +1,44 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestHeuristicTitleGeneratorFirstCommand(t *testing.T) {
+	termOutput := "Welcome to the shell\n$ npm run build\nBuilding project...\nDone.\n"
+
+	title, err := heuristicTitleGenerator{}.GenerateTitle(context.Background(), termOutput)
+	if err != nil {
+		t.Fatalf("GenerateTitle() error = %v", err)
+	}
+	if title != "npm run build" {
+		t.Errorf("GenerateTitle() = %q, want %q", title, "npm run build")
+	}
+}
+
+func TestHeuristicTitleGeneratorTopTerms(t *testing.T) {
+	termOutput := strings.Repeat("refactor refactor refactor database migration migration schema noise\n", 3)
+
+	title, err := heuristicTitleGenerator{}.GenerateTitle(context.Background(), termOutput)
+	if err != nil {
+		t.Fatalf("GenerateTitle() error = %v", err)
+	}
+	if !strings.Contains(title, "refactor") || !strings.Contains(title, "migration") {
+		t.Errorf("GenerateTitle() = %q, want it to contain the most frequent terms", title)
+	}
+}
+
+func TestHeuristicTitleGeneratorEmpty(t *testing.T) {
+	title, err := heuristicTitleGenerator{}.GenerateTitle(context.Background(), "   ")
+	if err != nil {
+		t.Fatalf("GenerateTitle() error = %v", err)
+	}
+	if title != "" {
+		t.Errorf("GenerateTitle() = %q, want empty title for empty input", title)
+	}
+}
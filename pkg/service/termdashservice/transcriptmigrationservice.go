@@ -0,0 +1,40 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+// MigrateTranscript upgrades a block's transcript file in place to
+// termdash.CurrentTranscriptSchemaVersion. It's a no-op if the block has no
+// transcript yet, or if the transcript is already current.
+func (s *TermDashService) MigrateTranscript(ctx context.Context, blockId string) error {
+	_, data, err := filestore.WFS.ReadFile(ctx, blockId, "termdash:transcript")
+	if err != nil {
+		return nil // no transcript yet; nothing to migrate
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	format := termdash.TranscriptFormatJSONL
+	if termdash.IsBinaryTranscript(data) {
+		format = termdash.TranscriptFormatBinary
+	}
+	migrated, changed := termdash.MigrateTranscriptEntries(termdash.DecodeTranscriptEntries(data))
+	if !changed {
+		return nil
+	}
+
+	encoded := termdash.EncodeTranscriptEntries(migrated, format)
+	if err := filestore.WFS.WriteFile(ctx, blockId, "termdash:transcript", encoded); err != nil {
+		return fmt.Errorf("error writing migrated transcript: %w", err)
+	}
+	return nil
+}
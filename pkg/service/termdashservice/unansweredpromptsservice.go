@@ -0,0 +1,102 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wavetermdev/waveterm/pkg/filestore"
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wstore"
+)
+
+// unansweredPromptLines is how many trailing transcript lines
+// ListUnansweredPrompts includes as a waiting session's parsed prompt text -
+// enough to capture a multi-line permission dialog without pulling in
+// unrelated earlier output.
+const unansweredPromptLines = 6
+
+// PromptInfo describes one non-archived Claude session currently waiting on
+// the user, for a "you have questions waiting" view across every session.
+type PromptInfo struct {
+	BlockId    string `json:"blockid"`
+	Kind       string `json:"kind"` // termdash.NeedsInputKindConfirmation or termdash.NeedsInputKindQuestion
+	PromptText string `json:"prompttext"`
+	WaitingMs  int64  `json:"waitingms"`
+}
+
+// ListUnansweredPrompts returns every non-archived Claude session currently
+// in StatusNeedsInput, each with its parsed prompt text and how long it's
+// been waiting. It composes the persisted status (attention detection), the
+// transcript's tail (permission-dialog parsing), and the status timeline's
+// ongoing segment (waiting-duration tracking). Results are sorted by wait
+// time, longest first.
+func (s *TermDashService) ListUnansweredPrompts(ctx context.Context) ([]PromptInfo, error) {
+	blocks, err := wstore.DBGetAllObjsByType[*waveobj.Block](ctx, waveobj.OType_Block)
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	var prompts []PromptInfo
+	for _, block := range blocks {
+		isClaudeType := block.Meta.GetString(waveobj.MetaKey_TermDashType, "") == "claude"
+		archived := block.Meta.GetBool(waveobj.MetaKey_TermDashArchived, false)
+		status := block.Meta.GetString(waveobj.MetaKey_TermDashStatus, "")
+		if !isClaudeType || archived || status != termdash.StatusNeedsInput {
+			continue
+		}
+
+		timeline, err := s.GetStatusTimeline(ctx, block.OID)
+		if err != nil {
+			continue
+		}
+
+		_, data, err := filestore.WFS.ReadFile(ctx, block.OID, "termdash:transcript")
+		if err != nil {
+			continue
+		}
+
+		if info, ok := buildPromptInfo(block.OID, timeline, reconstructTranscriptText(data)); ok {
+			prompts = append(prompts, info)
+		}
+	}
+
+	sort.Slice(prompts, func(i, j int) bool {
+		return prompts[i].WaitingMs > prompts[j].WaitingMs
+	})
+	return prompts, nil
+}
+
+// buildPromptInfo checks that a candidate session's status timeline is
+// still, right now, an ongoing StatusNeedsInput segment, and that its
+// transcript yields non-empty prompt text, building its PromptInfo if so.
+// Split out from the block/filestore iteration loop in
+// ListUnansweredPrompts so the filtering and text-extraction logic is
+// testable without a DB, mirroring buildSessionComparison in
+// sessioncomparisonservice.go.
+func buildPromptInfo(blockId string, timeline []termdash.StatusTransition, transcriptText string) (PromptInfo, bool) {
+	if len(timeline) == 0 {
+		return PromptInfo{}, false
+	}
+	current := timeline[len(timeline)-1]
+	if !current.Ongoing || current.Status != termdash.StatusNeedsInput {
+		return PromptInfo{}, false
+	}
+
+	promptText := strings.TrimSpace(lastNLines(termdash.StripAnsi(transcriptText), unansweredPromptLines))
+	if promptText == "" {
+		return PromptInfo{}, false
+	}
+
+	return PromptInfo{
+		BlockId:    blockId,
+		Kind:       termdash.ClassifyNeedsInputKind(promptText),
+		PromptText: promptText,
+		WaitingMs:  current.DurationMs,
+	}, true
+}
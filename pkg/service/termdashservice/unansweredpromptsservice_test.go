@@ -0,0 +1,75 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdashservice
+
+import (
+	"testing"
+
+	"github.com/wavetermdev/waveterm/pkg/termdash"
+)
+
+func TestBuildPromptInfoReturnsInfoForOngoingNeedsInput(t *testing.T) {
+	timeline := []termdash.StatusTransition{
+		{Status: termdash.StatusActive, DurationMs: 5000},
+		{Status: termdash.StatusNeedsInput, DurationMs: 12000, Ongoing: true},
+	}
+
+	got, ok := buildPromptInfo("block-1", timeline, "Do you want to proceed?\n❯ 1. Yes\n  2. No")
+	if !ok {
+		t.Fatal("buildPromptInfo() ok = false, want true for an ongoing needs-input session")
+	}
+	if got.BlockId != "block-1" {
+		t.Errorf("BlockId = %q, want block-1", got.BlockId)
+	}
+	if got.WaitingMs != 12000 {
+		t.Errorf("WaitingMs = %d, want 12000", got.WaitingMs)
+	}
+	if got.Kind != termdash.NeedsInputKindConfirmation {
+		t.Errorf("Kind = %q, want %q", got.Kind, termdash.NeedsInputKindConfirmation)
+	}
+	if got.PromptText == "" {
+		t.Error("PromptText is empty, want the transcript's tail")
+	}
+}
+
+func TestBuildPromptInfoSkipsWhenNotOngoing(t *testing.T) {
+	timeline := []termdash.StatusTransition{
+		{Status: termdash.StatusNeedsInput, DurationMs: 12000, Ongoing: false},
+		{Status: termdash.StatusActive, DurationMs: 1000, Ongoing: true},
+	}
+
+	if _, ok := buildPromptInfo("block-1", timeline, "Do you want to proceed?"); ok {
+		t.Error("buildPromptInfo() ok = true, want false when the current segment isn't needs-input")
+	}
+}
+
+func TestBuildPromptInfoSkipsWhenTimelineEmpty(t *testing.T) {
+	if _, ok := buildPromptInfo("block-1", nil, "Do you want to proceed?"); ok {
+		t.Error("buildPromptInfo() ok = true, want false with no timeline")
+	}
+}
+
+func TestBuildPromptInfoSkipsWhenTranscriptEmpty(t *testing.T) {
+	timeline := []termdash.StatusTransition{
+		{Status: termdash.StatusNeedsInput, DurationMs: 500, Ongoing: true},
+	}
+
+	if _, ok := buildPromptInfo("block-1", timeline, "   \n  \n"); ok {
+		t.Error("buildPromptInfo() ok = true, want false when the transcript yields no prompt text")
+	}
+}
+
+func TestBuildPromptInfoClassifiesOpenEndedQuestion(t *testing.T) {
+	timeline := []termdash.StatusTransition{
+		{Status: termdash.StatusNeedsInput, DurationMs: 3000, Ongoing: true},
+	}
+
+	got, ok := buildPromptInfo("block-1", timeline, "What should the new function be named?")
+	if !ok {
+		t.Fatal("buildPromptInfo() ok = false, want true")
+	}
+	if got.Kind != termdash.NeedsInputKindQuestion {
+		t.Errorf("Kind = %q, want %q", got.Kind, termdash.NeedsInputKindQuestion)
+	}
+}
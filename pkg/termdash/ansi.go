@@ -0,0 +1,185 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ansiEscapeRegex matches ANSI escape sequences: CSI sequences (cursor
+// movement, screen modes, SGR color/style codes) and OSC sequences (window
+// title, terminal hyperlinks, etc.). This is the single canonical pattern
+// for ANSI detection/removal across the package, replacing what used to be
+// several near-identical regexes maintained separately in statusdetector.go,
+// transcript.go, and termdashservice.
+//
+// The OSC branch accepts either terminator a terminal may use: BEL (\x07,
+// the traditional form) or ST (ESC \, the more portable form OSC 8
+// hyperlinks commonly use) -- matching only BEL left an ST-terminated
+// sequence (and, for a hyperlink, its URL and closing sequence) unstripped
+// garbage in the output.
+//
+// It only covers the 7-bit forms (ESC [ ... and ESC ] ...); the 8-bit
+// single-byte introducers some terminals/programs emit instead (\x9b for
+// CSI, \x9d for OSC) can't be expressed as part of this pattern, since
+// Go's regexp package matches over decoded UTF-8 runes and those bytes
+// aren't valid UTF-8 on their own. strip8BitIntroducers handles them in a
+// separate byte-level pass; see StripAnsi and ansiToHTML.
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b\[\?[0-9;]*[a-zA-Z]`)
+
+// sgrRegex matches only SGR (color/style) escape sequences, the subset
+// ansiToHTML can translate to markup.
+var sgrRegex = regexp.MustCompile(`\x1b\[([0-9;]*)m`)
+
+// strip8BitIntroducers removes 8-bit single-byte CSI (0x9B) and OSC (0x9D)
+// escape sequences. These bytes are only ever valid on their own as raw
+// Latin-1-style control codes, not as part of any valid UTF-8 sequence, so
+// left unstripped they corrupt prompt matching and stored transcripts.
+// Handled byte-by-byte rather than folded into ansiEscapeRegex: Go's
+// regexp package decodes input as UTF-8 runes, and a standalone 0x9B or
+// 0x9D byte decodes to the same replacement rune either way, making the
+// two introducers indistinguishable to a regexp-based match.
+func strip8BitIntroducers(text string) string {
+	if !strings.ContainsAny(text, "\x9b\x9d") {
+		return text
+	}
+	b := []byte(text)
+	var sb strings.Builder
+	sb.Grow(len(b))
+	for i := 0; i < len(b); {
+		switch b[i] {
+		case 0x9b: // 8-bit CSI
+			j := i + 1
+			if j < len(b) && b[j] == '?' {
+				j++
+			}
+			for j < len(b) && (b[j] == ';' || (b[j] >= '0' && b[j] <= '9')) {
+				j++
+			}
+			if j < len(b) && ((b[j] >= 'a' && b[j] <= 'z') || (b[j] >= 'A' && b[j] <= 'Z')) {
+				j++
+			}
+			i = j
+		case 0x9d: // 8-bit OSC, terminated by BEL
+			j := i + 1
+			for j < len(b) && b[j] != '\a' {
+				j++
+			}
+			if j < len(b) {
+				j++ // consume the terminating BEL
+			}
+			i = j
+		default:
+			sb.WriteByte(b[i])
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// StripAnsi removes all ANSI escape codes from text.
+func StripAnsi(text string) string {
+	return ansiEscapeRegex.ReplaceAllString(strip8BitIntroducers(text), "")
+}
+
+// ANSIMode controls how an ANSIMode-aware reader (GetTranscript,
+// GetTranscriptRange) handles ANSI escape codes present in stored
+// transcript text, so the same stored session can be rendered for search
+// (stripped), raw capture (preserved), or display (converted to markup).
+type ANSIMode string
+
+const (
+	// ANSIModeStrip removes ANSI escape codes entirely. The default, and
+	// the only mode that makes sense for text a caller will match/search
+	// against.
+	ANSIModeStrip ANSIMode = "strip"
+
+	// ANSIModePreserve leaves ANSI escape codes untouched, e.g. for a raw
+	// capture a consumer will feed straight into a terminal emulator.
+	ANSIModePreserve ANSIMode = "preserve"
+
+	// ANSIModeConvertHTML converts ANSI SGR color/style codes into inline
+	// HTML <span> markup and drops any other escape codes, for rendering
+	// colored output outside a terminal (e.g. a web export).
+	ANSIModeConvertHTML ANSIMode = "html"
+)
+
+// ApplyANSIMode transforms text according to mode. An unrecognized mode
+// falls back to ANSIModeStrip, the only mode that's always safe to return.
+func ApplyANSIMode(text string, mode ANSIMode) string {
+	switch mode {
+	case ANSIModePreserve:
+		return text
+	case ANSIModeConvertHTML:
+		return ansiToHTML(text)
+	default:
+		return StripAnsi(text)
+	}
+}
+
+// ansiColorClasses maps SGR color codes to the CSS class ansiToHTML emits
+// for them.
+var ansiColorClasses = map[string]string{
+	"30": "ansi-black", "31": "ansi-red", "32": "ansi-green", "33": "ansi-yellow",
+	"34": "ansi-blue", "35": "ansi-magenta", "36": "ansi-cyan", "37": "ansi-white",
+	"90": "ansi-bright-black", "91": "ansi-bright-red", "92": "ansi-bright-green", "93": "ansi-bright-yellow",
+	"94": "ansi-bright-blue", "95": "ansi-bright-magenta", "96": "ansi-bright-cyan", "97": "ansi-bright-white",
+}
+
+// ansiToHTML converts ANSI SGR codes into nested <span class="..."> markup,
+// closing open spans on reset (bare or "0") and HTML-escaping plain text in
+// between. Non-SGR escape codes (cursor movement, screen modes) have no
+// HTML equivalent and are dropped.
+func ansiToHTML(text string) string {
+	var sb strings.Builder
+	openSpans := 0
+	pos := 0
+	for _, loc := range sgrRegex.FindAllStringSubmatchIndex(text, -1) {
+		sb.WriteString(htmlEscape(text[pos:loc[0]]))
+		codes := text[loc[2]:loc[3]]
+		if codes == "" || codes == "0" {
+			for openSpans > 0 {
+				sb.WriteString("</span>")
+				openSpans--
+			}
+		} else if classes := sgrClasses(codes); classes != "" {
+			sb.WriteString(`<span class="` + classes + `">`)
+			openSpans++
+		}
+		pos = loc[1]
+	}
+	sb.WriteString(htmlEscape(text[pos:]))
+	for openSpans > 0 {
+		sb.WriteString("</span>")
+		openSpans--
+	}
+	return ansiEscapeRegex.ReplaceAllString(strip8BitIntroducers(sb.String()), "")
+}
+
+// sgrClasses maps a semicolon-separated SGR code list to the CSS classes
+// ansiToHTML should apply, ignoring codes with no mapped class (e.g.
+// underline, background colors).
+func sgrClasses(codes string) string {
+	var classes []string
+	for _, code := range strings.Split(codes, ";") {
+		if code == "1" {
+			classes = append(classes, "ansi-bold")
+			continue
+		}
+		if class, ok := ansiColorClasses[code]; ok {
+			classes = append(classes, class)
+		}
+	}
+	return strings.Join(classes, " ")
+}
+
+// htmlEscape escapes the HTML-significant characters ansiToHTML's plain
+// text segments might contain.
+func htmlEscape(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	s = strings.ReplaceAll(s, ">", "&gt;")
+	return s
+}
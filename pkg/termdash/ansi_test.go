@@ -0,0 +1,109 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"testing"
+)
+
+const ansiSample = "\x1b[31mred\x1b[0m plain \x1b[1m\x1b[32mbold green\x1b[0m"
+
+func TestStripAnsiRemovesEscapeCodes(t *testing.T) {
+	got := StripAnsi(ansiSample)
+	want := "red plain bold green"
+	if got != want {
+		t.Errorf("StripAnsi() = %q, want %q", got, want)
+	}
+}
+
+func TestApplyANSIModeStrip(t *testing.T) {
+	got := ApplyANSIMode(ansiSample, ANSIModeStrip)
+	want := "red plain bold green"
+	if got != want {
+		t.Errorf("ApplyANSIMode(strip) = %q, want %q", got, want)
+	}
+}
+
+func TestApplyANSIModePreserve(t *testing.T) {
+	got := ApplyANSIMode(ansiSample, ANSIModePreserve)
+	if got != ansiSample {
+		t.Errorf("ApplyANSIMode(preserve) = %q, want unchanged %q", got, ansiSample)
+	}
+}
+
+func TestApplyANSIModeConvertHTML(t *testing.T) {
+	got := ApplyANSIMode(ansiSample, ANSIModeConvertHTML)
+	want := `<span class="ansi-red">red</span> plain <span class="ansi-bold"><span class="ansi-green">bold green</span></span>`
+	if got != want {
+		t.Errorf("ApplyANSIMode(html) = %q, want %q", got, want)
+	}
+}
+
+func TestApplyANSIModeUnrecognizedFallsBackToStrip(t *testing.T) {
+	got := ApplyANSIMode(ansiSample, ANSIMode("bogus"))
+	want := "red plain bold green"
+	if got != want {
+		t.Errorf("ApplyANSIMode(bogus) = %q, want %q", got, want)
+	}
+}
+
+func TestAnsiToHTMLEscapesPlainText(t *testing.T) {
+	got := ApplyANSIMode("<script> & \"quotes\"", ANSIModeConvertHTML)
+	want := "&lt;script&gt; &amp; \"quotes\""
+	if got != want {
+		t.Errorf("ApplyANSIMode(html) = %q, want %q", got, want)
+	}
+}
+
+func TestStripAnsiRemoves8BitCSI(t *testing.T) {
+	// \x9b is the 8-bit single-byte equivalent of the 7-bit "\x1b[" CSI
+	// introducer.
+	got := StripAnsi("\x9b31mred\x9b0m plain")
+	want := "red plain"
+	if got != want {
+		t.Errorf("StripAnsi() = %q, want %q", got, want)
+	}
+}
+
+func TestStripAnsiRemoves8BitCSIWithPrivateMarker(t *testing.T) {
+	got := StripAnsi("\x9b?25lhidden cursor\x9b?25h")
+	want := "hidden cursor"
+	if got != want {
+		t.Errorf("StripAnsi() = %q, want %q", got, want)
+	}
+}
+
+func TestStripAnsiRemoves8BitOSC(t *testing.T) {
+	// \x9d is the 8-bit single-byte equivalent of the 7-bit "\x1b]" OSC
+	// introducer.
+	got := StripAnsi("\x9d0;window title\x07plain text")
+	want := "plain text"
+	if got != want {
+		t.Errorf("StripAnsi() = %q, want %q", got, want)
+	}
+}
+
+func TestStripAnsiRemoves7BitAnd8BitEquivalentsTheSame(t *testing.T) {
+	sevenBit := StripAnsi("\x1b[31mred\x1b[0m")
+	eightBit := StripAnsi("\x9b31mred\x9b0m")
+	if sevenBit != eightBit {
+		t.Errorf("7-bit stripped to %q, 8-bit stripped to %q, want them equal", sevenBit, eightBit)
+	}
+}
+
+func TestStripAnsiRemovesOSC8HyperlinkBELTerminated(t *testing.T) {
+	got := StripAnsi("\x1b]8;;https://example.com\x07Example\x1b]8;;\x07 plain")
+	want := "Example plain"
+	if got != want {
+		t.Errorf("StripAnsi() = %q, want %q", got, want)
+	}
+}
+
+func TestStripAnsiRemovesOSC8HyperlinkSTTerminated(t *testing.T) {
+	got := StripAnsi("\x1b]8;;https://example.com\x1b\\Example\x1b]8;;\x1b\\ plain")
+	want := "Example plain"
+	if got != want {
+		t.Errorf("StripAnsi() = %q, want %q", got, want)
+	}
+}
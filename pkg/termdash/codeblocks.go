@@ -0,0 +1,66 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CodeBlock is a single fenced code block recovered from a session's
+// transcript, e.g. a snippet Claude suggested that the user wants to copy
+// back out.
+type CodeBlock struct {
+	Language   string
+	Content    string
+	Terminated bool // false if the transcript ended before a closing fence was found
+}
+
+// codeFenceOpenPattern matches a fence-opening line: three or more
+// backticks (optionally indented up to 3 spaces, per CommonMark) followed
+// by an optional language tag.
+var codeFenceOpenPattern = regexp.MustCompile("^ {0,3}(`{3,})[ \\t]*([^\\s`]*)[ \\t]*$")
+
+// ExtractCodeBlocks scans text for fenced code blocks and returns each one
+// found, in order of appearance. A fence only closes on a line consisting
+// solely of at least as many backticks as it opened with, so a shorter
+// backtick run inside the block (e.g. a nested example) is treated as
+// ordinary content rather than closing it early. A block left open at the
+// end of text is still returned, with Terminated set to false, so a
+// trailing incomplete suggestion isn't silently dropped.
+func ExtractCodeBlocks(text string) []CodeBlock {
+	var blocks []CodeBlock
+	lines := strings.Split(text, "\n")
+
+	for i := 0; i < len(lines); i++ {
+		m := codeFenceOpenPattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		fence := m[1]
+		language := m[2]
+		closePattern := regexp.MustCompile("^ {0,3}`{" + strconv.Itoa(len(fence)) + ",}[ \\t]*$")
+
+		var content []string
+		terminated := false
+		j := i + 1
+		for ; j < len(lines); j++ {
+			if closePattern.MatchString(lines[j]) {
+				terminated = true
+				break
+			}
+			content = append(content, lines[j])
+		}
+
+		blocks = append(blocks, CodeBlock{
+			Language:   language,
+			Content:    strings.Join(content, "\n"),
+			Terminated: terminated,
+		})
+		i = j // resume scanning after the closing fence (or at end of text)
+	}
+
+	return blocks
+}
@@ -0,0 +1,77 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractCodeBlocksFindsMultipleBlocksWithLanguages(t *testing.T) {
+	text := "Here's a fix:\n" +
+		"```go\n" +
+		"func main() {}\n" +
+		"```\n" +
+		"And the test:\n" +
+		"```python\n" +
+		"def test(): pass\n" +
+		"```\n"
+
+	got := ExtractCodeBlocks(text)
+	want := []CodeBlock{
+		{Language: "go", Content: "func main() {}", Terminated: true},
+		{Language: "python", Content: "def test(): pass", Terminated: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractCodeBlocks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExtractCodeBlocksHandlesNestedFences(t *testing.T) {
+	text := "````markdown\n" +
+		"Example:\n" +
+		"```go\n" +
+		"x := 1\n" +
+		"```\n" +
+		"````\n"
+
+	got := ExtractCodeBlocks(text)
+	want := []CodeBlock{
+		{Language: "markdown", Content: "Example:\n```go\nx := 1\n```", Terminated: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractCodeBlocks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExtractCodeBlocksHandlesUnterminatedBlock(t *testing.T) {
+	text := "```javascript\n" +
+		"console.log('hi')\n"
+
+	got := ExtractCodeBlocks(text)
+	want := []CodeBlock{
+		{Language: "javascript", Content: "console.log('hi')\n", Terminated: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractCodeBlocks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExtractCodeBlocksReturnsNilForPlainText(t *testing.T) {
+	got := ExtractCodeBlocks("just some regular output, no fences here")
+	if got != nil {
+		t.Errorf("ExtractCodeBlocks() = %+v, want nil", got)
+	}
+}
+
+func TestExtractCodeBlocksHandlesLanguagelessFence(t *testing.T) {
+	text := "```\nplain text block\n```\n"
+	got := ExtractCodeBlocks(text)
+	want := []CodeBlock{
+		{Language: "", Content: "plain text block", Terminated: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractCodeBlocks() = %+v, want %+v", got, want)
+	}
+}
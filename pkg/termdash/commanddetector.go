@@ -0,0 +1,89 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CommandResult represents a shell command detected in a session's
+// transcript, along with its exit status when it could be determined.
+type CommandResult struct {
+	Command       string
+	ExitCode      int
+	ExitCodeKnown bool
+}
+
+// commandPromptPattern matches a leading shell prompt followed by the
+// command that was run, e.g. "$ npm test" or "❯ git status", the same style
+// commandPromptRegex in the summary heuristic generator looks for.
+var commandPromptPattern = regexp.MustCompile(`^\s*(?:\$|❯|>)\s*(\S.*)$`)
+
+// commandPromptWithExitStatusPattern matches a shell prompt theme that
+// prefixes itself with the previous command's exit status before the new
+// command, e.g. "[1] $ npm test", capturing the status and new command
+// separately. Checked before commandPromptPattern since it's the more
+// specific of the two.
+var commandPromptWithExitStatusPattern = regexp.MustCompile(`^\s*\[(\d+)\]\s*(?:\$|❯|>)\s*(\S.*)$`)
+
+// explicitExitCodePatterns match output that states a command's exit status
+// directly, capturing the numeric code.
+var explicitExitCodePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)exit code:?\s*(\d+)`),
+	regexp.MustCompile(`(?i)exit status:?\s*(\d+)`),
+	regexp.MustCompile(`(?i)process exited with code\s*(\d+)`),
+}
+
+// DetectCommandResults scans output for shell commands run in a session and
+// pairs each with its exit status when detectable, either from an explicit
+// "exit code N"-style message appearing in the command's own output, or from
+// the exit-status prefix on the following prompt (a common shell theme that
+// surfaces $?). Commands whose result can't be determined are still
+// returned, with ExitCodeKnown false.
+func DetectCommandResults(output string) []CommandResult {
+	var results []CommandResult
+	var pending *CommandResult
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := commandPromptWithExitStatusPattern.FindStringSubmatch(line); m != nil {
+			if pending != nil {
+				if code, err := strconv.Atoi(m[1]); err == nil {
+					pending.ExitCode = code
+					pending.ExitCodeKnown = true
+				}
+				results = append(results, *pending)
+			}
+			pending = &CommandResult{Command: strings.TrimSpace(m[2])}
+			continue
+		}
+		if m := commandPromptPattern.FindStringSubmatch(line); m != nil {
+			if pending != nil {
+				results = append(results, *pending)
+			}
+			pending = &CommandResult{Command: strings.TrimSpace(m[1])}
+			continue
+		}
+
+		if pending == nil || pending.ExitCodeKnown {
+			continue
+		}
+		for _, p := range explicitExitCodePatterns {
+			m := p.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			if code, err := strconv.Atoi(m[1]); err == nil {
+				pending.ExitCode = code
+				pending.ExitCodeKnown = true
+			}
+			break
+		}
+	}
+	if pending != nil {
+		results = append(results, *pending)
+	}
+	return results
+}
@@ -0,0 +1,44 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestDetectCommandResultsExplicitExitCodeMarksFailure(t *testing.T) {
+	output := "$ npm test\nrunning tests...\n3 failing\nexit code 1\n$ echo done\ndone\n"
+
+	got := DetectCommandResults(output)
+	if len(got) != 2 {
+		t.Fatalf("DetectCommandResults() returned %d results, want 2: %+v", len(got), got)
+	}
+	if got[0].Command != "npm test" || !got[0].ExitCodeKnown || got[0].ExitCode != 1 {
+		t.Errorf("results[0] = %+v, want Command=npm test ExitCodeKnown=true ExitCode=1", got[0])
+	}
+	if got[1].Command != "echo done" || got[1].ExitCodeKnown {
+		t.Errorf("results[1] = %+v, want Command=echo done ExitCodeKnown=false", got[1])
+	}
+}
+
+func TestDetectCommandResultsPromptExitStatusPrefixAppliesToPreviousCommand(t *testing.T) {
+	output := "$ go build ./...\n# command-line-arguments\nbuild failed\n[2] $ go vet ./...\nok\n"
+
+	got := DetectCommandResults(output)
+	if len(got) != 2 {
+		t.Fatalf("DetectCommandResults() returned %d results, want 2: %+v", len(got), got)
+	}
+	if got[0].Command != "go build ./..." || !got[0].ExitCodeKnown || got[0].ExitCode != 2 {
+		t.Errorf("results[0] = %+v, want Command=go build ./... ExitCodeKnown=true ExitCode=2", got[0])
+	}
+	if got[1].Command != "go vet ./..." || got[1].ExitCodeKnown {
+		t.Errorf("results[1] = %+v, want Command=go vet ./... ExitCodeKnown=false", got[1])
+	}
+}
+
+func TestDetectCommandResultsNoPromptsReturnsEmpty(t *testing.T) {
+	output := "just some plain output\nno shell prompts here\n"
+	got := DetectCommandResults(output)
+	if len(got) != 0 {
+		t.Errorf("DetectCommandResults() = %v, want no results", got)
+	}
+}
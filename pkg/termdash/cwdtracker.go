@@ -0,0 +1,134 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// CwdChangeCallback is invoked whenever a CwdTracker detects a `cd` command
+// that changes the tracked directory.
+type CwdChangeCallback func(oldCwd, newCwd string)
+
+// cdCommandPattern matches a typed `cd` command line, capturing its
+// argument (empty for a bare `cd`).
+var cdCommandPattern = regexp.MustCompile(`^cd(?:\s+(.+))?$`)
+
+// CwdTracker maintains a best-effort current working directory for a
+// session by watching typed input for `cd` commands, so tools that anchor
+// file paths (extraction, language detection) stay correct as the user
+// navigates around. It has no visibility into the shell's actual cwd (e.g.
+// a cd inside a script it can't see into), so it's a heuristic, not a
+// guarantee.
+type CwdTracker struct {
+	mu       sync.Mutex
+	cwd      string
+	prevCwd  string
+	homeDir  string
+	lineBuf  string
+	callback CwdChangeCallback
+}
+
+// NewCwdTracker creates a CwdTracker starting at initialCwd. homeDir is
+// used to resolve a bare `cd` (no argument) and `~`-prefixed paths; pass ""
+// if unknown, in which case a bare `cd` is a no-op.
+func NewCwdTracker(initialCwd string, homeDir string, callback CwdChangeCallback) *CwdTracker {
+	return &CwdTracker{
+		cwd:      initialCwd,
+		homeDir:  homeDir,
+		callback: callback,
+	}
+}
+
+// ProcessInput feeds raw bytes as sent to the pty (individual keystrokes,
+// not necessarily whole lines) to the tracker. It buffers until a line is
+// terminated by \r or \n, then checks whether that line was a cd command.
+func (ct *CwdTracker) ProcessInput(data []byte) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	for _, b := range data {
+		switch b {
+		case '\r', '\n':
+			line := ct.lineBuf
+			ct.lineBuf = ""
+			ct.applyLineLocked(line)
+		case 0x7f, 0x08: // backspace / delete
+			if len(ct.lineBuf) > 0 {
+				ct.lineBuf = ct.lineBuf[:len(ct.lineBuf)-1]
+			}
+		default:
+			if b >= 0x20 && b < 0x7f {
+				ct.lineBuf += string(b)
+			}
+		}
+	}
+}
+
+// applyLineLocked checks a completed input line for a cd command and
+// updates the tracked cwd if it is one. Caller must hold ct.mu.
+func (ct *CwdTracker) applyLineLocked(line string) {
+	m := cdCommandPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return
+	}
+	newCwd := ct.resolveLocked(strings.TrimSpace(m[1]))
+	if newCwd == "" || newCwd == ct.cwd {
+		return
+	}
+	oldCwd := ct.cwd
+	ct.prevCwd = ct.cwd
+	ct.cwd = newCwd
+	if ct.callback != nil {
+		ct.callback(oldCwd, newCwd)
+	}
+}
+
+// resolveLocked resolves a cd argument against the current cwd, handling
+// relative paths, an absolute path, `~`, a bare cd (goes home), and `cd -`
+// (goes to the previous directory). Returns "" if the argument can't be
+// resolved (e.g. `cd -` with no previous directory yet).
+func (ct *CwdTracker) resolveLocked(arg string) string {
+	switch {
+	case arg == "":
+		return ct.homeDir
+	case arg == "-":
+		return ct.prevCwd
+	case arg == "~":
+		return ct.homeDir
+	case strings.HasPrefix(arg, "~/"):
+		if ct.homeDir == "" {
+			return ""
+		}
+		return path.Clean(path.Join(ct.homeDir, arg[2:]))
+	case path.IsAbs(arg):
+		return path.Clean(arg)
+	default:
+		return path.Clean(path.Join(ct.cwd, arg))
+	}
+}
+
+// Cwd returns the currently tracked working directory.
+func (ct *CwdTracker) Cwd() string {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.cwd
+}
+
+// DetectCwdChanges replays inputText (the concatenated text of a session's
+// recorded input entries) through a CwdTracker seeded with startCwd and
+// homeDir, and returns every cwd the session visited, starting with
+// startCwd, in order. A cd command left uncommitted at the end of
+// inputText (no trailing \r or \n) never executed, so it's ignored, same
+// as a real shell.
+func DetectCwdChanges(inputText string, startCwd string, homeDir string) []string {
+	history := []string{startCwd}
+	tracker := NewCwdTracker(startCwd, homeDir, func(_, newCwd string) {
+		history = append(history, newCwd)
+	})
+	tracker.ProcessInput([]byte(inputText))
+	return history
+}
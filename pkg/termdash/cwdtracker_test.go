@@ -0,0 +1,73 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestDetectCwdChangesTracksRelativeAndAbsolutePaths(t *testing.T) {
+	input := "cd projects\r" + "cd sub\r" + "cd /etc\r"
+	got := DetectCwdChanges(input, "/home/user", "/home/user")
+	want := []string{"/home/user", "/home/user/projects", "/home/user/projects/sub", "/etc"}
+	if !equalStrings(got, want) {
+		t.Errorf("DetectCwdChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectCwdChangesHandlesCdDash(t *testing.T) {
+	input := "cd /var\r" + "cd /etc\r" + "cd -\r"
+	got := DetectCwdChanges(input, "/home/user", "/home/user")
+	want := []string{"/home/user", "/var", "/etc", "/var"}
+	if !equalStrings(got, want) {
+		t.Errorf("DetectCwdChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectCwdChangesHandlesBareCdAndTilde(t *testing.T) {
+	input := "cd /var\r" + "cd\r" + "cd /var\r" + "cd ~/projects\r"
+	got := DetectCwdChanges(input, "/home/user", "/home/user")
+	want := []string{"/home/user", "/var", "/home/user", "/var", "/home/user/projects"}
+	if !equalStrings(got, want) {
+		t.Errorf("DetectCwdChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectCwdChangesIgnoresNonCdCommands(t *testing.T) {
+	input := "ls -la\r" + "echo cd fakeout\r" + "cd ..\r"
+	got := DetectCwdChanges(input, "/home/user/projects", "/home/user")
+	want := []string{"/home/user/projects", "/home/user"}
+	if !equalStrings(got, want) {
+		t.Errorf("DetectCwdChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectCwdChangesIgnoresUncommittedTrailingCommand(t *testing.T) {
+	input := "cd /var\r" + "cd /et" // no terminator; user hasn't pressed Enter yet
+	got := DetectCwdChanges(input, "/home/user", "/home/user")
+	want := []string{"/home/user", "/var"}
+	if !equalStrings(got, want) {
+		t.Errorf("DetectCwdChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectCwdChangesHandlesBackspaceEditing(t *testing.T) {
+	// User types "cd /vaar" (typo), backspaces out the last two chars, then finishes "cd /var".
+	input := "cd /vaar\x7f\x7fr\r"
+	got := DetectCwdChanges(input, "/home/user", "/home/user")
+	want := []string{"/home/user", "/var"}
+	if !equalStrings(got, want) {
+		t.Errorf("DetectCwdChanges() = %v, want %v", got, want)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FailedEdit represents a single detected file-edit failure in a Claude
+// session's output — an edit tool call that didn't apply because the diff
+// no longer matched the file or the file changed underneath it.
+type FailedEdit struct {
+	File   string
+	Reason string
+}
+
+// editFailurePatterns match known claude CLI edit-tool failure messages,
+// capturing the affected file path. reason is a short human-readable label
+// for the recognized failure kind.
+var editFailurePatterns = []struct {
+	pattern *regexp.Regexp
+	reason  string
+}{
+	{regexp.MustCompile(`(?i)string to replace not found in ([^\s:]+)`), "string not found"},
+	{regexp.MustCompile(`(?i)file has been (?:unexpectedly )?modified since (?:it was )?(?:last )?read:?\s*([^\s]+)`), "file changed since read"},
+	{regexp.MustCompile(`(?i)failed to apply edit to ([^\s:]+)`), "edit not applied"},
+}
+
+// DetectFailedEdits scans output for recognizable file-edit failure
+// messages and returns each one found, in order of appearance.
+func DetectFailedEdits(output string) []FailedEdit {
+	var failures []FailedEdit
+	for _, line := range strings.Split(output, "\n") {
+		for _, p := range editFailurePatterns {
+			m := p.pattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			failures = append(failures, FailedEdit{File: m[1], Reason: p.reason})
+		}
+	}
+	return failures
+}
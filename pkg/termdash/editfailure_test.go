@@ -0,0 +1,38 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestDetectFailedEditsStringNotFound(t *testing.T) {
+	output := "Editing pkg/foo/bar.go\nError: String to replace not found in pkg/foo/bar.go\nDone.\n"
+
+	got := DetectFailedEdits(output)
+	if len(got) != 1 {
+		t.Fatalf("DetectFailedEdits() returned %d entries, want 1: %v", len(got), got)
+	}
+	if got[0].File != "pkg/foo/bar.go" || got[0].Reason != "string not found" {
+		t.Errorf("DetectFailedEdits() = %+v, want File=pkg/foo/bar.go Reason=string not found", got[0])
+	}
+}
+
+func TestDetectFailedEditsFileChangedSinceRead(t *testing.T) {
+	output := "File has been modified since it was last read: main.go\n"
+
+	got := DetectFailedEdits(output)
+	if len(got) != 1 {
+		t.Fatalf("DetectFailedEdits() returned %d entries, want 1: %v", len(got), got)
+	}
+	if got[0].File != "main.go" || got[0].Reason != "file changed since read" {
+		t.Errorf("DetectFailedEdits() = %+v, want File=main.go Reason=file changed since read", got[0])
+	}
+}
+
+func TestDetectFailedEditsNoMatchesReturnsEmpty(t *testing.T) {
+	output := "Everything applied cleanly.\n"
+	got := DetectFailedEdits(output)
+	if len(got) != 0 {
+		t.Errorf("DetectFailedEdits() = %v, want no entries", got)
+	}
+}
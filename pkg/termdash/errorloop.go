@@ -0,0 +1,92 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultErrorLoopThreshold is how many times an identical error line must
+// repeat within DefaultErrorLoopWindow lines before DetectErrorLoop reports
+// a loop.
+const DefaultErrorLoopThreshold = 3
+
+// DefaultErrorLoopWindow is the number of trailing non-blank lines
+// DetectErrorLoop considers when counting repeats of an error line, so an
+// error that recurs after a long stretch of unrelated output doesn't count
+// as the same loop.
+const DefaultErrorLoopWindow = 20
+
+// errorLinePatterns match lines that look like an error message, the same
+// generic style DetectFailedEdits uses for its more specific edit-failure
+// patterns.
+var errorLinePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\berror\b`),
+	regexp.MustCompile(`(?i)\bexception\b`),
+	regexp.MustCompile(`(?i)\btraceback\b`),
+}
+
+// isErrorLine reports whether line looks like an error message.
+func isErrorLine(line string) bool {
+	for _, p := range errorLinePatterns {
+		if p.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorLoop describes a detected repeated-error loop: the error line that
+// repeated and how many times it appeared within the window.
+type ErrorLoop struct {
+	Line  string
+	Count int
+}
+
+// DetectErrorLoop scans output for an error line that repeats at least
+// threshold times within a trailing window of the last windowSize non-blank
+// lines, signaling that Claude is stuck retrying the same failing action
+// rather than making progress. Lines that scroll out of the window no
+// longer count, so the same error recurring after a long stretch of
+// unrelated output isn't treated as part of the same loop. A threshold or
+// windowSize of 0 or less falls back to DefaultErrorLoopThreshold /
+// DefaultErrorLoopWindow. Returns the loop and true on the line at which
+// the threshold is first reached, or a zero ErrorLoop and false if no line
+// loops.
+func DetectErrorLoop(output string, threshold int, windowSize int) (ErrorLoop, bool) {
+	if threshold <= 0 {
+		threshold = DefaultErrorLoopThreshold
+	}
+	if windowSize <= 0 {
+		windowSize = DefaultErrorLoopWindow
+	}
+
+	var window []string
+	counts := make(map[string]int)
+
+	for _, rawLine := range strings.Split(output, "\n") {
+		line := strings.TrimSpace(rawLine)
+		if line == "" {
+			continue
+		}
+
+		window = append(window, line)
+		counts[line]++
+		if len(window) > windowSize {
+			oldest := window[0]
+			window = window[1:]
+			counts[oldest]--
+			if counts[oldest] <= 0 {
+				delete(counts, oldest)
+			}
+		}
+
+		if isErrorLine(line) && counts[line] >= threshold {
+			return ErrorLoop{Line: line, Count: counts[line]}, true
+		}
+	}
+
+	return ErrorLoop{}, false
+}
@@ -0,0 +1,70 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestDetectErrorLoopFiresAfterThreshold(t *testing.T) {
+	output := "Error: connection refused\n" +
+		"retrying...\n" +
+		"Error: connection refused\n" +
+		"retrying...\n" +
+		"Error: connection refused\n"
+
+	loop, found := DetectErrorLoop(output, 3, DefaultErrorLoopWindow)
+	if !found {
+		t.Fatal("DetectErrorLoop() found = false, want true after the error repeats 3 times")
+	}
+	if loop.Line != "Error: connection refused" || loop.Count != 3 {
+		t.Errorf("DetectErrorLoop() = %+v, want Line=%q Count=3", loop, "Error: connection refused")
+	}
+}
+
+func TestDetectErrorLoopDoesNotFireBelowThreshold(t *testing.T) {
+	output := "Error: connection refused\n" +
+		"retrying...\n" +
+		"Error: connection refused\n"
+
+	_, found := DetectErrorLoop(output, 3, DefaultErrorLoopWindow)
+	if found {
+		t.Error("DetectErrorLoop() found = true, want false when the error has only repeated twice")
+	}
+}
+
+func TestDetectErrorLoopIgnoresNonErrorRepeats(t *testing.T) {
+	output := "Compiling...\n" +
+		"Compiling...\n" +
+		"Compiling...\n"
+
+	_, found := DetectErrorLoop(output, 3, DefaultErrorLoopWindow)
+	if found {
+		t.Error("DetectErrorLoop() found = true, want false since none of the repeated lines look like errors")
+	}
+}
+
+func TestDetectErrorLoopRespectsWindowSize(t *testing.T) {
+	// Two occurrences of the same error, separated by more lines than the
+	// window, so the second isn't within windowSize of the first and the
+	// threshold of 2 is never reached inside one window.
+	output := "Error: disk full\n" +
+		"line 1\nline 2\nline 3\nline 4\n" +
+		"Error: disk full\n"
+
+	_, found := DetectErrorLoop(output, 2, 2)
+	if found {
+		t.Error("DetectErrorLoop() found = true, want false since the repeats fall outside the small window")
+	}
+}
+
+func TestDetectErrorLoopUsesDefaultsWhenUnset(t *testing.T) {
+	var output string
+	for i := 0; i < DefaultErrorLoopThreshold; i++ {
+		output += "Exception: boom\n"
+	}
+
+	loop, found := DetectErrorLoop(output, 0, 0)
+	if !found || loop.Count != DefaultErrorLoopThreshold {
+		t.Errorf("DetectErrorLoop(0, 0) = %+v, found=%v, want the default threshold to apply", loop, found)
+	}
+}
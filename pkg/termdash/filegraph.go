@@ -0,0 +1,88 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"regexp"
+	"strings"
+)
+
+// fileGraphPattern matches the same tool-call echo lines as
+// touchedFilePattern, plus a plain "@path/to/file.ext" mention, capturing
+// whichever alternative matched: group 1 is a mention's path, groups 2/3
+// are a tool call's operation and path.
+var fileGraphPattern = regexp.MustCompile(`@([\w./-]+\.\w+)|(Read|Edit|Write|Update|MultiEdit)\(([^)]+)\)`)
+
+// FileGraphOpRead and FileGraphOpWrite classify a FileGraphEvent: whether
+// Claude read a file (including a bare "@file" mention, which only
+// references a file) or modified it.
+const (
+	FileGraphOpRead  = "read"
+	FileGraphOpWrite = "write"
+)
+
+// FileGraphEvent is one file reference in transcript order.
+type FileGraphEvent struct {
+	Path string
+	Op   string // FileGraphOpRead or FileGraphOpWrite
+}
+
+// FileGraph is a session's file-interaction graph: every file Claude read
+// or wrote, in the order those interactions happened, plus the derived
+// unique read and write sets. Richer than ExtractTouchedFiles, which
+// returns a single deduplicated list of every file read or modified, with
+// no read/write distinction and only first-appearance order.
+type FileGraph struct {
+	Events []FileGraphEvent
+	Reads  []string
+	Writes []string
+}
+
+// writeOps is the set of tool-call operation names that modify a file: the
+// Edit/Write/Update/MultiEdit subset of touchedFilePattern's operations.
+// Read(...) and @file mentions are read-only references.
+var writeOps = map[string]bool{
+	"Edit":      true,
+	"Write":     true,
+	"Update":    true,
+	"MultiEdit": true,
+}
+
+// DetectFileGraph scans transcript text for Read/Edit/Write/Update/
+// MultiEdit tool calls and "@file" mentions and returns the session's
+// file-interaction graph, in order of appearance.
+func DetectFileGraph(text string) *FileGraph {
+	graph := &FileGraph{}
+	seenRead := make(map[string]bool)
+	seenWrite := make(map[string]bool)
+	for _, m := range fileGraphPattern.FindAllStringSubmatch(text, -1) {
+		var path, op string
+		if m[1] != "" {
+			path, op = m[1], FileGraphOpRead
+		} else {
+			path = strings.TrimSpace(m[3])
+			if writeOps[m[2]] {
+				op = FileGraphOpWrite
+			} else {
+				op = FileGraphOpRead
+			}
+		}
+		if path == "" {
+			continue
+		}
+		graph.Events = append(graph.Events, FileGraphEvent{Path: path, Op: op})
+		if op == FileGraphOpWrite {
+			if !seenWrite[path] {
+				seenWrite[path] = true
+				graph.Writes = append(graph.Writes, path)
+			}
+		} else {
+			if !seenRead[path] {
+				seenRead[path] = true
+				graph.Reads = append(graph.Reads, path)
+			}
+		}
+	}
+	return graph
+}
@@ -0,0 +1,58 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectFileGraphSeparatesReadsAndWrites(t *testing.T) {
+	text := "⏺ Read(pkg/foo/bar.go)\nlooking things over\n⏺ Edit(pkg/foo/bar.go)\n⏺ Write(pkg/foo/baz.go)\n"
+	graph := DetectFileGraph(text)
+
+	wantReads := []string{"pkg/foo/bar.go"}
+	wantWrites := []string{"pkg/foo/bar.go", "pkg/foo/baz.go"}
+	if !reflect.DeepEqual(graph.Reads, wantReads) {
+		t.Errorf("Reads = %v, want %v", graph.Reads, wantReads)
+	}
+	if !reflect.DeepEqual(graph.Writes, wantWrites) {
+		t.Errorf("Writes = %v, want %v", graph.Writes, wantWrites)
+	}
+}
+
+func TestDetectFileGraphPreservesEventOrder(t *testing.T) {
+	text := "⏺ Read(a.go)\n⏺ Edit(a.go)\n⏺ Read(b.go)\n"
+	graph := DetectFileGraph(text)
+
+	want := []FileGraphEvent{
+		{Path: "a.go", Op: FileGraphOpRead},
+		{Path: "a.go", Op: FileGraphOpWrite},
+		{Path: "b.go", Op: FileGraphOpRead},
+	}
+	if !reflect.DeepEqual(graph.Events, want) {
+		t.Errorf("Events = %+v, want %+v", graph.Events, want)
+	}
+}
+
+func TestDetectFileGraphCountsAtFileMentionAsRead(t *testing.T) {
+	text := "let's look at @pkg/foo/bar.go before editing\n⏺ Edit(pkg/foo/bar.go)\n"
+	graph := DetectFileGraph(text)
+
+	wantReads := []string{"pkg/foo/bar.go"}
+	wantWrites := []string{"pkg/foo/bar.go"}
+	if !reflect.DeepEqual(graph.Reads, wantReads) {
+		t.Errorf("Reads = %v, want %v", graph.Reads, wantReads)
+	}
+	if !reflect.DeepEqual(graph.Writes, wantWrites) {
+		t.Errorf("Writes = %v, want %v", graph.Writes, wantWrites)
+	}
+}
+
+func TestDetectFileGraphNoMatchesReturnsEmptyGraph(t *testing.T) {
+	graph := DetectFileGraph("just some regular output\n")
+	if len(graph.Events) != 0 || len(graph.Reads) != 0 || len(graph.Writes) != 0 {
+		t.Errorf("DetectFileGraph() = %+v, want an empty graph", graph)
+	}
+}
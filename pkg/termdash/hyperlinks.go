@@ -0,0 +1,31 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "regexp"
+
+// osc8HyperlinkRegex matches a complete OSC 8 hyperlink: an opening
+// sequence carrying the URL (params are ignored), the visible text, and a
+// closing sequence with an empty URL. Accepts either terminator a terminal
+// may use (BEL or ST), matching ansiEscapeRegex's OSC handling.
+var osc8HyperlinkRegex = regexp.MustCompile(`\x1b\]8;[^;]*;([^\x07\x1b]*)(?:\x07|\x1b\\)([^\x1b]*)\x1b\]8;[^;]*;(?:\x07|\x1b\\)`)
+
+// Hyperlink is one OSC 8 terminal hyperlink extracted from text: its target
+// URL and the visible text a terminal would display for it.
+type Hyperlink struct {
+	URL  string
+	Text string
+}
+
+// ExtractHyperlinks scans text for OSC 8 hyperlink sequences and returns
+// each one's URL and visible text, in order of appearance. StripAnsi
+// already removes the escape machinery and leaves the visible text behind;
+// this is for a caller that also wants the URL.
+func ExtractHyperlinks(text string) []Hyperlink {
+	var links []Hyperlink
+	for _, m := range osc8HyperlinkRegex.FindAllStringSubmatch(text, -1) {
+		links = append(links, Hyperlink{URL: m[1], Text: m[2]})
+	}
+	return links
+}
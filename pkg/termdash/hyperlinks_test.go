@@ -0,0 +1,43 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractHyperlinksBELTerminated(t *testing.T) {
+	got := ExtractHyperlinks("see \x1b]8;;https://example.com\x07Example\x1b]8;;\x07 for more")
+	want := []Hyperlink{{URL: "https://example.com", Text: "Example"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractHyperlinks() = %v, want %v", got, want)
+	}
+}
+
+func TestExtractHyperlinksSTTerminated(t *testing.T) {
+	got := ExtractHyperlinks("see \x1b]8;;https://example.com\x1b\\Example\x1b]8;;\x1b\\ for more")
+	want := []Hyperlink{{URL: "https://example.com", Text: "Example"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractHyperlinks() = %v, want %v", got, want)
+	}
+}
+
+func TestExtractHyperlinksMultiple(t *testing.T) {
+	text := "\x1b]8;;https://a.example\x07A\x1b]8;;\x07 and \x1b]8;;https://b.example\x07B\x1b]8;;\x07"
+	got := ExtractHyperlinks(text)
+	want := []Hyperlink{
+		{URL: "https://a.example", Text: "A"},
+		{URL: "https://b.example", Text: "B"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractHyperlinks() = %v, want %v", got, want)
+	}
+}
+
+func TestExtractHyperlinksNoMatchReturnsNil(t *testing.T) {
+	if got := ExtractHyperlinks("just plain text"); got != nil {
+		t.Errorf("ExtractHyperlinks() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "regexp"
+
+// nonInteractiveFlagPattern matches the `-p`/`--print` flags that put
+// Claude Code into one-shot mode: print a response and exit, with no
+// interactive prompt ever shown.
+var nonInteractiveFlagPattern = regexp.MustCompile(`(?:^|\s)(-p|--print)(?:\s|$)`)
+
+// IsInteractiveCommand reports whether cmdStr looks like it launches Claude
+// interactively, as opposed to a one-shot `claude -p "..."` invocation that
+// should never get idle timers or needs-input detection.
+func IsInteractiveCommand(cmdStr string) bool {
+	return !nonInteractiveFlagPattern.MatchString(cmdStr)
+}
+
+// DetectNonInteractiveFromOutput reports whether output looks like a
+// one-shot run rather than an interactive session, for invocations where
+// the command line alone doesn't reveal it (e.g. `claude` wrapped in a
+// shell function or alias). An interactive session's terminal UI styles
+// its very first chunk of output with ANSI escape codes; `-p` output is
+// plain text.
+func DetectNonInteractiveFromOutput(output string) bool {
+	return output != "" && StripAnsi(output) == output
+}
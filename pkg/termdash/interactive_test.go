@@ -0,0 +1,57 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestIsInteractiveCommandDetectsPrintFlag(t *testing.T) {
+	cases := []struct {
+		cmdStr string
+		want   bool
+	}{
+		{"claude -p \"summarize this repo\"", false},
+		{"claude --print \"summarize this repo\"", false},
+		{"claude", true},
+		{"claude --resume abc123", true},
+		{"claude --permission-mode plan", true},
+	}
+	for _, c := range cases {
+		if got := IsInteractiveCommand(c.cmdStr); got != c.want {
+			t.Errorf("IsInteractiveCommand(%q) = %v, want %v", c.cmdStr, got, c.want)
+		}
+	}
+}
+
+func TestDetectNonInteractiveFromOutputPlainTextIsNonInteractive(t *testing.T) {
+	if !DetectNonInteractiveFromOutput("Here is a summary of the repo.\n") {
+		t.Error("expected plain-text output to be classified non-interactive")
+	}
+}
+
+func TestDetectNonInteractiveFromOutputAnsiIsInteractive(t *testing.T) {
+	if DetectNonInteractiveFromOutput("\x1b[1mClaude Code\x1b[0m\n") {
+		t.Error("expected ANSI-styled output to be classified interactive")
+	}
+}
+
+func TestDetectNonInteractiveFromOutputEmptyIsInteractive(t *testing.T) {
+	if DetectNonInteractiveFromOutput("") {
+		t.Error("expected empty output to not be classified non-interactive")
+	}
+}
+
+// TestNonInteractiveCommandSkipsStatusDetector mirrors the gate
+// ShellController applies before constructing a StatusDetector: a `-p`
+// invocation never gets one, so it can never receive an idle or
+// needs-input status transition.
+func TestNonInteractiveCommandSkipsStatusDetector(t *testing.T) {
+	cmdStr := `claude -p "summarize this repo"`
+	var sd *StatusDetector
+	if IsInteractiveCommand(cmdStr) {
+		sd = NewStatusDetector(func(string, string) {})
+	}
+	if sd != nil {
+		t.Fatal("expected no StatusDetector for a -p invocation")
+	}
+}
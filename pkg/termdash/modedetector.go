@@ -0,0 +1,49 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "regexp"
+
+const (
+	ClaudeModeDefault     = ""
+	ClaudeModePlan        = "plan"
+	ClaudeModeAcceptEdits = "accept-edits"
+)
+
+// modeFooterPattern matches the hint line Claude Code redraws at the
+// bottom of its UI on every mode footer refresh, whether or not a
+// non-default mode is active. Its presence tells us the chunk contains a
+// fresh footer redraw, so the mode it reports (or lack thereof) can be
+// trusted; without it, a chunk simply hasn't scrolled the footer into view
+// and shouldn't be treated as evidence the session left its current mode.
+var modeFooterPattern = regexp.MustCompile(`(?i)shift\+tab to cycle`)
+
+// modeBannerPatterns match the mode indicator banners Claude Code prints
+// alongside the footer hint when the current mode isn't the default. Order
+// matters: patterns are checked in order and the first match wins.
+var modeBannerPatterns = []struct {
+	pattern *regexp.Regexp
+	mode    string
+}{
+	{regexp.MustCompile(`(?i)plan mode on`), ClaudeModePlan},
+	{regexp.MustCompile(`(?i)accept edits on`), ClaudeModeAcceptEdits},
+}
+
+// DetectClaudeMode scans output for a fresh Claude Code mode footer and
+// returns the mode it reports and whether a footer was actually found. When
+// found is false, callers should leave any previously detected mode as-is:
+// the footer only redraws periodically, so its absence from a given chunk
+// doesn't mean the session left its current mode.
+func DetectClaudeMode(text string) (mode string, found bool) {
+	stripped := StripAnsi(text)
+	if !modeFooterPattern.MatchString(stripped) {
+		return ClaudeModeDefault, false
+	}
+	for _, p := range modeBannerPatterns {
+		if p.pattern.MatchString(stripped) {
+			return p.mode, true
+		}
+	}
+	return ClaudeModeDefault, true
+}
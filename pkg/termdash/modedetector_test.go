@@ -0,0 +1,46 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestDetectClaudeModeRecognizesPlanBanner(t *testing.T) {
+	banner := "⏸ plan mode on (shift+tab to cycle)"
+	mode, found := DetectClaudeMode(banner)
+	if !found {
+		t.Fatal("expected a mode footer to be found")
+	}
+	if mode != ClaudeModePlan {
+		t.Errorf("mode = %q, want %q", mode, ClaudeModePlan)
+	}
+}
+
+func TestDetectClaudeModeRecognizesAcceptEditsBanner(t *testing.T) {
+	banner := "⏵⏵ accept edits on (shift+tab to cycle)"
+	mode, found := DetectClaudeMode(banner)
+	if !found {
+		t.Fatal("expected a mode footer to be found")
+	}
+	if mode != ClaudeModeAcceptEdits {
+		t.Errorf("mode = %q, want %q", mode, ClaudeModeAcceptEdits)
+	}
+}
+
+func TestDetectClaudeModeFooterWithoutBannerIsDefault(t *testing.T) {
+	footer := "(shift+tab to cycle)"
+	mode, found := DetectClaudeMode(footer)
+	if !found {
+		t.Fatal("expected a mode footer to be found")
+	}
+	if mode != ClaudeModeDefault {
+		t.Errorf("mode = %q, want default", mode)
+	}
+}
+
+func TestDetectClaudeModeNoFooterReportsNotFound(t *testing.T) {
+	_, found := DetectClaudeMode("just some ordinary command output\n")
+	if found {
+		t.Error("expected no mode footer to be found in ordinary output")
+	}
+}
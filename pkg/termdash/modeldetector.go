@@ -0,0 +1,47 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "regexp"
+
+const (
+	ClaudeModelOpus   = "opus"
+	ClaudeModelSonnet = "sonnet"
+	ClaudeModelHaiku  = "haiku"
+)
+
+// modelBannerPatterns match the model name Claude Code prints in its
+// startup banner (e.g. "Model: claude-opus-4-20250514") and in its
+// footer status line (e.g. "claude-sonnet-4-5 · API Usage Billing"),
+// mapped to a canonical short model name. Order matters: patterns are
+// checked in order and the first match wins, so a longer/more specific
+// name (e.g. a full "claude-opus-..." slug) doesn't get shadowed by a
+// shorter one.
+var modelBannerPatterns = []struct {
+	pattern *regexp.Regexp
+	model   string
+}{
+	{regexp.MustCompile(`(?i)claude-opus[a-z0-9.\-]*`), ClaudeModelOpus},
+	{regexp.MustCompile(`(?i)claude-sonnet[a-z0-9.\-]*`), ClaudeModelSonnet},
+	{regexp.MustCompile(`(?i)claude-haiku[a-z0-9.\-]*`), ClaudeModelHaiku},
+	{regexp.MustCompile(`(?i)\bopus(?:[\s\-][0-9][0-9.]*)?\b`), ClaudeModelOpus},
+	{regexp.MustCompile(`(?i)\bsonnet(?:[\s\-][0-9][0-9.]*)?\b`), ClaudeModelSonnet},
+	{regexp.MustCompile(`(?i)\bhaiku(?:[\s\-][0-9][0-9.]*)?\b`), ClaudeModelHaiku},
+}
+
+// DetectClaudeModel scans output for a mention of the active Claude model,
+// in either the startup banner's full model slug (e.g.
+// "claude-opus-4-20250514") or the footer status line's short name (e.g.
+// "opus"), and returns the canonical short model name. Returns false if no
+// model mention is found, so callers can leave any previously detected
+// model as-is rather than clearing it.
+func DetectClaudeModel(text string) (model string, found bool) {
+	stripped := StripAnsi(text)
+	for _, p := range modelBannerPatterns {
+		if p.pattern.MatchString(stripped) {
+			return p.model, true
+		}
+	}
+	return "", false
+}
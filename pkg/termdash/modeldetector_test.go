@@ -0,0 +1,56 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestDetectClaudeModelRecognizesFullSlugInBanner(t *testing.T) {
+	banner := "Model: claude-opus-4-20250514"
+	model, found := DetectClaudeModel(banner)
+	if !found {
+		t.Fatal("expected a model mention to be found")
+	}
+	if model != ClaudeModelOpus {
+		t.Errorf("model = %q, want %q", model, ClaudeModelOpus)
+	}
+}
+
+func TestDetectClaudeModelRecognizesShortNameInStatusLine(t *testing.T) {
+	statusLine := "claude-sonnet-4-5 · API Usage Billing"
+	model, found := DetectClaudeModel(statusLine)
+	if !found {
+		t.Fatal("expected a model mention to be found")
+	}
+	if model != ClaudeModelSonnet {
+		t.Errorf("model = %q, want %q", model, ClaudeModelSonnet)
+	}
+}
+
+func TestDetectClaudeModelRecognizesBareShortName(t *testing.T) {
+	statusLine := "opus · 12% context left"
+	model, found := DetectClaudeModel(statusLine)
+	if !found {
+		t.Fatal("expected a model mention to be found")
+	}
+	if model != ClaudeModelOpus {
+		t.Errorf("model = %q, want %q", model, ClaudeModelOpus)
+	}
+}
+
+func TestDetectClaudeModelRecognizesHaiku(t *testing.T) {
+	model, found := DetectClaudeModel("Model: claude-haiku-4-5")
+	if !found {
+		t.Fatal("expected a model mention to be found")
+	}
+	if model != ClaudeModelHaiku {
+		t.Errorf("model = %q, want %q", model, ClaudeModelHaiku)
+	}
+}
+
+func TestDetectClaudeModelNoMentionReturnsFalse(t *testing.T) {
+	_, found := DetectClaudeModel("just some regular terminal output\n")
+	if found {
+		t.Error("expected no model mention to be found")
+	}
+}
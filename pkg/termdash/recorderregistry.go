@@ -0,0 +1,53 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"context"
+	"sync"
+)
+
+// activeRecorders tracks every TranscriptRecorder currently in use, so
+// FlushAllAndStop can reach recorders a caller forgot to Stop individually
+// (e.g. on application shutdown). Recorders register in
+// NewTranscriptRecorder and unregister in Stop.
+var (
+	activeRecordersMu sync.Mutex
+	activeRecorders   = map[*TranscriptRecorder]struct{}{}
+)
+
+// registerRecorder adds tr to the active recorder registry.
+func registerRecorder(tr *TranscriptRecorder) {
+	activeRecordersMu.Lock()
+	defer activeRecordersMu.Unlock()
+	activeRecorders[tr] = struct{}{}
+}
+
+// unregisterRecorder removes tr from the active recorder registry. Safe to
+// call more than once for the same recorder.
+func unregisterRecorder(tr *TranscriptRecorder) {
+	activeRecordersMu.Lock()
+	defer activeRecordersMu.Unlock()
+	delete(activeRecorders, tr)
+}
+
+// FlushAllAndStop stops and flushes every currently registered
+// TranscriptRecorder, intended for use during application shutdown so no
+// in-flight buffered transcript data is lost when a caller forgot to Stop
+// its recorder individually. ctx is accepted for symmetry with other
+// shutdown hooks and to leave room for a future flush target to honor a
+// deadline; today's flush is synchronous and in-memory, so it isn't
+// otherwise consulted.
+func FlushAllAndStop(ctx context.Context) {
+	activeRecordersMu.Lock()
+	recorders := make([]*TranscriptRecorder, 0, len(activeRecorders))
+	for tr := range activeRecorders {
+		recorders = append(recorders, tr)
+	}
+	activeRecordersMu.Unlock()
+
+	for _, tr := range recorders {
+		tr.Stop()
+	}
+}
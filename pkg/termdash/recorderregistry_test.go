@@ -0,0 +1,59 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFlushAllAndStopFlushesEveryRegisteredRecorder(t *testing.T) {
+	flushedA := make(chan []byte, 1)
+	flushedB := make(chan []byte, 1)
+
+	trA := NewTranscriptRecorder(func(data []byte) { flushedA <- data }, WithFlushStrategy(FlushStrategySizeOnly))
+	trB := NewTranscriptRecorder(func(data []byte) { flushedB <- data }, WithFlushStrategy(FlushStrategySizeOnly))
+	defer trA.Stop()
+	defer trB.Stop()
+
+	trA.RecordOutput([]byte("output from A"))
+	trB.RecordOutput([]byte("output from B"))
+
+	FlushAllAndStop(context.Background())
+
+	select {
+	case data := <-flushedA:
+		entries := parseTranscriptLines(t, data)
+		if len(entries) != 1 || entries[0].Text != "output from A" {
+			t.Errorf("recorder A flushed %v, want one entry with text %q", entries, "output from A")
+		}
+	case <-time.After(time.Second):
+		t.Error("recorder A was never flushed")
+	}
+
+	select {
+	case data := <-flushedB:
+		entries := parseTranscriptLines(t, data)
+		if len(entries) != 1 || entries[0].Text != "output from B" {
+			t.Errorf("recorder B flushed %v, want one entry with text %q", entries, "output from B")
+		}
+	case <-time.After(time.Second):
+		t.Error("recorder B was never flushed")
+	}
+}
+
+func TestFlushAllAndStopUnregistersRecorders(t *testing.T) {
+	tr := NewTranscriptRecorder(func(data []byte) {})
+	defer tr.Stop()
+
+	FlushAllAndStop(context.Background())
+
+	activeRecordersMu.Lock()
+	_, stillRegistered := activeRecorders[tr]
+	activeRecordersMu.Unlock()
+	if stillRegistered {
+		t.Error("recorder still registered after FlushAllAndStop")
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "regexp"
+
+// resumeBannerRegex matches the banner the claude CLI prints when resuming
+// or continuing a prior session, capturing the original session's UUID.
+var resumeBannerRegex = regexp.MustCompile(`(?i)(?:resuming|continuing) session[:\s]+([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})`)
+
+// DetectResumeSessionID scans early terminal output for a resume/continue
+// banner and returns the original session ID it references, if any.
+func DetectResumeSessionID(output string) (string, bool) {
+	m := resumeBannerRegex.FindStringSubmatch(output)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
@@ -0,0 +1,43 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestDetectResumeSessionID(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		wantID string
+		wantOK bool
+	}{
+		{
+			name:   "resuming banner",
+			input:  "Resuming session: 5f2c9b1a-2e3d-4b6a-9c7e-1a2b3c4d5e6f\nWelcome back\n",
+			wantID: "5f2c9b1a-2e3d-4b6a-9c7e-1a2b3c4d5e6f",
+			wantOK: true,
+		},
+		{
+			name:   "continuing banner",
+			input:  "Continuing session 5f2c9b1a-2e3d-4b6a-9c7e-1a2b3c4d5e6f from last message\n",
+			wantID: "5f2c9b1a-2e3d-4b6a-9c7e-1a2b3c4d5e6f",
+			wantOK: true,
+		},
+		{
+			name:   "no banner",
+			input:  "Building project...\n",
+			wantID: "",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotID, gotOK := DetectResumeSessionID(tt.input)
+			if gotID != tt.wantID || gotOK != tt.wantOK {
+				t.Errorf("DetectResumeSessionID(%q) = (%q, %v), want (%q, %v)", tt.input, gotID, gotOK, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
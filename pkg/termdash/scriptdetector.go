@@ -0,0 +1,82 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "unicode"
+
+const (
+	ScriptRTL = "rtl"
+	ScriptCJK = "cjk"
+)
+
+// isRTLRune reports whether r falls in a Hebrew or Arabic Unicode block, the
+// scripts a frontend needs to know about to render text right-to-left.
+func isRTLRune(r rune) bool {
+	switch {
+	case r >= 0x0590 && r <= 0x05FF: // Hebrew
+		return true
+	case r >= 0x0600 && r <= 0x06FF: // Arabic
+		return true
+	case r >= 0x0750 && r <= 0x077F: // Arabic Supplement
+		return true
+	case r >= 0xFB1D && r <= 0xFB4F: // Hebrew presentation forms
+		return true
+	case r >= 0xFB50 && r <= 0xFDFF: // Arabic presentation forms A
+		return true
+	case r >= 0xFE70 && r <= 0xFEFF: // Arabic presentation forms B
+		return true
+	}
+	return false
+}
+
+// isCJKRune reports whether r falls in a CJK Unicode block: Han ideographs,
+// Hiragana, Katakana, or Hangul.
+func isCJKRune(r rune) bool {
+	switch {
+	case r >= 0x4E00 && r <= 0x9FFF: // CJK Unified Ideographs
+		return true
+	case r >= 0x3040 && r <= 0x309F: // Hiragana
+		return true
+	case r >= 0x30A0 && r <= 0x30FF: // Katakana
+		return true
+	case r >= 0xAC00 && r <= 0xD7A3: // Hangul syllables
+		return true
+	}
+	return false
+}
+
+// DetectScript scans text for the dominant right-to-left (Arabic, Hebrew) or
+// CJK (Chinese, Japanese, Korean) script, so a frontend can adjust text
+// direction for sessions whose output is primarily in one of them. This is a
+// lightweight per-rune Unicode-range heuristic, not full script/language
+// detection: mixed output (e.g. a mostly-Latin session that prints one CJK
+// filename) won't false-positive unless that script actually dominates.
+// Returns "", false if neither script is dominant.
+func DetectScript(text string) (script string, found bool) {
+	stripped := StripAnsi(text)
+	var rtlCount, cjkCount, otherCount int
+	for _, r := range stripped {
+		switch {
+		case isRTLRune(r):
+			rtlCount++
+		case isCJKRune(r):
+			cjkCount++
+		case unicode.IsLetter(r):
+			otherCount++
+		}
+	}
+	if rtlCount == 0 && cjkCount == 0 {
+		return "", false
+	}
+	if rtlCount >= cjkCount {
+		if rtlCount <= otherCount {
+			return "", false
+		}
+		return ScriptRTL, true
+	}
+	if cjkCount <= otherCount {
+		return "", false
+	}
+	return ScriptCJK, true
+}
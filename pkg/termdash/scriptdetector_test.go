@@ -0,0 +1,63 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestDetectScriptRecognizesArabicAsRTL(t *testing.T) {
+	script, found := DetectScript("جارٍ تشغيل الاختبارات الآن، الرجاء الانتظار قليلاً\n")
+	if !found {
+		t.Fatal("expected a dominant script to be found")
+	}
+	if script != ScriptRTL {
+		t.Errorf("script = %q, want %q", script, ScriptRTL)
+	}
+}
+
+func TestDetectScriptRecognizesHebrewAsRTL(t *testing.T) {
+	script, found := DetectScript("מריץ את הבדיקות כעת, אנא המתן\n")
+	if !found {
+		t.Fatal("expected a dominant script to be found")
+	}
+	if script != ScriptRTL {
+		t.Errorf("script = %q, want %q", script, ScriptRTL)
+	}
+}
+
+func TestDetectScriptRecognizesChineseAsCJK(t *testing.T) {
+	script, found := DetectScript("正在运行测试，请稍候\n")
+	if !found {
+		t.Fatal("expected a dominant script to be found")
+	}
+	if script != ScriptCJK {
+		t.Errorf("script = %q, want %q", script, ScriptCJK)
+	}
+}
+
+func TestDetectScriptRecognizesJapaneseAsCJK(t *testing.T) {
+	script, found := DetectScript("テストを実行しています、お待ちください\n")
+	if !found {
+		t.Fatal("expected a dominant script to be found")
+	}
+	if script != ScriptCJK {
+		t.Errorf("script = %q, want %q", script, ScriptCJK)
+	}
+}
+
+func TestDetectScriptLatinOutputReturnsFalse(t *testing.T) {
+	_, found := DetectScript("Running tests...\n5 passed, 0 failed\n")
+	if found {
+		t.Error("expected no dominant script to be found for Latin output")
+	}
+}
+
+func TestDetectScriptStripsAnsiBeforeCounting(t *testing.T) {
+	script, found := DetectScript("\x1b[32m正在运行测试\x1b[0m\n")
+	if !found {
+		t.Fatal("expected a dominant script to be found")
+	}
+	if script != ScriptCJK {
+		t.Errorf("script = %q, want %q", script, ScriptCJK)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+// SessionMonitor wraps a StatusDetector and a TranscriptRecorder behind a
+// single interleaved-stream interface, so a caller with one PTY read loop
+// doesn't need to remember to fan each chunk out to both subsystems
+// separately (and can't accidentally update one but not the other).
+type SessionMonitor struct {
+	StatusDetector     *StatusDetector
+	TranscriptRecorder *TranscriptRecorder
+}
+
+// NewSessionMonitor creates a SessionMonitor, constructing its
+// StatusDetector and TranscriptRecorder with the given callback, flush
+// function, and options.
+func NewSessionMonitor(statusCallback StatusChangeCallback, flushFn TranscriptFlushFunc, detectorOpts []StatusDetectorOption, recorderOpts []TranscriptRecorderOption) *SessionMonitor {
+	return &SessionMonitor{
+		StatusDetector:     NewStatusDetector(statusCallback, detectorOpts...),
+		TranscriptRecorder: NewTranscriptRecorder(flushFn, recorderOpts...),
+	}
+}
+
+// HandleOutput fans a chunk of PTY output out to both the status detector
+// and the transcript recorder.
+func (sm *SessionMonitor) HandleOutput(data []byte) {
+	sm.StatusDetector.ProcessOutput(data)
+	sm.TranscriptRecorder.RecordOutput(data)
+}
+
+// HandleInput records a chunk of user input to the transcript. The status
+// detector has no input-side behavior, so this only reaches the recorder.
+func (sm *SessionMonitor) HandleInput(data []byte) {
+	sm.TranscriptRecorder.RecordInput(data)
+}
+
+// Stop stops both the status detector and the transcript recorder,
+// flushing any buffered transcript data.
+func (sm *SessionMonitor) Stop() {
+	sm.StatusDetector.Stop()
+	sm.TranscriptRecorder.Stop()
+}
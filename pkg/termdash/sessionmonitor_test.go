@@ -0,0 +1,91 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSessionMonitorHandleOutputReachesBothSubsystems(t *testing.T) {
+	var mu sync.Mutex
+	var statusChanges []string
+	flushCh := make(chan []byte, 4)
+	sm := NewSessionMonitor(
+		func(oldStatus, newStatus string) {
+			mu.Lock()
+			defer mu.Unlock()
+			statusChanges = append(statusChanges, newStatus)
+		},
+		func(data []byte) { flushCh <- data },
+		nil, nil,
+	)
+	defer sm.Stop()
+
+	sm.HandleOutput([]byte("Do you want to proceed?"))
+
+	// The status callback fires asynchronously (StatusDetector.setStatus runs
+	// it on its own goroutine), so poll instead of asserting synchronously.
+	deadline := time.After(time.Second)
+	for {
+		mu.Lock()
+		last := ""
+		if len(statusChanges) > 0 {
+			last = statusChanges[len(statusChanges)-1]
+		}
+		snapshot := append([]string(nil), statusChanges...)
+		mu.Unlock()
+		if last == StatusNeedsInput {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("statusChanges = %v, want the status detector to see the output and report needs-input", snapshot)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	sm.TranscriptRecorder.Stop()
+	flushed := <-flushCh
+	entries := ParseTranscriptEntries(flushed)
+	if len(entries) != 1 || entries[0].Type != "output" || entries[0].Text != "Do you want to proceed?" {
+		t.Errorf("entries = %v, want the transcript recorder to see the same output", entries)
+	}
+}
+
+func TestSessionMonitorHandleInputReachesTranscriptOnly(t *testing.T) {
+	flushCh := make(chan []byte, 4)
+	sm := NewSessionMonitor(nil, func(data []byte) { flushCh <- data }, nil, nil)
+	defer sm.Stop()
+
+	sm.HandleInput([]byte("y\n"))
+	sm.TranscriptRecorder.Stop()
+
+	flushed := <-flushCh
+	entries := ParseTranscriptEntries(flushed)
+	if len(entries) != 1 || entries[0].Type != "input" || entries[0].Text != "y\n" {
+		t.Errorf("entries = %v, want the transcript recorder to see the input", entries)
+	}
+}
+
+func TestSessionMonitorStopStopsBothSubsystems(t *testing.T) {
+	flushCh := make(chan []byte, 4)
+	sm := NewSessionMonitor(nil, func(data []byte) { flushCh <- data }, nil, nil)
+
+	sm.HandleOutput([]byte("some output"))
+	sm.Stop()
+
+	if sm.StatusDetector.GetStatus() != StatusActive {
+		t.Errorf("StatusDetector.GetStatus() = %q, want %q", sm.StatusDetector.GetStatus(), StatusActive)
+	}
+	select {
+	case flushed := <-flushCh:
+		if len(flushed) == 0 {
+			t.Error("expected Stop to flush buffered transcript data")
+		}
+	case <-time.After(time.Second):
+		t.Error("expected Stop to flush buffered transcript data")
+	}
+}
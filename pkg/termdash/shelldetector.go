@@ -0,0 +1,45 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "regexp"
+
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+)
+
+// shellBannerPatterns match a session's shell revealing itself in its early
+// output, either via an exported $SHELL/$0 value echoed by the session's
+// init script (e.g. "SHELL=/bin/zsh") or via a shell-specific
+// command-not-found error, which is a common way a shell's identity leaks
+// into the first bit of output without the session ever printing $SHELL
+// directly. Order doesn't matter: each pattern names a single shell.
+var shellBannerPatterns = []struct {
+	pattern *regexp.Regexp
+	shell   string
+}{
+	{regexp.MustCompile(`(?i)/zsh\b`), ShellZsh},
+	{regexp.MustCompile(`(?i)\bzsh:`), ShellZsh},
+	{regexp.MustCompile(`(?i)/fish\b`), ShellFish},
+	{regexp.MustCompile(`(?i)\bfish:`), ShellFish},
+	{regexp.MustCompile(`(?i)/bash\b`), ShellBash},
+	{regexp.MustCompile(`(?i)\bbash:`), ShellBash},
+}
+
+// DetectShell scans output for a mention of the session's shell, in either
+// an echoed $SHELL path or a shell-specific error message, and returns the
+// canonical shell name (ShellBash, ShellZsh, or ShellFish). Returns false if
+// no shell mention is found, so callers can leave any previously detected
+// shell as-is rather than clearing it.
+func DetectShell(text string) (shell string, found bool) {
+	stripped := StripAnsi(text)
+	for _, p := range shellBannerPatterns {
+		if p.pattern.MatchString(stripped) {
+			return p.shell, true
+		}
+	}
+	return "", false
+}
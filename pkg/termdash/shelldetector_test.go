@@ -0,0 +1,43 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestDetectShellRecognizesZshFromShellEnvPath(t *testing.T) {
+	shell, found := DetectShell("SHELL=/usr/bin/zsh\n")
+	if !found {
+		t.Fatal("expected a shell mention to be found")
+	}
+	if shell != ShellZsh {
+		t.Errorf("shell = %q, want %q", shell, ShellZsh)
+	}
+}
+
+func TestDetectShellRecognizesBashFromCommandNotFoundError(t *testing.T) {
+	shell, found := DetectShell("bash: foo: command not found\n")
+	if !found {
+		t.Fatal("expected a shell mention to be found")
+	}
+	if shell != ShellBash {
+		t.Errorf("shell = %q, want %q", shell, ShellBash)
+	}
+}
+
+func TestDetectShellRecognizesFish(t *testing.T) {
+	shell, found := DetectShell("fish: Unknown command: foo\n")
+	if !found {
+		t.Fatal("expected a shell mention to be found")
+	}
+	if shell != ShellFish {
+		t.Errorf("shell = %q, want %q", shell, ShellFish)
+	}
+}
+
+func TestDetectShellNoMentionReturnsFalse(t *testing.T) {
+	_, found := DetectShell("just some regular terminal output\n")
+	if found {
+		t.Error("expected no shell mention to be found")
+	}
+}
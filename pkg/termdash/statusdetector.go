@@ -4,82 +4,546 @@
 package termdash
 
 import (
+	"context"
+	"encoding/json"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
 const (
-	StatusActive     = "active"
-	StatusNeedsInput = "needs-input"
-	StatusIdle       = "idle"
-	StatusExited     = "exited"
+	StatusActive      = "active"
+	StatusNeedsInput  = "needs-input"
+	StatusIdle        = "idle"
+	StatusExited      = "exited"
+	StatusRateLimited = "rate-limited"
+	StatusToolRunning = "tool-running"
+
+	// StatusError means recent output looked like a crash or fatal error
+	// (see errorPatterns) that is still within the last few lines
+	// classifyLocked inspects. It outranks a prompt in those same lines
+	// (see statusClassifiers). Distinct from StatusExited: the process may still
+	// be running (e.g. a caught panic that printed a stack trace and kept
+	// going, or a shell command that failed with "command not found").
+	StatusError = "error"
+
+	// IdleReasonNone means the session isn't idle in a way GetIdleReason
+	// classifies; check GetStatus for the actual status.
+	IdleReasonNone = ""
+
+	// IdleReasonWaitingForUser means Claude has stopped producing output and
+	// is genuinely waiting on the user's next instruction, worth notifying
+	// about.
+	IdleReasonWaitingForUser = "waiting-for-user"
+
+	// IdleReasonUserAway means Claude is still actively working but the
+	// user's last input predates Claude's most recent output, i.e. the user
+	// stepped away while Claude kept going -- not worth notifying about.
+	IdleReasonUserAway = "user-away"
+
+	// NeedsInputKindConfirmation and NeedsInputKindQuestion are the two
+	// sub-types of StatusNeedsInput: a yes/no-style permission prompt the
+	// UI can render as buttons, versus an open-ended clarifying question
+	// that needs a text response.
+	NeedsInputKindConfirmation = "confirmation"
+	NeedsInputKindQuestion     = "question"
 
 	// How long after last output before transitioning to idle
 	IdleTimeout = 10 * time.Second
 
-	// Minimum time between status change callbacks to avoid rapid flapping
+	// MaxIdleBackoffShift caps exponential idle-timeout growth (used with
+	// WithIdleBackoff) at IdleTimeout*2^MaxIdleBackoffShift.
+	MaxIdleBackoffShift = 4
+
+	// MinActiveDurationForReset is how long a session must stay active
+	// before going idle again for that period to count as genuine activity
+	// (used with WithIdleBackoff) and reset the backoff, rather than a brief
+	// flicker that keeps lengthening the idle timeout.
+	MinActiveDurationForReset = IdleTimeout
+
+	// Minimum time between status change callbacks to avoid rapid flapping.
+	// This is the starting point for the adaptive debounce implemented by
+	// recordFlapLocked/decayDebounceLocked, not a fixed value.
 	DebounceInterval = 500 * time.Millisecond
 
+	// MinDebounceInterval and MaxDebounceInterval cap how far the adaptive
+	// debounce can shrink or grow.
+	MinDebounceInterval = 100 * time.Millisecond
+	MaxDebounceInterval = 5 * time.Second
+
+	// FlapWindow is the rolling window over which suppressed status changes
+	// are counted as flapping. FlapGrowThreshold is how many suppressed
+	// changes within that window double the debounce interval.
+	FlapWindow        = 5 * time.Second
+	FlapGrowThreshold = 3
+
 	// Max bytes to keep in the line buffer
 	MaxLineBufferSize = 4096
+
+	// How long a weak prompt match (bare ">" or "$") must persist without
+	// new output before it's trusted as needs-input. Strong prompts skip
+	// this settling period entirely.
+	WeakPromptSettleWindow = 300 * time.Millisecond
+
+	// DefaultClassifyThrottle bounds how often ProcessOutput re-runs prompt
+	// pattern classification. Status can't meaningfully change faster than
+	// DebounceInterval anyway, so under a high output rate this caps
+	// regex-matching CPU while every call still appends to the line buffer.
+	DefaultClassifyThrottle = 50 * time.Millisecond
+)
+
+// compilePromptPatterns compiles each pattern source, optionally prefixing
+// it with the (?m) flag so a trailing "$" anchors against the end of any
+// line in the matched text rather than only the very end of it. Go's
+// regexp treats "$" as end-of-text by default, so a strong prompt like
+// "❯\s*$" fails to match once a trailing status line (or any further
+// output) has been appended after the prompt line within the
+// classification window.
+func compilePromptPatterns(sources []string, multiline bool) []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(sources))
+	for i, src := range sources {
+		if multiline {
+			src = "(?m)" + src
+		}
+		patterns[i] = regexp.MustCompile(src)
+	}
+	return patterns
+}
+
+// strongPromptPatternSources match output that unambiguously indicates
+// Claude is waiting for user input, such as an explicit permission dialog.
+// These transition to needs-input immediately.
+var strongPromptPatternSources = []string{
+	`❯\s*$`,                          // Claude main prompt
+	`\?\s*\(?(yes|no|y\/n)\)?`,       // Yes/no confirmation prompt
+	`Do you want to proceed`,         // Claude permission prompt
+	`\(Y\)es.*\(N\)o`,                // Claude Y/N prompt
+	`Press Enter to continue`,        // Continue prompt
+	`\[Y/n\]`,                        // Standard Y/n prompt
+	`waiting for (?:input|response)`, // Explicit waiting messages
+}
+
+var (
+	strongPromptPatterns          = compilePromptPatterns(strongPromptPatternSources, false)
+	strongPromptPatternsMultiline = compilePromptPatterns(strongPromptPatternSources, true)
+)
+
+// confirmationPromptPatterns is the subset of strongPromptPatterns that
+// specifically ask for a yes/no-style decision, used by
+// classifyNeedsInputKind to distinguish a confirmation from an open-ended
+// question. Every pattern here also appears in strongPromptPatterns.
+var confirmationPromptPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\?\s*\(?(yes|no|y\/n)\)?`), // Yes/no confirmation prompt
+	regexp.MustCompile(`Do you want to proceed`),   // Claude permission prompt
+	regexp.MustCompile(`\(Y\)es.*\(N\)o`),          // Claude Y/N prompt
+	regexp.MustCompile(`Press Enter to continue`),  // Continue prompt
+	regexp.MustCompile(`\[Y/n\]`),                  // Standard Y/n prompt
+}
+
+// questionPromptPatternSources match output that looks like Claude asking
+// an open-ended clarifying question ("Which file did you mean?") rather
+// than a yes/no confirmation. Checked after strongPromptPatterns so a
+// yes/no prompt (which also ends in "?") is classified as a confirmation
+// first.
+var questionPromptPatternSources = []string{
+	`\?\s*$`, // line ending in a question mark
+}
+
+var (
+	questionPromptPatterns          = compilePromptPatterns(questionPromptPatternSources, false)
+	questionPromptPatternsMultiline = compilePromptPatterns(questionPromptPatternSources, true)
+)
+
+// weakPromptPatternSources match output that merely looks prompt-like, such
+// as a bare ">" or "$" at the end of a line. These are easily
+// false-positived by mid-stream text (e.g. a ">" inside quoted output), so
+// they only settle into needs-input after WeakPromptSettleWindow with no
+// further output.
+var weakPromptPatternSources = []string{
+	`>\s*$`,  // Continuation prompt
+	`\$\s*$`, // Shell prompt (after Claude exits)
+}
+
+var (
+	weakPromptPatterns          = compilePromptPatterns(weakPromptPatternSources, false)
+	weakPromptPatternsMultiline = compilePromptPatterns(weakPromptPatternSources, true)
+)
+
+// zshWeakPromptPatternSources extends weakPromptPatternSources with zsh's
+// default prompt character ("%"), which bash and fish sessions never emit
+// on their own and so would otherwise false-positive on stray "%" output
+// (e.g. a progress percentage). Used only once SetShellType has identified
+// the session as zsh.
+var zshWeakPromptPatternSources = append(append([]string{}, weakPromptPatternSources...), `%\s*$`)
+
+var (
+	zshWeakPromptPatterns          = compilePromptPatterns(zshWeakPromptPatternSources, false)
+	zshWeakPromptPatternsMultiline = compilePromptPatterns(zshWeakPromptPatternSources, true)
 )
 
-// Patterns that indicate Claude is waiting for user input.
-// These match the end of terminal output when Claude's prompt is displayed.
-var promptPatterns = []*regexp.Regexp{
-	regexp.MustCompile(`❯\s*$`),                       // Claude main prompt
-	regexp.MustCompile(`>\s*$`),                        // Continuation prompt
-	regexp.MustCompile(`\$\s*$`),                       // Shell prompt (after Claude exits)
-	regexp.MustCompile(`\?\s*\(?(yes|no|y\/n)\)?`),       // Yes/no confirmation prompt
-	regexp.MustCompile(`Do you want to proceed`),       // Claude permission prompt
-	regexp.MustCompile(`\(Y\)es.*\(N\)o`),              // Claude Y/N prompt
-	regexp.MustCompile(`Press Enter to continue`),      // Continue prompt
-	regexp.MustCompile(`\[Y/n\]`),                      // Standard Y/n prompt
-	regexp.MustCompile(`waiting for (?:input|response)`), // Explicit waiting messages
+// rateLimitPatterns match output indicating Claude has hit an API rate limit
+// and is waiting to retry, so ProcessOutput can distinguish this from
+// ordinary idleness rather than letting the idle timer eventually fire and
+// mislead the user into thinking nothing is happening.
+var rateLimitPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)rate.?limit`),
+	regexp.MustCompile(`(?i)usage limit reached`),
+	regexp.MustCompile(`(?i)retrying in`),
+}
+
+// errorPatterns matches output that looks like a crash, fatal error, or
+// unhandled exception, regardless of language/runtime. Deliberately narrow
+// (fixed strings/prefixes a real crash reliably prints) rather than a broad
+// heuristic like "contains the word error", to avoid misclassifying normal
+// output that merely mentions an error in passing.
+var errorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`panic:`),
+	regexp.MustCompile(`(?i)fatal error:`),
+	regexp.MustCompile(`Traceback \(most recent call last\)`),
+	regexp.MustCompile(`(?i)command not found`),
+}
+
+// toolStartPattern matches Claude CLI's echo line for invoking a tool that
+// can run for a long time with no further output in between, e.g.
+// "⏺ Bash(npm test)", capturing the command. Modeled on touchedFilePattern
+// in touchedfiles.go, which matches the same CLI convention for
+// file-editing tools.
+var toolStartPattern = regexp.MustCompile(`⏺\s*Bash\(([^)]+)\)`)
+
+// toolCompletionPattern matches Claude CLI's result-marker line, printed
+// once a tool call's output is ready.
+var toolCompletionPattern = regexp.MustCompile(`⎿`)
+
+// rateLimitWaitRegex extracts a wait duration from a rate-limit message,
+// e.g. "retrying in 30 seconds" or "try again in 4 minutes".
+var rateLimitWaitRegex = regexp.MustCompile(`(?i)(\d+)\s*(hour|hr|minute|min|second|sec)s?`)
+
+// rateLimitUnitDurations maps the unit captured by rateLimitWaitRegex to its
+// time.Duration multiplier.
+var rateLimitUnitDurations = map[string]time.Duration{
+	"hour":   time.Hour,
+	"hr":     time.Hour,
+	"minute": time.Minute,
+	"min":    time.Minute,
+	"second": time.Second,
+	"sec":    time.Second,
+}
+
+// parseRateLimitWait extracts the wait duration from a rate-limit message, if
+// one is present, e.g. "30" and "seconds" from "retrying in 30 seconds".
+// Returns 0, false if no duration could be found.
+func parseRateLimitWait(text string) (time.Duration, bool) {
+	match := rateLimitWaitRegex.FindStringSubmatch(text)
+	if match == nil {
+		return 0, false
+	}
+	count, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	unit, ok := rateLimitUnitDurations[strings.ToLower(match[2])]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(count) * unit, true
 }
 
 // StatusChangeCallback is called when the Claude session status changes.
 // oldStatus may be empty on first detection.
 type StatusChangeCallback func(oldStatus, newStatus string)
 
+// Alternate-screen escape sequences used by full-screen programs like
+// tmux, screen, vim, and less. While the alternate screen is active, the
+// inner program owns the display and prompt-based detection is unreliable.
+var (
+	altScreenEnterRegex = regexp.MustCompile(`\x1b\[\?1049h`)
+	altScreenExitRegex  = regexp.MustCompile(`\x1b\[\?1049l`)
+)
+
 // StatusDetector monitors terminal output from a Claude Code session
 // and detects status transitions (active, needs-input, idle, exited).
 type StatusDetector struct {
-	mu            sync.Mutex
-	lineBuffer    string
-	currentStatus string
-	lastOutputAt  time.Time
-	lastChangeAt  time.Time
-	idleTimer     *time.Timer
-	callback      StatusChangeCallback
-	stopped       bool
+	mu              sync.Mutex
+	lineBuffer      string
+	currentStatus   string
+	needsInputKind  string // NeedsInputKindConfirmation or NeedsInputKindQuestion, meaningful only while currentStatus is StatusNeedsInput
+	lastOutputAt    time.Time
+	lastInputAt     time.Time // last time ProcessInput was called, i.e. the user typed something
+	lastChangeAt    time.Time
+	idleTimer       *time.Timer
+	callback        StatusChangeCallback
+	idleCallback    func() // set via OnIdle; fired specifically on a transition into StatusIdle
+	stopped         bool
+	stopOnce        sync.Once
+	stopCh          chan struct{} // closed by Stop, so a context-watch goroutine started by NewStatusDetectorWithContext can exit without leaking
+	inAltScreen     bool
+	weakPromptTimer *time.Timer
+	weakPromptSince time.Time
+
+	// classifyThrottle, lastClassifyAt, classifyPending, and classifyTimer
+	// implement the ProcessOutput classification throttle: at most one
+	// classification per classifyThrottle, with a trailing call scheduled
+	// so the final chunk of a burst still gets classified.
+	classifyThrottle time.Duration
+	lastClassifyAt   time.Time
+	classifyPending  bool
+	classifyTimer    *time.Timer
+	classifyCount    int // number of times classifyLocked has run; for tests/benchmarks
+
+	// currentDebounce, flapCount, and flapWindowStart implement the adaptive
+	// debounce: a status change suppressed by the debounce counts as a
+	// flap, and FlapGrowThreshold flaps within FlapWindow double
+	// currentDebounce (capped at MaxDebounceInterval). A change that goes
+	// through cleanly after a full FlapWindow of calm halves it back down
+	// (floored at MinDebounceInterval), so a session that settles down
+	// becomes responsive again instead of staying debounced at its noisiest
+	// rate forever.
+	currentDebounce time.Duration
+	flapCount       int
+	flapWindowStart time.Time
+
+	// idleBackoffEnabled, idleBackoffCount, and activeSince implement
+	// WithIdleBackoff: repeated idle transitions without a sustained active
+	// period between them progressively lengthen the idle timeout, cutting
+	// notification churn for sessions that flicker active/idle.
+	idleBackoffEnabled bool
+	idleBackoffCount   int
+	activeSince        time.Time
+
+	// outputPreprocessor, when set via WithOutputPreprocessor, runs on the
+	// ANSI-stripped output before prompt matching.
+	outputPreprocessor func(string) string
+
+	// rateLimitWait holds the most recently parsed wait duration from a rate
+	// -limit message, meaningful only while currentStatus is
+	// StatusRateLimited. Cleared once output resumes.
+	rateLimitWait time.Duration
+
+	// pendingToolName holds the name of a tool invocation echoed by
+	// toolStartPattern whose completion output (toolCompletionPattern)
+	// hasn't appeared yet. If output goes quiet for idleTimeout while this
+	// is set, checkIdleLocked classifies the session as StatusToolRunning
+	// instead of StatusIdle, since Claude is waiting on the tool rather
+	// than genuinely idle.
+	pendingToolName string
+
+	// multilineAnchoring, when set via WithMultilinePromptAnchoring, selects
+	// the (?m)-compiled prompt pattern variants so a "$" anchor matches any
+	// line ending in the classification window, not just the very end.
+	multilineAnchoring bool
+
+	// shellType holds the session's detected shell (ShellBash, ShellZsh, or
+	// ShellFish), set via SetShellType once DetectShell identifies it from
+	// output. Empty until then, in which case classifyLocked falls back to
+	// the shell-agnostic weak prompt patterns.
+	shellType string
+
+	// strongPatternOverride, questionPatternOverride, and weakPatternOverride,
+	// set via WithPromptPatterns, replace the corresponding default pattern
+	// set entirely. Nil leaves that category's default (shell/multiline
+	// -aware) selection in place.
+	strongPatternOverride   []*regexp.Regexp
+	questionPatternOverride []*regexp.Regexp
+	weakPatternOverride     []*regexp.Regexp
+
+	// customPromptPatterns holds patterns registered via AddPromptPattern,
+	// consulted alongside (not instead of) the strong prompt set. Guarded by
+	// mu since AddPromptPattern can be called concurrently with
+	// ProcessOutput's PTY read loop.
+	customPromptPatterns []*regexp.Regexp
+
+	// history is a fixed-capacity ring buffer of the most recent status
+	// changes, recorded by setStatus for the History accessor. Debounced
+	// (suppressed) changes are not recorded, only ones that actually applied.
+	history []StatusChangeRecord
+}
+
+// MaxStatusHistory caps the number of changes retained by History; once
+// full, recording a new change evicts the oldest.
+const MaxStatusHistory = 64
+
+// StatusChangeRecord records a single status change for History: the status
+// before and after, and when it happened. Distinct from StatusTransition,
+// which describes a segment of a persisted transcript's status timeline
+// rather than a live detector's in-memory change log.
+type StatusChangeRecord struct {
+	OldStatus string
+	NewStatus string
+	At        time.Time
+}
+
+// History returns a copy of the recorded status changes, oldest first,
+// capped at MaxStatusHistory entries. Useful for debugging flapping
+// detection or letting a UI render a timeline of recent changes. Safe to
+// call concurrently with ProcessOutput.
+func (sd *StatusDetector) History() []StatusChangeRecord {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	out := make([]StatusChangeRecord, len(sd.history))
+	copy(out, sd.history)
+	return out
+}
+
+// StatusDetectorOption configures optional behavior on a StatusDetector.
+type StatusDetectorOption func(*StatusDetector)
+
+// WithInitialStatus seeds the detector's starting status instead of the
+// default unknown (empty) status, e.g. from InferStatusFromTranscript when
+// reattaching to a session that already has output. An empty status leaves
+// the default in place.
+func WithInitialStatus(status string) StatusDetectorOption {
+	return func(sd *StatusDetector) {
+		if status != "" {
+			sd.currentStatus = status
+		}
+	}
+}
+
+// WithIdleBackoff enables backoff-aware idle detection: each idle
+// transition that follows less than MinActiveDurationForReset of active
+// time doubles the idle timeout (capped at MaxIdleBackoffShift doublings),
+// so a session that flickers active/idle stops generating an idle
+// notification every few seconds. A sustained active period resets the
+// timeout back to IdleTimeout.
+func WithIdleBackoff() StatusDetectorOption {
+	return func(sd *StatusDetector) {
+		sd.idleBackoffEnabled = true
+	}
+}
+
+// WithClassifyThrottle overrides DefaultClassifyThrottle, e.g. to disable
+// throttling entirely (a zero duration classifies on every call) or widen
+// it under known-heavy output.
+func WithClassifyThrottle(d time.Duration) StatusDetectorOption {
+	return func(sd *StatusDetector) {
+		sd.classifyThrottle = d
+	}
+}
+
+// WithDebounceInterval overrides DebounceInterval as the detector's starting
+// debounce duration, e.g. to make a session more or less tolerant of rapid
+// status flapping before FlapGrowThreshold's adaptive doubling kicks in.
+// StatusExited and StatusError always bypass the debounce regardless of this
+// setting.
+func WithDebounceInterval(d time.Duration) StatusDetectorOption {
+	return func(sd *StatusDetector) {
+		sd.currentDebounce = d
+	}
+}
+
+// WithOutputPreprocessor runs fn on each chunk's ANSI-stripped output before
+// prompt matching, so a deployment that wraps Claude in a TUI adding its own
+// framing (e.g. a fixed prefix per line) can strip or normalize it before
+// the detector's patterns ever see it.
+func WithOutputPreprocessor(fn func(string) string) StatusDetectorOption {
+	return func(sd *StatusDetector) {
+		sd.outputPreprocessor = fn
+	}
+}
+
+// WithMultilinePromptAnchoring compiles prompt patterns with Go regexp's
+// (?m) flag, so a "$" anchor matches the end of any line within the
+// classification window rather than only the very end of the joined text.
+// Enable this for sessions whose terminal UI leaves a trailing status line
+// after the prompt line, which would otherwise prevent a "$"-anchored
+// pattern like the main prompt from ever matching.
+func WithMultilinePromptAnchoring() StatusDetectorOption {
+	return func(sd *StatusDetector) {
+		sd.multilineAnchoring = true
+	}
+}
+
+// WithPromptPatterns replaces the default strong/question/weak prompt
+// pattern sets entirely, for a deployment running a customized Claude
+// prompt or a different agent CLI whose prompts don't match the built-in
+// heuristics. A nil slice leaves that category's default (shell/multiline
+// -aware) selection in place; pass the others as nil to override just one
+// category.
+func WithPromptPatterns(strong, question, weak []*regexp.Regexp) StatusDetectorOption {
+	return func(sd *StatusDetector) {
+		sd.strongPatternOverride = strong
+		sd.questionPatternOverride = question
+		sd.weakPatternOverride = weak
+	}
+}
+
+// AddPromptPattern registers an additional pattern treated the same as a
+// strong prompt match: an unambiguous signal Claude is waiting on input,
+// checked alongside the default (or WithPromptPatterns-overridden) strong
+// set rather than replacing it. For a customized prompt suffix or another
+// agent CLI's prompt format the built-in heuristics don't recognize. Safe
+// to call concurrently with ProcessOutput.
+func (sd *StatusDetector) AddPromptPattern(re *regexp.Regexp) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	sd.customPromptPatterns = append(sd.customPromptPatterns, re)
 }
 
 // NewStatusDetector creates a new detector that will call the callback
-// whenever the Claude session status changes.
-func NewStatusDetector(callback StatusChangeCallback) *StatusDetector {
+// whenever the Claude session status changes. The detector starts in an
+// unknown state (GetStatus returns "") until the first ProcessOutput call
+// classifies it, at which point the callback fires with oldStatus == "" --
+// see StatusChangeCallback. Use WithInitialStatus to seed a known starting
+// status instead, e.g. when reattaching to a session with prior output.
+func NewStatusDetector(callback StatusChangeCallback, opts ...StatusDetectorOption) *StatusDetector {
 	sd := &StatusDetector{
-		currentStatus: StatusActive,
-		lastOutputAt:  time.Now(),
-		callback:      callback,
+		currentStatus:    "",
+		lastOutputAt:     time.Now(),
+		lastInputAt:      time.Now(),
+		activeSince:      time.Now(),
+		callback:         callback,
+		classifyThrottle: DefaultClassifyThrottle,
+		currentDebounce:  DebounceInterval,
+		flapWindowStart:  time.Now(),
+		stopCh:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(sd)
 	}
 	sd.startIdleTimer()
 	return sd
 }
 
+// NewStatusDetectorWithContext creates a detector exactly like
+// NewStatusDetector, but also stops it (as Stop would) as soon as ctx is
+// done, so a caller with a context-scoped lifecycle doesn't need its own
+// goroutine watching for cancellation. The watch goroutine exits on
+// whichever comes first, ctx.Done or an explicit Stop call, so calling Stop
+// manually never leaks it.
+func NewStatusDetectorWithContext(ctx context.Context, callback StatusChangeCallback, opts ...StatusDetectorOption) *StatusDetector {
+	sd := NewStatusDetector(callback, opts...)
+	go func() {
+		select {
+		case <-ctx.Done():
+			sd.Stop()
+		case <-sd.stopCh:
+		}
+	}()
+	return sd
+}
+
+// idleTimeout returns the idle timeout to apply for the next check: the
+// base IdleTimeout, or a backed-off multiple of it when WithIdleBackoff is
+// enabled and prior idle transitions have grown the backoff.
+func (sd *StatusDetector) idleTimeout() time.Duration {
+	if !sd.idleBackoffEnabled || sd.idleBackoffCount == 0 {
+		return IdleTimeout
+	}
+	shift := sd.idleBackoffCount
+	if shift > MaxIdleBackoffShift {
+		shift = MaxIdleBackoffShift
+	}
+	return IdleTimeout * time.Duration(int64(1)<<uint(shift))
+}
+
 func (sd *StatusDetector) startIdleTimer() {
-	sd.idleTimer = time.AfterFunc(IdleTimeout, func() {
+	sd.idleTimer = time.AfterFunc(sd.idleTimeout(), func() {
 		sd.mu.Lock()
 		defer sd.mu.Unlock()
-		if sd.stopped {
-			return
-		}
-		// Only transition to idle if we're currently active and haven't received output recently
-		if sd.currentStatus == StatusActive && time.Since(sd.lastOutputAt) >= IdleTimeout {
-			sd.setStatus(StatusIdle)
-		}
+		sd.checkIdleLocked()
 	})
 }
 
@@ -88,36 +552,135 @@ func (sd *StatusDetector) resetIdleTimer() {
 		sd.idleTimer.Stop()
 	}
 	if !sd.stopped {
-		sd.idleTimer = time.AfterFunc(IdleTimeout, func() {
-			sd.mu.Lock()
-			defer sd.mu.Unlock()
-			if sd.stopped {
-				return
-			}
-			if sd.currentStatus == StatusActive && time.Since(sd.lastOutputAt) >= IdleTimeout {
-				sd.setStatus(StatusIdle)
+		sd.startIdleTimer()
+	}
+}
+
+// checkIdleLocked evaluates the idle condition and transitions to
+// StatusIdle if warranted. Must be called with mu held.
+func (sd *StatusDetector) checkIdleLocked() {
+	if sd.stopped {
+		return
+	}
+	// Only transition to idle if we're currently active and haven't received output recently
+	if sd.currentStatus == StatusActive && time.Since(sd.lastOutputAt) >= sd.idleTimeout() {
+		if sd.pendingToolName != "" {
+			// Quiet, but Claude is waiting on a tool it already announced,
+			// not genuinely idle. Doesn't touch idleBackoffCount: this isn't
+			// the flappy active/idle flicker the backoff guards against.
+			sd.setStatus(StatusToolRunning)
+			return
+		}
+		if sd.idleBackoffEnabled {
+			if time.Since(sd.activeSince) >= MinActiveDurationForReset {
+				sd.idleBackoffCount = 0
+			} else {
+				sd.idleBackoffCount++
 			}
-		})
+		}
+		sd.setStatus(StatusIdle)
 	}
 }
 
+// CheckIdle immediately evaluates the idle condition (based on lastOutputAt
+// and IdleTimeout) and transitions to StatusIdle if warranted, without
+// waiting for the internal timer to fire. This makes idle behavior
+// testable without sleeping, and gives callers a hook to trigger the
+// check on app focus/wake events.
+func (sd *StatusDetector) CheckIdle() {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	sd.checkIdleLocked()
+}
+
+// OnIdle registers fn to be called, in its own goroutine, whenever the
+// detector transitions into StatusIdle specifically -- in addition to, not
+// instead of, the general StatusChangeCallback. Lets a caller wire
+// idle-triggered behavior (e.g. offering to summarize the session or
+// extract learnings) without string-comparing statuses in its general
+// callback. Only one idle callback is kept; a later call replaces the
+// earlier one. Safe to call concurrently with ProcessOutput.
+func (sd *StatusDetector) OnIdle(fn func()) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	sd.idleCallback = fn
+}
+
 // setStatus updates the status and fires the callback if changed.
 // Must be called with mu held.
 func (sd *StatusDetector) setStatus(newStatus string) {
 	if newStatus == sd.currentStatus {
 		return
 	}
-	// Debounce: don't change status too rapidly
-	if time.Since(sd.lastChangeAt) < DebounceInterval {
+	// Debounce: don't change status too rapidly. The interval itself adapts
+	// to observed flap frequency; see recordFlapLocked/decayDebounceLocked.
+	// Terminal states bypass the debounce entirely: a process exit or a
+	// detected error must never be silently swallowed just because it landed
+	// within the debounce window of the previous change.
+	if newStatus != StatusExited && newStatus != StatusError && time.Since(sd.lastChangeAt) < sd.currentDebounce {
+		sd.recordFlapLocked()
 		return
 	}
+	sd.decayDebounceLocked()
 	oldStatus := sd.currentStatus
 	sd.currentStatus = newStatus
 	sd.lastChangeAt = time.Now()
+	if newStatus == StatusActive {
+		sd.activeSince = sd.lastChangeAt
+	}
+	sd.history = append(sd.history, StatusChangeRecord{OldStatus: oldStatus, NewStatus: newStatus, At: sd.lastChangeAt})
+	if len(sd.history) > MaxStatusHistory {
+		sd.history = sd.history[len(sd.history)-MaxStatusHistory:]
+	}
 	if sd.callback != nil {
 		// Fire callback outside the lock
 		go sd.callback(oldStatus, newStatus)
 	}
+	if newStatus == StatusIdle && sd.idleCallback != nil {
+		go sd.idleCallback()
+	}
+}
+
+// recordFlapLocked counts a status change suppressed by the debounce as a
+// flap within the current FlapWindow, resetting the window on the first
+// flap after a quiet period. Once FlapGrowThreshold flaps land in one
+// window, currentDebounce doubles (capped at MaxDebounceInterval) and the
+// window resets, so sustained flapping keeps lengthening the debounce
+// rather than growing it once and stopping. Must be called with mu held.
+func (sd *StatusDetector) recordFlapLocked() {
+	now := time.Now()
+	if now.Sub(sd.flapWindowStart) > FlapWindow {
+		sd.flapWindowStart = now
+		sd.flapCount = 0
+	}
+	sd.flapCount++
+	if sd.flapCount >= FlapGrowThreshold {
+		sd.currentDebounce *= 2
+		if sd.currentDebounce > MaxDebounceInterval {
+			sd.currentDebounce = MaxDebounceInterval
+		}
+		sd.flapCount = 0
+		sd.flapWindowStart = now
+	}
+}
+
+// decayDebounceLocked halves currentDebounce (floored at
+// MinDebounceInterval) when a status change goes through cleanly after a
+// full FlapWindow with no flapping, so a session that settles down
+// gradually becomes responsive again instead of staying debounced at
+// whatever rate its noisiest period reached. Must be called with mu held.
+func (sd *StatusDetector) decayDebounceLocked() {
+	if time.Since(sd.flapWindowStart) < FlapWindow {
+		return
+	}
+	sd.flapWindowStart = time.Now()
+	if sd.currentDebounce <= MinDebounceInterval {
+		return
+	}
+	sd.currentDebounce /= 2
+	if sd.currentDebounce < MinDebounceInterval {
+		sd.currentDebounce = MinDebounceInterval
+	}
 }
 
 // ProcessOutput feeds terminal output data to the detector.
@@ -138,16 +701,310 @@ func (sd *StatusDetector) ProcessOutput(data []byte) {
 		sd.lineBuffer = sd.lineBuffer[len(sd.lineBuffer)-MaxLineBufferSize:]
 	}
 
+	sd.updateAltScreenState(data)
+	if sd.inAltScreen {
+		// A full-screen program (tmux, screen, vim, etc.) owns the display.
+		// Prompt-based detection doesn't apply here, so just treat the
+		// session as active until the alternate screen is exited.
+		sd.setStatus(StatusActive)
+		return
+	}
+
+	sd.throttledClassifyLocked()
+}
+
+// throttledClassifyLocked runs classifyLocked at most once per
+// classifyThrottle: immediately if the throttle has elapsed since the last
+// classification, or else scheduling a single trailing call for when it
+// will, so a burst of chunks coalesces into the buffer without each one
+// paying for a fresh regex pass, while the final state after the burst is
+// still classified. Must be called with mu held.
+func (sd *StatusDetector) throttledClassifyLocked() {
+	now := time.Now()
+	if sd.lastClassifyAt.IsZero() || now.Sub(sd.lastClassifyAt) >= sd.classifyThrottle {
+		sd.lastClassifyAt = now
+		sd.classifyLocked()
+		return
+	}
+	if sd.classifyPending {
+		return
+	}
+	sd.classifyPending = true
+	sd.classifyTimer = time.AfterFunc(sd.classifyThrottle-now.Sub(sd.lastClassifyAt), func() {
+		sd.mu.Lock()
+		defer sd.mu.Unlock()
+		sd.classifyPending = false
+		if sd.stopped {
+			return
+		}
+		sd.lastClassifyAt = time.Now()
+		sd.classifyLocked()
+	})
+}
+
+// classifyContext bundles the classification input each statusClassifier
+// needs: the ANSI-stripped, preprocessed tail of the line buffer, plus the
+// prompt pattern sets selected for the session's shell type and multiline
+// anchoring setting. Built once per classifyLocked call and shared across
+// every classifier so pattern selection stays in one place.
+type classifyContext struct {
+	stripped         string
+	strongPatterns   []*regexp.Regexp
+	questionPatterns []*regexp.Regexp
+	weakPatterns     []*regexp.Regexp
+	customPatterns   []*regexp.Regexp
+}
+
+// statusClassifier is one entry in statusClassifiers: a named, prioritized
+// check against classifyContext. classify reports whether it matched; on a
+// match it's responsible for its own state transition (setStatus,
+// arming/clearing the weak-prompt timer, etc.) via sd.
+type statusClassifier struct {
+	name     string
+	priority int
+	classify func(sd *StatusDetector, ctx classifyContext) bool
+}
+
+// statusClassifiers is the ordered classifier pipeline classifyLocked runs:
+// highest priority first, stopping at the first match. Precedence is
+// rate-limit > error > strong prompt > question prompt > weak prompt >
+// tool/active fallback, expressed here as explicit priorities so a future
+// classifier can be slotted in at the right precedence without re-reading
+// and re-ordering a growing switch. Error sits above prompt detection
+// deliberately: a crash's output is often immediately followed by a shell
+// prompt (the process exited, or a wrapping shell recovered), and a session
+// that panicked is worth surfacing as an error even though the last few
+// lines also happen to look prompt-like. Sorted by priority, descending, at
+// package init.
+var statusClassifiers = sortedClassifiers([]statusClassifier{
+	{name: "rate-limit", priority: 100, classify: classifyRateLimit},
+	{name: "error", priority: 95, classify: classifyError},
+	{name: "strong-prompt", priority: 90, classify: classifyStrongPrompt},
+	{name: "question-prompt", priority: 80, classify: classifyQuestionPrompt},
+	{name: "weak-prompt", priority: 70, classify: classifyWeakPrompt},
+	{name: "tool-and-active", priority: 0, classify: classifyToolAndActive},
+})
+
+// sortedClassifiers returns classifiers sorted by priority, descending, so
+// statusClassifiers always reflects highest-priority-first regardless of the
+// order its literal lists them in.
+func sortedClassifiers(classifiers []statusClassifier) []statusClassifier {
+	sort.SliceStable(classifiers, func(i, j int) bool {
+		return classifiers[i].priority > classifiers[j].priority
+	})
+	return classifiers
+}
+
+// classifyRateLimit matches a rate-limit message. A rate-limit wait is a
+// distinct, transient condition, not idleness: Claude is going to resume on
+// its own once the wait elapses.
+func classifyRateLimit(sd *StatusDetector, ctx classifyContext) bool {
+	if !matchesAny(rateLimitPatterns, ctx.stripped) {
+		return false
+	}
+	sd.clearWeakPromptLocked()
+	wait, ok := parseRateLimitWait(ctx.stripped)
+	if !ok {
+		wait = 0
+	}
+	sd.rateLimitWait = wait
+	sd.setStatus(StatusRateLimited)
+	return true
+}
+
+// classifyError matches a crash or fatal error (see errorPatterns). Checked
+// ahead of prompt detection since a crash's output is frequently followed,
+// in the same buffered chunk, by a shell prompt -- without this precedence
+// the session would read as merely needing input instead of having failed.
+func classifyError(sd *StatusDetector, ctx classifyContext) bool {
+	if !matchesAny(errorPatterns, ctx.stripped) {
+		return false
+	}
+	sd.clearWeakPromptLocked()
+	sd.setStatus(StatusError)
+	return true
+}
+
+// classifyStrongPrompt matches an unambiguous prompt; no need to wait out
+// the settle window.
+func classifyStrongPrompt(sd *StatusDetector, ctx classifyContext) bool {
+	if !matchesAny(ctx.strongPatterns, ctx.stripped) && !matchesAny(ctx.customPatterns, ctx.stripped) {
+		return false
+	}
+	sd.clearWeakPromptLocked()
+	sd.needsInputKind = classifyNeedsInputKind(ctx.stripped)
+	sd.setStatus(StatusNeedsInput)
+	return true
+}
+
+// classifyQuestionPrompt matches an open-ended question, which also
+// warrants an immediate transition; it's just as unambiguous as a strong
+// prompt, only phrased as a question instead of a fixed pattern.
+func classifyQuestionPrompt(sd *StatusDetector, ctx classifyContext) bool {
+	if !matchesAny(ctx.questionPatterns, ctx.stripped) {
+		return false
+	}
+	sd.clearWeakPromptLocked()
+	sd.needsInputKind = NeedsInputKindQuestion
+	sd.setStatus(StatusNeedsInput)
+	return true
+}
+
+// classifyWeakPrompt matches output that's prompt-like but easy to
+// false-positive mid-stream, so it only arms the settle timer instead of
+// transitioning immediately.
+func classifyWeakPrompt(sd *StatusDetector, ctx classifyContext) bool {
+	if !matchesAny(ctx.weakPatterns, ctx.stripped) {
+		return false
+	}
+	sd.armWeakPromptLocked()
+	return true
+}
+
+// classifyToolAndActive is the pipeline's fallback classifier: it always
+// matches, so it must run last. It handles tool-completion and tool-start
+// bookkeeping before settling on either StatusToolRunning (still waiting on
+// an announced tool) or StatusActive (ordinary output).
+func classifyToolAndActive(sd *StatusDetector, ctx classifyContext) bool {
+	sd.clearWeakPromptLocked()
+	sd.rateLimitWait = 0
+	if sd.currentStatus == StatusToolRunning && toolCompletionPattern.MatchString(ctx.stripped) {
+		// The announced tool's completion output arrived.
+		sd.pendingToolName = ""
+		sd.setStatus(StatusActive)
+		return true
+	}
+	// getLastLines can still include an earlier chunk's tool banner, so
+	// only treat this as a (possibly new, e.g. sequential) tool invocation
+	// if it names a different tool than the one already pending, to avoid
+	// re-arming on the same stale banner text.
+	if m := toolStartPattern.FindStringSubmatch(ctx.stripped); m != nil && m[1] != sd.pendingToolName {
+		sd.pendingToolName = m[1]
+		sd.setStatus(StatusActive)
+		return true
+	}
+	if sd.currentStatus == StatusToolRunning {
+		// Still waiting on the announced tool; this output isn't its
+		// completion, so stay in StatusToolRunning rather than flickering
+		// back to active.
+		return true
+	}
+	// We're receiving output, so Claude is active
+	sd.setStatus(StatusActive)
+	return true
+}
+
+// classifyLocked matches the tail of the line buffer against statusClassifiers,
+// the ordered classifier pipeline, and applies the highest-priority match.
+// Must be called with mu held.
+func (sd *StatusDetector) classifyLocked() {
+	sd.classifyCount++
 	// Get the last few lines for pattern matching
 	lastLines := getLastLines(sd.lineBuffer, 3)
-	stripped := stripAnsi(lastLines)
+	stripped := StripAnsi(lastLines)
+	if sd.outputPreprocessor != nil {
+		stripped = sd.outputPreprocessor(stripped)
+	}
 
-	// Check if output matches a prompt pattern (needs-input)
-	if matchesPrompt(stripped) {
+	strongPatterns, questionPatterns, weakPatterns := strongPromptPatterns, questionPromptPatterns, weakPromptPatterns
+	if sd.shellType == ShellZsh {
+		weakPatterns = zshWeakPromptPatterns
+	}
+	if sd.multilineAnchoring {
+		strongPatterns, questionPatterns, weakPatterns = strongPromptPatternsMultiline, questionPromptPatternsMultiline, weakPromptPatternsMultiline
+		if sd.shellType == ShellZsh {
+			weakPatterns = zshWeakPromptPatternsMultiline
+		}
+	}
+	if sd.strongPatternOverride != nil {
+		strongPatterns = sd.strongPatternOverride
+	}
+	if sd.questionPatternOverride != nil {
+		questionPatterns = sd.questionPatternOverride
+	}
+	if sd.weakPatternOverride != nil {
+		weakPatterns = sd.weakPatternOverride
+	}
+
+	ctx := classifyContext{
+		stripped:         stripped,
+		strongPatterns:   strongPatterns,
+		questionPatterns: questionPatterns,
+		weakPatterns:     weakPatterns,
+		customPatterns:   sd.customPromptPatterns,
+	}
+	for _, c := range statusClassifiers {
+		if c.classify(sd, ctx) {
+			return
+		}
+	}
+}
+
+// armWeakPromptLocked records that a weak prompt pattern matched as of the
+// current output time and, if one isn't already pending, schedules a check
+// after WeakPromptSettleWindow. Must be called with mu held.
+func (sd *StatusDetector) armWeakPromptLocked() {
+	if !sd.weakPromptSince.IsZero() {
+		return
+	}
+	sd.weakPromptSince = sd.lastOutputAt
+	sd.weakPromptTimer = time.AfterFunc(WeakPromptSettleWindow, func() {
+		sd.mu.Lock()
+		defer sd.mu.Unlock()
+		sd.checkWeakPromptLocked()
+	})
+}
+
+// clearWeakPromptLocked cancels any pending weak prompt check. Must be
+// called with mu held.
+func (sd *StatusDetector) clearWeakPromptLocked() {
+	if sd.weakPromptTimer != nil {
+		sd.weakPromptTimer.Stop()
+		sd.weakPromptTimer = nil
+	}
+	sd.weakPromptSince = time.Time{}
+}
+
+// checkWeakPromptLocked settles a pending weak prompt match into
+// needs-input, but only if no new output has arrived since it was seen.
+// Must be called with mu held.
+func (sd *StatusDetector) checkWeakPromptLocked() {
+	if sd.stopped || sd.weakPromptSince.IsZero() {
+		return
+	}
+	if sd.lastOutputAt.Equal(sd.weakPromptSince) {
+		// Weak prompts (a bare ">" or "$") are generic continuation
+		// prompts, not confirmations.
+		sd.needsInputKind = NeedsInputKindQuestion
 		sd.setStatus(StatusNeedsInput)
-	} else {
-		// We're receiving output, so Claude is active
-		sd.setStatus(StatusActive)
+	}
+	sd.weakPromptSince = time.Time{}
+	sd.weakPromptTimer = nil
+}
+
+// CheckWeakPrompt immediately evaluates a pending weak prompt match instead
+// of waiting for the settle timer to fire, mirroring CheckIdle. This makes
+// the settling behavior testable without sleeping.
+func (sd *StatusDetector) CheckWeakPrompt() {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	sd.checkWeakPromptLocked()
+}
+
+// updateAltScreenState scans a chunk of output for alternate-screen
+// enter/exit sequences and updates inAltScreen accordingly. Must be
+// called with mu held.
+func (sd *StatusDetector) updateAltScreenState(data []byte) {
+	enterIdx := altScreenEnterRegex.FindIndex(data)
+	exitIdx := altScreenExitRegex.FindIndex(data)
+	switch {
+	case enterIdx != nil && exitIdx != nil:
+		// Both appear in this chunk; whichever comes last wins.
+		sd.inAltScreen = enterIdx[0] > exitIdx[0]
+	case enterIdx != nil:
+		sd.inAltScreen = true
+	case exitIdx != nil:
+		sd.inAltScreen = false
 	}
 }
 
@@ -161,11 +1018,64 @@ func (sd *StatusDetector) SetExited() {
 // Stop cleans up the detector's resources.
 func (sd *StatusDetector) Stop() {
 	sd.mu.Lock()
-	defer sd.mu.Unlock()
 	sd.stopped = true
 	if sd.idleTimer != nil {
 		sd.idleTimer.Stop()
 	}
+	if sd.classifyTimer != nil {
+		sd.classifyTimer.Stop()
+	}
+	sd.clearWeakPromptLocked()
+	sd.mu.Unlock()
+	sd.stopOnce.Do(func() { close(sd.stopCh) })
+}
+
+// ProcessInput records that the user sent input, e.g. a keystroke written
+// to the pty. It doesn't affect status classification directly, but feeds
+// GetIdleReason's distinction between Claude waiting on the user and the
+// user having stepped away while Claude keeps working.
+func (sd *StatusDetector) ProcessInput(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	sd.lastInputAt = time.Now()
+}
+
+// classifyIdleReason determines why a session appears idle to an external
+// notifier, so a session where Claude is still working (StatusActive) but
+// the user simply hasn't touched the keyboard in a while isn't mistaken for
+// one that's genuinely waiting on the user.
+func classifyIdleReason(status string, lastInputAt, lastOutputAt time.Time) string {
+	switch status {
+	case StatusIdle, StatusNeedsInput:
+		// Whatever earned this status, Claude has stopped producing output;
+		// it's Claude that's waiting now, regardless of how recently the
+		// user last typed.
+		return IdleReasonWaitingForUser
+	case StatusActive:
+		// Claude is still producing output. If the user's last input
+		// predates that output, Claude is working through something on its
+		// own and the user isn't watching -- not worth a notification.
+		if lastInputAt.Before(lastOutputAt) {
+			return IdleReasonUserAway
+		}
+		return IdleReasonNone
+	default:
+		return IdleReasonNone
+	}
+}
+
+// GetIdleReason classifies why the session currently appears idle to an
+// external notifier: IdleReasonWaitingForUser if Claude has stopped and is
+// waiting on the user (worth notifying), IdleReasonUserAway if Claude is
+// still working but the user has stepped away (not worth notifying), or
+// IdleReasonNone otherwise.
+func (sd *StatusDetector) GetIdleReason() string {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	return classifyIdleReason(sd.currentStatus, sd.lastInputAt, sd.lastOutputAt)
 }
 
 // GetStatus returns the current detected status.
@@ -175,9 +1085,155 @@ func (sd *StatusDetector) GetStatus() string {
 	return sd.currentStatus
 }
 
-// matchesPrompt checks if the text matches any known Claude prompt pattern.
+// NeedsInputKind returns which sub-type of StatusNeedsInput was last
+// detected (NeedsInputKindConfirmation or NeedsInputKindQuestion). Its
+// value is only meaningful while GetStatus reports StatusNeedsInput.
+func (sd *StatusDetector) NeedsInputKind() string {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	return sd.needsInputKind
+}
+
+// RateLimitWait returns the wait duration parsed from the most recent
+// rate-limit message, or 0 if none was found. Its value is only meaningful
+// while GetStatus reports StatusRateLimited.
+func (sd *StatusDetector) RateLimitWait() time.Duration {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	return sd.rateLimitWait
+}
+
+// SetShellType records the session's detected shell (ShellBash, ShellZsh,
+// or ShellFish), e.g. once DetectShell identifies it from early output.
+// This selects which weak prompt patterns classifyLocked checks: a zsh
+// session additionally treats a trailing "%" as a shell prompt, which bash
+// and fish sessions don't emit on their own.
+func (sd *StatusDetector) SetShellType(shell string) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	sd.shellType = shell
+}
+
+// ShellType returns the session's detected shell, or "" if none has been
+// detected yet.
+func (sd *StatusDetector) ShellType() string {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	return sd.shellType
+}
+
+// ToolName returns the name of the tool invocation Claude's CLI most
+// recently echoed that hasn't been followed by completion output yet. Its
+// value is only meaningful while GetStatus reports StatusToolRunning.
+func (sd *StatusDetector) ToolName() string {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	return sd.pendingToolName
+}
+
+// classifyNeedsInputKind determines whether a strong prompt match is a
+// yes/no-style confirmation or a more open-ended question, based on
+// confirmationPromptPatterns. Anything matching strongPromptPatterns but
+// not confirmationPromptPatterns (e.g. the bare Claude "❯" prompt, or an
+// explicit "waiting for input" message) defaults to a question, since it
+// isn't asking for a fixed yes/no decision.
+func classifyNeedsInputKind(text string) string {
+	if matchesAny(confirmationPromptPatterns, text) {
+		return NeedsInputKindConfirmation
+	}
+	return NeedsInputKindQuestion
+}
+
+// ClassifyNeedsInputKind is the exported form of classifyNeedsInputKind, for
+// callers outside this package that already know a piece of text represents
+// a waiting session's prompt (e.g. a transcript's tail read back out of
+// storage) and just need to tell a yes/no-style confirmation apart from an
+// open-ended question.
+func ClassifyNeedsInputKind(text string) string {
+	return classifyNeedsInputKind(StripAnsi(text))
+}
+
+// StatusHistoryEntry is a single recorded status change, as persisted to
+// the status-history file.
+type StatusHistoryEntry struct {
+	Timestamp int64  `json:"ts"`
+	Status    string `json:"status"`
+}
+
+// StatusTransition describes one segment of a session's status timeline:
+// the status it was in, when that segment started, and how long it lasted.
+// The final segment (the session's current status) has Ongoing set and its
+// DurationMs measured against the time the timeline was built.
+type StatusTransition struct {
+	Status     string `json:"status"`
+	Timestamp  int64  `json:"ts"`
+	DurationMs int64  `json:"durationMs"`
+	Ongoing    bool   `json:"ongoing"`
+}
+
+// ParseStatusHistory parses JSONL status-history data (one StatusHistoryEntry
+// per line) into a slice, skipping malformed lines.
+func ParseStatusHistory(data []byte) []StatusHistoryEntry {
+	var entries []StatusHistoryEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var entry StatusHistoryEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// BuildStatusTimeline turns a chronological list of status-history entries
+// into a timeline of StatusTransitions, each carrying the duration until the
+// next transition. The final segment's duration is measured against nowMs
+// and marked Ongoing, since the session hasn't left that status yet.
+func BuildStatusTimeline(entries []StatusHistoryEntry, nowMs int64) []StatusTransition {
+	timeline := make([]StatusTransition, len(entries))
+	for i, entry := range entries {
+		end := nowMs
+		ongoing := i == len(entries)-1
+		if !ongoing {
+			end = entries[i+1].Timestamp
+		}
+		timeline[i] = StatusTransition{
+			Status:     entry.Status,
+			Timestamp:  entry.Timestamp,
+			DurationMs: end - entry.Timestamp,
+			Ongoing:    ongoing,
+		}
+	}
+	return timeline
+}
+
+// ClassifyPromptStatus inspects text (typically the tail of a session's
+// transcript) and returns StatusNeedsInput if it looks like Claude is
+// waiting on a prompt, or StatusActive otherwise. Used to seed a freshly
+// created StatusDetector's initial status when reattaching to a session
+// that already has recorded output, instead of starting in the unknown
+// (empty) status.
+func ClassifyPromptStatus(text string) string {
+	if matchesPrompt(StripAnsi(text)) {
+		return StatusNeedsInput
+	}
+	return StatusActive
+}
+
+// matchesPrompt checks if the text matches any known Claude prompt pattern,
+// strong or weak. Used for one-shot checks that don't care about the
+// settling behavior applied to weak prompts during live detection.
 func matchesPrompt(text string) bool {
-	for _, pat := range promptPatterns {
+	return matchesAny(strongPromptPatterns, text) || matchesAny(questionPromptPatterns, text) || matchesAny(weakPromptPatterns, text)
+}
+
+// matchesAny checks if the text matches any pattern in the given set.
+func matchesAny(patterns []*regexp.Regexp, text string) bool {
+	for _, pat := range patterns {
 		if pat.MatchString(text) {
 			return true
 		}
@@ -193,10 +1249,3 @@ func getLastLines(text string, n int) string {
 	}
 	return strings.Join(lines[len(lines)-n:], "\n")
 }
-
-// stripAnsi removes ANSI escape codes from terminal output.
-var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]|\x1b\][^\x07]*\x07|\x1b\[[0-9;]*m`)
-
-func stripAnsi(text string) string {
-	return ansiRegex.ReplaceAllString(text, "")
-}
@@ -0,0 +1,36 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TaskInfo describes one Claude subagent/task invocation recorded in a
+// session's transcript.
+type TaskInfo struct {
+	Description string `json:"description"`
+	Timestamp   int64  `json:"ts"`
+}
+
+// taskStartPattern matches Claude CLI's echo line for spawning a subagent
+// task, e.g. "⏺ Task(Investigate the flaky test)", capturing its
+// description. Mirrors toolStartPattern in statusdetector.go for the Bash
+// tool and touchedFilePattern in touchedfiles.go for file-editing tools.
+var taskStartPattern = regexp.MustCompile(`⏺\s*Task\(([^)]+)\)`)
+
+// DetectTaskDescriptions scans text for Claude subagent/task invocation
+// banners and returns each one's description, in order of appearance.
+func DetectTaskDescriptions(text string) []string {
+	var descriptions []string
+	for _, m := range taskStartPattern.FindAllStringSubmatch(text, -1) {
+		desc := strings.TrimSpace(m[1])
+		if desc == "" {
+			continue
+		}
+		descriptions = append(descriptions, desc)
+	}
+	return descriptions
+}
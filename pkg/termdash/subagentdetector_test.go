@@ -0,0 +1,32 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectTaskDescriptionsFindsBanner(t *testing.T) {
+	got := DetectTaskDescriptions("⏺ Task(Investigate the flaky test)\n")
+	want := []string{"Investigate the flaky test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectTaskDescriptions() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectTaskDescriptionsFindsMultipleBanners(t *testing.T) {
+	text := "⏺ Task(Run the test suite)\nsome output\n⏺ Task(Fix the lint errors)\n"
+	got := DetectTaskDescriptions(text)
+	want := []string{"Run the test suite", "Fix the lint errors"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectTaskDescriptions() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectTaskDescriptionsNoMatchReturnsNil(t *testing.T) {
+	if got := DetectTaskDescriptions("just some regular terminal output\n"); got != nil {
+		t.Errorf("DetectTaskDescriptions() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"regexp"
+	"strings"
+)
+
+// touchedFilePattern matches the claude CLI's tool-call echo lines for
+// operations that read or modify a specific file, capturing the path, e.g.
+// "⏺ Read(pkg/foo/bar.go)" or "Edit(main.go)".
+var touchedFilePattern = regexp.MustCompile(`(?:Read|Edit|Write|Update|MultiEdit)\(([^)]+)\)`)
+
+// ExtractTouchedFiles scans transcript text for tool-call lines that read or
+// modify a specific file and returns the unique set of file paths touched,
+// in order of first appearance.
+func ExtractTouchedFiles(text string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, m := range touchedFilePattern.FindAllStringSubmatch(text, -1) {
+		file := strings.TrimSpace(m[1])
+		if file == "" || seen[file] {
+			continue
+		}
+		seen[file] = true
+		files = append(files, file)
+	}
+	return files
+}
@@ -0,0 +1,26 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractTouchedFilesFindsToolCalledFiles(t *testing.T) {
+	text := "⏺ Read(pkg/foo/bar.go)\nsome output\n⏺ Edit(pkg/foo/bar.go)\n⏺ Write(main.go)\n"
+
+	got := ExtractTouchedFiles(text)
+	want := []string{"pkg/foo/bar.go", "main.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractTouchedFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestExtractTouchedFilesReturnsNilWhenNoneFound(t *testing.T) {
+	got := ExtractTouchedFiles("just some plain terminal output\n")
+	if got != nil {
+		t.Errorf("ExtractTouchedFiles() = %v, want nil", got)
+	}
+}
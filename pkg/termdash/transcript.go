@@ -4,11 +4,14 @@
 package termdash
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 const (
@@ -17,19 +20,58 @@ const (
 
 	// Maximum time between flushes
 	TranscriptFlushInterval = 5 * time.Second
+
+	// Default window within which consecutive same-type entries are
+	// coalesced into one, when coalescing is enabled.
+	DefaultCoalesceWindow = 100 * time.Millisecond
+
+	// CurrentTranscriptSchemaVersion is the schema version stamped onto
+	// every TranscriptEntry a live recorder writes. Bump it whenever a
+	// change to TranscriptEntry (a new field, a changed meaning for an
+	// existing one) requires readers to distinguish old data from new, and
+	// teach MigrateTranscriptEntries how to upgrade the previous version.
+	CurrentTranscriptSchemaVersion = 1
 )
 
 // TranscriptEntry represents a single entry in the transcript log.
 type TranscriptEntry struct {
 	Timestamp int64  `json:"ts"`
-	Type      string `json:"type"` // "output" or "input"
+	Type      string `json:"type"` // "output", "input", "paste", or "task"
 	Text      string `json:"text"`
+
+	// Version is the schema version this entry was written in. Entries
+	// from before this field existed decode with Version 0 (its zero
+	// value), which readers treat identically to any other known version
+	// unless a future migration says otherwise. Omitted from JSON when 0
+	// so old-format transcripts round-trip unchanged.
+	Version int `json:"v,omitempty"`
 }
 
 // TranscriptFlushFunc is called when the transcript buffer should be persisted.
 // The data is JSONL-formatted (one JSON object per line).
 type TranscriptFlushFunc func(data []byte)
 
+// FlushStrategy controls which triggers can cause a TranscriptRecorder to
+// flush its buffer.
+type FlushStrategy string
+
+const (
+	// FlushStrategyHybrid flushes whenever either the size threshold or the
+	// time interval is reached. The default, and the recorder's original,
+	// only behavior.
+	FlushStrategyHybrid FlushStrategy = "hybrid"
+
+	// FlushStrategySizeOnly flushes only once TranscriptFlushThreshold bytes
+	// have buffered, ignoring the timer, for minimal small writes at the
+	// cost of unpredictable flush timing.
+	FlushStrategySizeOnly FlushStrategy = "size-only"
+
+	// FlushStrategyTimeOnly flushes only on the TranscriptFlushInterval
+	// timer, ignoring the size threshold, for a predictable I/O cadence at
+	// the cost of a buffer that can grow large between flushes.
+	FlushStrategyTimeOnly FlushStrategy = "time-only"
+)
+
 // TranscriptRecorder records cleaned terminal I/O for a Claude session.
 // It strips ANSI codes, deduplicates animation noise, and batches writes.
 type TranscriptRecorder struct {
@@ -43,10 +85,392 @@ type TranscriptRecorder struct {
 	// Track recent output to deduplicate spinner/animation frames
 	lastOutput string
 	dupCount   int
+
+	// coalesceWindow, when non-zero, causes consecutive same-type entries
+	// recorded within the window to be merged into a single entry.
+	coalesceWindow time.Duration
+
+	// readableKeyNames, when true, causes RecordInput to translate known
+	// control/escape sequences (arrow keys, Enter, Tab, Ctrl-C, ...) into
+	// readable tokens like "<Up>" instead of storing raw bytes.
+	readableKeyNames bool
+
+	// animationSimilarityThreshold, when non-zero, causes RecordOutput to
+	// also treat consecutive output lines as animation-like (and dedup them)
+	// when their text similarity is at or above this ratio, catching
+	// near-identical repeated lines (e.g. a log line differing only by a
+	// counter) that the fixed animationPatterns regexes miss.
+	animationSimilarityThreshold float64
+
+	// recordFilter, when set, is consulted before buffering each entry;
+	// entries for which it returns false are dropped.
+	recordFilter RecordFilter
+
+	// binaryFormat, when true, causes flush() to write the compact binary
+	// transcript encoding instead of JSONL, prefixing the very first flush
+	// with the format's magic header.
+	binaryFormat      bool
+	wroteBinaryHeader bool
+
+	// flushStrategy controls which of the size threshold and time interval
+	// triggers are active. Defaults to FlushStrategyHybrid.
+	flushStrategy FlushStrategy
+
+	// flushInterval is the timer's period. Defaults to
+	// TranscriptFlushInterval; tests shrink it directly to exercise the
+	// timer trigger without a real-time wait.
+	flushInterval time.Duration
+
+	// outputSamplingThreshold, when non-zero, causes RecordOutput to sample
+	// down any single burst whose cleaned text exceeds this many bytes
+	// instead of recording it in full. Disabled (0) by default.
+	outputSamplingThreshold int
+
+	// outputSamplingKeepLines is how many lines are kept from the head and
+	// tail of a burst sampled down by outputSamplingThreshold.
+	outputSamplingKeepLines int
+
+	// inputPrivacyMode controls how much of the user's typing RecordInput
+	// persists. Defaults to "" (InputPrivacyFull), which keeps today's
+	// per-keystroke recording behavior.
+	inputPrivacyMode string
+
+	// pendingCommandLine accumulates printable keystrokes between Enter
+	// presses when inputPrivacyMode is InputPrivacyCommandsOnly, so a
+	// complete command line can be recorded as a single entry instead of
+	// one entry per keystroke.
+	pendingCommandLine strings.Builder
+
+	// inPaste and pasteBuffer track an in-progress bracketed paste:
+	// inPaste is true between seeing bracketedPasteStart and
+	// bracketedPasteEnd (which may arrive across multiple RecordInput
+	// calls), and pasteBuffer accumulates the pasted text in between.
+	inPaste     bool
+	pasteBuffer strings.Builder
+
+	// pasteMaxBytes, when non-zero, truncates a completed paste's recorded
+	// text to this many bytes, appending a "[... N bytes truncated ...]"
+	// marker. Disabled (0) by default.
+	pasteMaxBytes int
 }
 
-// ANSI escape code patterns for stripping
-var transcriptAnsiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]|\x1b\][^\x07]*\x07|\x1b\[[0-9;]*m|\x1b\[\?[0-9;]*[a-zA-Z]`)
+// Input privacy modes for WithInputPrivacyMode.
+const (
+	// InputPrivacyFull records every keystroke individually, as
+	// RecordInput has always done. The default when no mode is set.
+	InputPrivacyFull = "full"
+
+	// InputPrivacyCommandsOnly buffers keystrokes and records one entry
+	// per complete command line (i.e. once Enter is pressed), rather than
+	// an entry per keystroke.
+	InputPrivacyCommandsOnly = "commands-only"
+
+	// InputPrivacyNone records no input at all.
+	InputPrivacyNone = "none"
+)
+
+// RecordFilter decides whether entry should be recorded. Returning false
+// drops the entry before it's buffered.
+type RecordFilter func(TranscriptEntry) bool
+
+// TranscriptRecorderOption configures optional behavior on a TranscriptRecorder.
+type TranscriptRecorderOption func(*TranscriptRecorder)
+
+// WithCoalesceWindow enables coalescing of consecutive same-type entries
+// (e.g. per-token streaming output) recorded within window into a single
+// entry, keeping the first entry's timestamp. A window of 0 disables
+// coalescing.
+func WithCoalesceWindow(window time.Duration) TranscriptRecorderOption {
+	return func(tr *TranscriptRecorder) {
+		tr.coalesceWindow = window
+	}
+}
+
+// WithReadableKeyNames enables translation of known control/escape
+// sequences into readable tokens (e.g. "<Up>", "<Ctrl-C>", "<Enter>") in
+// recorded input entries, in place of raw bytes. Off by default, so
+// existing transcripts keep recording raw input.
+func WithReadableKeyNames() TranscriptRecorderOption {
+	return func(tr *TranscriptRecorder) {
+		tr.readableKeyNames = true
+	}
+}
+
+// WithInputPrivacyMode controls how much of the user's typing RecordInput
+// persists: InputPrivacyFull (the default) records every keystroke,
+// InputPrivacyCommandsOnly records only complete command lines (built on
+// the same Enter-detection RecordInput already uses for readable key
+// names), and InputPrivacyNone records no input at all. An empty mode is
+// treated as InputPrivacyFull, so existing callers are unaffected.
+func WithInputPrivacyMode(mode string) TranscriptRecorderOption {
+	return func(tr *TranscriptRecorder) {
+		tr.inputPrivacyMode = mode
+	}
+}
+
+// WithMaxPasteBytes truncates a recorded bracketed paste's text to maxBytes,
+// appending a "[... N bytes truncated ...]" marker, so a huge paste doesn't
+// bloat the transcript. maxBytes <= 0 disables truncation, which is the
+// default.
+func WithMaxPasteBytes(maxBytes int) TranscriptRecorderOption {
+	return func(tr *TranscriptRecorder) {
+		tr.pasteMaxBytes = maxBytes
+	}
+}
+
+// DefaultAnimationSimilarityThreshold is a reasonable starting point for
+// WithAnimationSimilarityThreshold: lines need to be quite similar (90%)
+// before they're treated as animation-like.
+const DefaultAnimationSimilarityThreshold = 0.9
+
+// WithAnimationSimilarityThreshold enables similarity-based animation
+// detection: consecutive output lines whose text similarity is at or above
+// threshold (0-1) are deduplicated like spinner frames, even if they don't
+// match animationPatterns. threshold <= 0 disables this (the default).
+func WithAnimationSimilarityThreshold(threshold float64) TranscriptRecorderOption {
+	return func(tr *TranscriptRecorder) {
+		tr.animationSimilarityThreshold = threshold
+	}
+}
+
+// WithRecordFilter installs filter to decide, per entry, whether it should
+// be recorded. Entries for which filter returns false are dropped before
+// buffering — useful for skipping huge binary dumps (e.g. `cat`ting a media
+// file) that would otherwise bloat the transcript.
+func WithRecordFilter(filter RecordFilter) TranscriptRecorderOption {
+	return func(tr *TranscriptRecorder) {
+		tr.recordFilter = filter
+	}
+}
+
+// WithBinaryFormat causes the recorder to persist its transcript using the
+// compact binary encoding (see EncodeTranscriptEntriesBinary) instead of
+// JSONL. Off by default, so existing transcripts keep their JSONL format.
+func WithBinaryFormat() TranscriptRecorderOption {
+	return func(tr *TranscriptRecorder) {
+		tr.binaryFormat = true
+	}
+}
+
+// WithFlushStrategy configures which triggers can cause the recorder to
+// flush its buffer. The default, FlushStrategyHybrid, is applied
+// automatically if this option isn't used.
+func WithFlushStrategy(strategy FlushStrategy) TranscriptRecorderOption {
+	return func(tr *TranscriptRecorder) {
+		tr.flushStrategy = strategy
+	}
+}
+
+// DefaultOutputSamplingKeepLines is how many lines WithOutputSampling keeps
+// from the head and tail of an oversized burst when the caller doesn't have
+// a specific number in mind.
+const DefaultOutputSamplingKeepLines = 50
+
+// WithOutputSampling enables sampling for high-throughput sessions: any
+// single RecordOutput call whose cleaned text exceeds thresholdBytes is
+// reduced to its first and last keepLines lines, with the omitted middle
+// replaced by a "[... N lines omitted ...]" marker. This bounds transcript
+// size for firehose sessions (e.g. a session dumping verbose logs) while
+// preserving enough context to see how the burst started and ended.
+// thresholdBytes <= 0 disables sampling, which is the default.
+func WithOutputSampling(thresholdBytes, keepLines int) TranscriptRecorderOption {
+	return func(tr *TranscriptRecorder) {
+		tr.outputSamplingThreshold = thresholdBytes
+		tr.outputSamplingKeepLines = keepLines
+	}
+}
+
+// sampleBurst reduces text to its first and last keepLines lines if it has
+// more than 2*keepLines lines, replacing the omitted middle with a
+// "[... N lines omitted ...]" marker. Returns text unchanged if it's
+// already short enough, or if keepLines <= 0.
+func sampleBurst(text string, keepLines int) string {
+	if keepLines <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) <= keepLines*2 {
+		return text
+	}
+	omitted := len(lines) - keepLines*2
+	sampled := make([]string, 0, keepLines*2+1)
+	sampled = append(sampled, lines[:keepLines]...)
+	sampled = append(sampled, fmt.Sprintf("[... %d lines omitted ...]", omitted))
+	sampled = append(sampled, lines[len(lines)-keepLines:]...)
+	return strings.Join(sampled, "\n")
+}
+
+// DefaultBinaryRatioThreshold is the fraction of non-printable bytes above
+// which TextOnlyFilter treats an entry as binary and drops it.
+const DefaultBinaryRatioThreshold = 0.3
+
+// TextOnlyFilter is a built-in RecordFilter that drops entries whose text
+// is mostly non-printable bytes, e.g. a binary file dumped to the
+// terminal.
+func TextOnlyFilter(entry TranscriptEntry) bool {
+	return !isMostlyBinary(entry.Text, DefaultBinaryRatioThreshold)
+}
+
+// subsystemLogPrefixPattern matches the "[termdash]" / "[termdash:xxx]"
+// prefix every log.Printf call in this subsystem uses (see
+// statusattachservice.go, summaryservice.go, retentionservice.go,
+// learningsservice.go).
+var subsystemLogPrefixPattern = regexp.MustCompile(`(?m)^\[termdash(:\w+)?\]`)
+
+// ExcludeSubsystemLogFilter is a built-in RecordFilter that drops entries
+// whose text contains a line starting with this subsystem's own log
+// prefix. This guards against a future output sink or tail feature
+// accidentally recording termdash's own log output back into a session's
+// transcript, which would create a feedback loop.
+func ExcludeSubsystemLogFilter(entry TranscriptEntry) bool {
+	return !subsystemLogPrefixPattern.MatchString(entry.Text)
+}
+
+// isMostlyBinary reports whether the fraction of non-printable runes in
+// text is at or above ratio. Newlines and tabs don't count as
+// non-printable.
+func isMostlyBinary(text string, ratio float64) bool {
+	if text == "" {
+		return false
+	}
+	var total, nonPrintable int
+	for _, r := range text {
+		total++
+		if r == '\n' || r == '\t' {
+			continue
+		}
+		if r < 0x20 || r == 0x7f || r == utf8.RuneError {
+			nonPrintable++
+		}
+	}
+	return float64(nonPrintable)/float64(total) >= ratio
+}
+
+// ParseTranscriptEntries parses JSONL transcript data (one TranscriptEntry
+// per line) into a slice, skipping malformed lines.
+func ParseTranscriptEntries(data []byte) []TranscriptEntry {
+	var entries []TranscriptEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var entry TranscriptEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// MarshalTranscriptEntries serializes entries back to JSONL, in the same
+// format flush() writes.
+func MarshalTranscriptEntries(entries []TranscriptEntry) []byte {
+	var out []byte
+	for _, entry := range entries {
+		jsonLine, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		out = append(out, jsonLine...)
+		out = append(out, '\n')
+	}
+	return out
+}
+
+// DecodeTranscriptEntries parses transcript data in either supported
+// format, auto-detecting binary via its magic header and falling back to
+// JSONL otherwise. Callers that don't know (or care) which format a stored
+// transcript uses should call this instead of ParseTranscriptEntries.
+func DecodeTranscriptEntries(data []byte) []TranscriptEntry {
+	if IsBinaryTranscript(data) {
+		return DecodeTranscriptEntriesBinary(data)
+	}
+	return ParseTranscriptEntries(data)
+}
+
+// EncodeTranscriptEntries serializes entries in the requested format.
+// Unrecognized formats (including TranscriptFormatJSONL) fall back to
+// JSONL, the default.
+func EncodeTranscriptEntries(entries []TranscriptEntry, format string) []byte {
+	if format == TranscriptFormatBinary {
+		return EncodeTranscriptEntriesBinary(entries)
+	}
+	return MarshalTranscriptEntries(entries)
+}
+
+// MigrateTranscriptEntries upgrades entries to CurrentTranscriptSchemaVersion,
+// so a reader only ever has to handle the current schema. It reports whether
+// any entry's version actually changed, letting a caller skip rewriting a
+// transcript that's already current. Entries decoded from a file with no "v"
+// field come through with Version 0 (the original, implicit schema, from
+// before this field existed); there's no structural difference between it
+// and version 1 yet, so migrating just stamps the current version. Future
+// schema changes should extend this function to also transform old-shaped
+// entries, not just relabel them.
+func MigrateTranscriptEntries(entries []TranscriptEntry) (migrated []TranscriptEntry, changed bool) {
+	migrated = make([]TranscriptEntry, len(entries))
+	for i, entry := range entries {
+		if entry.Version < CurrentTranscriptSchemaVersion {
+			entry.Version = CurrentTranscriptSchemaVersion
+			changed = true
+		}
+		migrated[i] = entry
+	}
+	return migrated, changed
+}
+
+// CompactTranscriptEntries applies stronger deduplication and coalescing
+// than the live recorder, for shrinking archived transcripts: runs of
+// identical animation-like output lines collapse into a single
+// "[repeated N times]" marker, and any other run of consecutive output
+// entries, up to the next animation-like line, is merged into one,
+// regardless of how much time passed between them (the live recorder only
+// coalesces within its coalesce window).
+// Input entries are always preserved exactly, never merged or altered.
+func CompactTranscriptEntries(entries []TranscriptEntry) []TranscriptEntry {
+	var compacted []TranscriptEntry
+	i := 0
+	for i < len(entries) {
+		entry := entries[i]
+		if entry.Type != "output" {
+			compacted = append(compacted, entry)
+			i++
+			continue
+		}
+
+		if isAnimationFrame(entry.Text) {
+			j := i + 1
+			for j < len(entries) && entries[j].Type == "output" && entries[j].Text == entry.Text {
+				j++
+			}
+			if repeatCount := j - i; repeatCount > 1 {
+				compacted = append(compacted, TranscriptEntry{
+					Timestamp: entry.Timestamp,
+					Type:      "output",
+					Text:      fmt.Sprintf("[repeated %d times]", repeatCount),
+				})
+				i = j
+				continue
+			}
+		}
+
+		merged := entry.Text
+		j := i + 1
+		for j < len(entries) && entries[j].Type == "output" && !isAnimationFrame(entries[j].Text) {
+			merged += entries[j].Text
+			j++
+		}
+		compacted = append(compacted, TranscriptEntry{
+			Timestamp: entry.Timestamp,
+			Type:      "output",
+			Text:      merged,
+		})
+		i = j
+	}
+	return compacted
+}
 
 // Patterns that indicate animation/spinner frames to deduplicate
 var animationPatterns = []*regexp.Regexp{
@@ -55,22 +479,65 @@ var animationPatterns = []*regexp.Regexp{
 }
 
 // NewTranscriptRecorder creates a recorder that calls flushFn to persist data.
-func NewTranscriptRecorder(flushFn TranscriptFlushFunc) *TranscriptRecorder {
+func NewTranscriptRecorder(flushFn TranscriptFlushFunc, opts ...TranscriptRecorderOption) *TranscriptRecorder {
 	tr := &TranscriptRecorder{
-		flushFn: flushFn,
+		flushFn:       flushFn,
+		flushStrategy: FlushStrategyHybrid,
+		flushInterval: TranscriptFlushInterval,
+	}
+	for _, opt := range opts {
+		opt(tr)
 	}
 	tr.startFlushTimer()
+	registerRecorder(tr)
 	return tr
 }
 
+// appendEntry adds entry to the buffer, coalescing it into the previous
+// entry if coalescing is enabled, the previous entry has the same type,
+// and it was recorded within the coalesce window. Must be called with
+// mu held.
+func (tr *TranscriptRecorder) appendEntry(entry TranscriptEntry) {
+	if tr.coalesceWindow > 0 && len(tr.buffer) > 0 {
+		last := &tr.buffer[len(tr.buffer)-1]
+		age := time.Duration(entry.Timestamp-last.Timestamp) * time.Millisecond
+		if last.Type == entry.Type && age < tr.coalesceWindow {
+			tr.bufferBytes -= tr.serializedEntrySize(*last)
+			last.Text += entry.Text
+			tr.bufferBytes += tr.serializedEntrySize(*last)
+			return
+		}
+	}
+	tr.buffer = append(tr.buffer, entry)
+	tr.bufferBytes += tr.serializedEntrySize(entry)
+}
+
+// serializedEntrySize returns the number of bytes entry occupies once
+// flushed in the recorder's configured format. bufferBytes is tracked in
+// these terms (rather than raw text length) so it accurately reflects the
+// buffer flush() will serialize, including "[repeated N]" dedup summary
+// markers.
+func (tr *TranscriptRecorder) serializedEntrySize(entry TranscriptEntry) int {
+	if tr.binaryFormat {
+		return len(EncodeTranscriptEntriesBinary([]TranscriptEntry{entry})) - len(transcriptBinaryMagic)
+	}
+	jsonLine, err := json.Marshal(entry)
+	if err != nil {
+		return 0
+	}
+	return len(jsonLine) + 1
+}
+
 func (tr *TranscriptRecorder) startFlushTimer() {
-	tr.flushTimer = time.AfterFunc(TranscriptFlushInterval, func() {
+	tr.flushTimer = time.AfterFunc(tr.flushInterval, func() {
 		tr.mu.Lock()
 		defer tr.mu.Unlock()
 		if tr.stopped {
 			return
 		}
-		tr.flush()
+		if tr.flushStrategy != FlushStrategySizeOnly {
+			tr.flush()
+		}
 		tr.startFlushTimer()
 	})
 }
@@ -88,38 +555,67 @@ func (tr *TranscriptRecorder) RecordOutput(data []byte) {
 		return
 	}
 
+	if tr.outputSamplingThreshold > 0 && len(cleaned) > tr.outputSamplingThreshold {
+		cleaned = sampleBurst(cleaned, tr.outputSamplingKeepLines)
+	}
+
 	// Deduplicate animation frames
-	if isAnimationFrame(cleaned) {
-		if cleaned == tr.lastOutput {
-			tr.dupCount++
-			return
-		}
+	if tr.isAnimationLike(cleaned) {
+		tr.dupCount++
+		tr.lastOutput = cleaned
+		return
 	}
 
 	// If we had duplicates, log a summary
-	if tr.dupCount > 0 {
-		tr.buffer = append(tr.buffer, TranscriptEntry{
-			Timestamp: time.Now().UnixMilli(),
-			Type:      "output",
-			Text:      "[repeated " + strings.Repeat(".", tr.dupCount) + "]",
-		})
-		tr.dupCount = 0
-	}
+	tr.flushDupCountLocked()
 
 	tr.lastOutput = cleaned
 	entry := TranscriptEntry{
 		Timestamp: time.Now().UnixMilli(),
 		Type:      "output",
 		Text:      cleaned,
+		Version:   CurrentTranscriptSchemaVersion,
 	}
-	tr.buffer = append(tr.buffer, entry)
-	tr.bufferBytes += len(cleaned)
+	if tr.recordFilter != nil && !tr.recordFilter(entry) {
+		return
+	}
+	tr.appendEntry(entry)
+	tr.emitTaskEntriesLocked(cleaned)
 
-	if tr.bufferBytes >= TranscriptFlushThreshold {
+	if tr.flushStrategy != FlushStrategyTimeOnly && tr.bufferBytes >= TranscriptFlushThreshold {
 		tr.flush()
 	}
 }
 
+// emitTaskEntriesLocked scans cleaned output for Claude subagent/task
+// invocation banners and records a distinct Type: "task" entry for each one
+// found, alongside the ordinary output entry RecordOutput already appended,
+// so a transcript reader can find task boundaries (see GetSubagentTasks)
+// without re-parsing banner text out of every output entry. Must be called
+// with mu held.
+func (tr *TranscriptRecorder) emitTaskEntriesLocked(cleaned string) {
+	for _, desc := range DetectTaskDescriptions(cleaned) {
+		entry := TranscriptEntry{
+			Timestamp: time.Now().UnixMilli(),
+			Type:      "task",
+			Text:      desc,
+			Version:   CurrentTranscriptSchemaVersion,
+		}
+		if tr.recordFilter != nil && !tr.recordFilter(entry) {
+			continue
+		}
+		tr.appendEntry(entry)
+	}
+}
+
+// bracketedPasteStart and bracketedPasteEnd are the escape sequences a
+// terminal wraps a pasted block in when bracketed paste mode is enabled, so
+// the receiving program can tell pasted text apart from typed keystrokes.
+const (
+	bracketedPasteStart = "\x1b[200~"
+	bracketedPasteEnd   = "\x1b[201~"
+)
+
 // RecordInput adds user input to the transcript.
 func (tr *TranscriptRecorder) RecordInput(data []byte) {
 	tr.mu.Lock()
@@ -128,9 +624,41 @@ func (tr *TranscriptRecorder) RecordInput(data []byte) {
 		return
 	}
 
+	if tr.inputPrivacyMode == InputPrivacyNone {
+		return
+	}
+
+	tr.recordInputLocked(data)
+}
+
+// recordInputLocked records one input write, first checking whether it's
+// part of a bracketed paste (handled distinctly from typed keystrokes) and
+// otherwise falling through to the existing per-keystroke/commands-only/full
+// recording logic. Must be called with mu held.
+func (tr *TranscriptRecorder) recordInputLocked(data []byte) {
+	if remainder, handled := tr.consumePasteLocked(data); handled {
+		if len(remainder) > 0 {
+			tr.recordInputLocked(remainder)
+		}
+		return
+	}
+
 	text := string(data)
-	// Skip control characters (arrow keys, etc.) — only record printable input
-	if len(text) == 1 && text[0] < 32 && text[0] != '\n' && text[0] != '\r' {
+	translated := false
+	if tr.readableKeyNames {
+		if name, ok := keyName(data); ok {
+			text = name
+			translated = true
+		}
+	}
+	// Skip control characters (arrow keys, etc.) that weren't translated to
+	// a readable name — only record printable input.
+	if !translated && len(text) == 1 && text[0] < 32 && text[0] != '\n' && text[0] != '\r' {
+		return
+	}
+
+	if tr.inputPrivacyMode == InputPrivacyCommandsOnly {
+		tr.recordCommandsOnlyLocked(data, text)
 		return
 	}
 
@@ -138,37 +666,179 @@ func (tr *TranscriptRecorder) RecordInput(data []byte) {
 		Timestamp: time.Now().UnixMilli(),
 		Type:      "input",
 		Text:      text,
+		Version:   CurrentTranscriptSchemaVersion,
 	}
-	tr.buffer = append(tr.buffer, entry)
-	tr.bufferBytes += len(text)
+	if tr.recordFilter != nil && !tr.recordFilter(entry) {
+		return
+	}
+	tr.appendEntry(entry)
 
-	if tr.bufferBytes >= TranscriptFlushThreshold {
+	if tr.flushStrategy != FlushStrategyTimeOnly && tr.bufferBytes >= TranscriptFlushThreshold {
 		tr.flush()
 	}
 }
 
-// flush writes buffered entries as JSONL to the flush function.
+// consumePasteLocked handles data that is, continues, or completes a
+// bracketed paste. If data (together with any already-buffered paste
+// content) doesn't involve a paste at all, it returns handled=false and
+// data should be processed as ordinary input instead. Otherwise it returns
+// handled=true and any bytes following a paste's end marker as remainder,
+// for the caller to process as the next input write.
+// Must be called with mu held.
+func (tr *TranscriptRecorder) consumePasteLocked(data []byte) (remainder []byte, handled bool) {
+	if !tr.inPaste {
+		idx := bytes.Index(data, []byte(bracketedPasteStart))
+		if idx == -1 {
+			return nil, false
+		}
+		tr.inPaste = true
+		tr.pasteBuffer.Reset()
+		data = data[idx+len(bracketedPasteStart):]
+	}
+
+	if endIdx := bytes.Index(data, []byte(bracketedPasteEnd)); endIdx != -1 {
+		tr.pasteBuffer.Write(data[:endIdx])
+		tr.emitPasteEntryLocked()
+		tr.inPaste = false
+		return data[endIdx+len(bracketedPasteEnd):], true
+	}
+
+	tr.pasteBuffer.Write(data)
+	return nil, true
+}
+
+// emitPasteEntryLocked records the accumulated pasteBuffer as a single
+// Type: "paste" entry, truncating it to pasteMaxBytes if set, then resets
+// the buffer. Must be called with mu held.
+func (tr *TranscriptRecorder) emitPasteEntryLocked() {
+	text := tr.pasteBuffer.String()
+	tr.pasteBuffer.Reset()
+	if tr.pasteMaxBytes > 0 && len(text) > tr.pasteMaxBytes {
+		omitted := len(text) - tr.pasteMaxBytes
+		text = text[:tr.pasteMaxBytes] + fmt.Sprintf("[... %d bytes truncated ...]", omitted)
+	}
+
+	entry := TranscriptEntry{
+		Timestamp: time.Now().UnixMilli(),
+		Type:      "paste",
+		Text:      text,
+		Version:   CurrentTranscriptSchemaVersion,
+	}
+	if tr.recordFilter != nil && !tr.recordFilter(entry) {
+		return
+	}
+	tr.appendEntry(entry)
+
+	if tr.flushStrategy != FlushStrategyTimeOnly && tr.bufferBytes >= TranscriptFlushThreshold {
+		tr.flush()
+	}
+}
+
+// recordCommandsOnlyLocked implements InputPrivacyCommandsOnly: it
+// accumulates printable keystrokes into pendingCommandLine and, once data is
+// an Enter keypress, records the accumulated line as a single entry instead
+// of one entry per keystroke. Backspace trims the last accumulated rune;
+// other special keys (arrows, Ctrl-C, Tab, ...) are ignored rather than
+// appended, regardless of whether readableKeyNames is enabled, since they
+// aren't part of a command line's text.
+// Must be called with mu held.
+func (tr *TranscriptRecorder) recordCommandsOnlyLocked(data []byte, text string) {
+	if len(data) == 1 && (data[0] == '\r' || data[0] == '\n') {
+		line := tr.pendingCommandLine.String()
+		tr.pendingCommandLine.Reset()
+		if line == "" {
+			return
+		}
+		entry := TranscriptEntry{
+			Timestamp: time.Now().UnixMilli(),
+			Type:      "input",
+			Text:      line,
+			Version:   CurrentTranscriptSchemaVersion,
+		}
+		if tr.recordFilter != nil && !tr.recordFilter(entry) {
+			return
+		}
+		tr.appendEntry(entry)
+		if tr.flushStrategy != FlushStrategyTimeOnly && tr.bufferBytes >= TranscriptFlushThreshold {
+			tr.flush()
+		}
+		return
+	}
+	if len(data) == 1 && data[0] == 127 {
+		current := tr.pendingCommandLine.String()
+		if current != "" {
+			_, size := utf8.DecodeLastRuneInString(current)
+			tr.pendingCommandLine.Reset()
+			tr.pendingCommandLine.WriteString(current[:len(current)-size])
+		}
+		return
+	}
+	if len(data) == 1 && data[0] < 32 {
+		// Other single-byte control characters (Ctrl-C, Tab, ...) don't
+		// belong in a command line's text; ignore them.
+		return
+	}
+	if _, ok := keyNames[string(data)]; ok {
+		// A recognized multi-byte escape sequence (arrow keys, etc.)
+		// isn't part of a command line's text either.
+		return
+	}
+	tr.pendingCommandLine.WriteString(text)
+}
+
+// flushDupCountLocked appends a pending "[repeated N times]" summary entry
+// for any animation-like frames suppressed by RecordOutput's dedup logic
+// that haven't been summarized yet, and clears dupCount. Called both when a
+// differing frame arrives (the normal path) and from flush/Stop, so a flush
+// landing mid-run of duplicates doesn't silently drop the pending count.
+// Must be called with mu held.
+func (tr *TranscriptRecorder) flushDupCountLocked() {
+	if tr.dupCount == 0 {
+		return
+	}
+	tr.appendEntry(TranscriptEntry{
+		Timestamp: time.Now().UnixMilli(),
+		Type:      "output",
+		Text:      fmt.Sprintf("[repeated %d times]", tr.dupCount),
+		Version:   CurrentTranscriptSchemaVersion,
+	})
+	tr.dupCount = 0
+}
+
+// flush writes buffered entries to the flush function, in JSONL or the
+// compact binary encoding depending on binaryFormat. When binary, the
+// magic header is prepended to only the very first flush, since flushFn
+// appends each chunk to a single growing file.
 // Must be called with mu held.
 func (tr *TranscriptRecorder) flush() {
+	tr.flushDupCountLocked()
 	if len(tr.buffer) == 0 {
 		return
 	}
 
-	var lines []byte
-	for _, entry := range tr.buffer {
-		jsonLine, err := json.Marshal(entry)
-		if err != nil {
-			continue
+	var chunk []byte
+	if tr.binaryFormat {
+		chunk = EncodeTranscriptEntriesBinary(tr.buffer)
+		if tr.wroteBinaryHeader {
+			chunk = chunk[len(transcriptBinaryMagic):]
+		}
+		tr.wroteBinaryHeader = true
+	} else {
+		for _, entry := range tr.buffer {
+			jsonLine, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+			chunk = append(chunk, jsonLine...)
+			chunk = append(chunk, '\n')
 		}
-		lines = append(lines, jsonLine...)
-		lines = append(lines, '\n')
 	}
 
 	tr.buffer = tr.buffer[:0]
 	tr.bufferBytes = 0
 
-	if len(lines) > 0 && tr.flushFn != nil {
-		go tr.flushFn(lines)
+	if len(chunk) > 0 && tr.flushFn != nil {
+		go tr.flushFn(chunk)
 	}
 }
 
@@ -181,12 +851,13 @@ func (tr *TranscriptRecorder) Stop() {
 		tr.flushTimer.Stop()
 	}
 	tr.flush()
+	unregisterRecorder(tr)
 }
 
 // cleanForTranscript strips ANSI codes and normalizes whitespace.
 func cleanForTranscript(text string) string {
 	// Strip ANSI escape codes
-	cleaned := transcriptAnsiRegex.ReplaceAllString(text, "")
+	cleaned := StripAnsi(text)
 	// Remove carriage returns (keep newlines)
 	cleaned = strings.ReplaceAll(cleaned, "\r", "")
 	// Collapse multiple blank lines
@@ -196,6 +867,29 @@ func cleanForTranscript(text string) string {
 	return strings.TrimSpace(cleaned)
 }
 
+// keyNames maps known control/escape sequences to readable tokens, used by
+// RecordInput when readableKeyNames is enabled.
+var keyNames = map[string]string{
+	"\x1b[A": "<Up>",
+	"\x1b[B": "<Down>",
+	"\x1b[C": "<Right>",
+	"\x1b[D": "<Left>",
+	"\r":     "<Enter>",
+	"\n":     "<Enter>",
+	"\t":     "<Tab>",
+	"\x1b":   "<Esc>",
+	"\x7f":   "<Backspace>",
+	"\x03":   "<Ctrl-C>",
+	"\x04":   "<Ctrl-D>",
+}
+
+// keyName looks up data as a known control/escape sequence, returning its
+// readable token and true if found.
+func keyName(data []byte) (string, bool) {
+	name, ok := keyNames[string(data)]
+	return name, ok
+}
+
 // isAnimationFrame checks if the text looks like a spinner or progress update.
 func isAnimationFrame(text string) bool {
 	for _, pat := range animationPatterns {
@@ -205,3 +899,70 @@ func isAnimationFrame(text string) bool {
 	}
 	return false
 }
+
+// isAnimationLike reports whether cleaned should be treated as an
+// animation frame to dedup against tr.lastOutput: either an exact repeat of
+// a recognized spinner/progress pattern, or, when
+// animationSimilarityThreshold is enabled, text highly similar to the
+// previous line.
+func (tr *TranscriptRecorder) isAnimationLike(cleaned string) bool {
+	if isAnimationFrame(cleaned) && cleaned == tr.lastOutput {
+		return true
+	}
+	if tr.animationSimilarityThreshold > 0 && tr.lastOutput != "" {
+		if textSimilarity(cleaned, tr.lastOutput) >= tr.animationSimilarityThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// textSimilarity returns a similarity ratio in [0,1] between a and b based
+// on Levenshtein edit distance: 1 means identical, 0 means completely
+// different.
+func textSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	maxLen := len([]rune(a))
+	if bLen := len([]rune(b)); bLen > maxLen {
+		maxLen = bLen
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshteinDistance(a, b))/float64(maxLen)
+}
+
+// levenshteinDistance returns the number of single-character edits needed
+// to turn a into b.
+func levenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			del := prev[j] + 1
+			ins := curr[j-1] + 1
+			sub := prev[j-1] + cost
+			min := del
+			if ins < min {
+				min = ins
+			}
+			if sub < min {
+				min = sub
+			}
+			curr[j] = min
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
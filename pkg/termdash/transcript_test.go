@@ -0,0 +1,638 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTranscriptRecorderCoalescesRapidSameTypeEntries(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithCoalesceWindow(100*time.Millisecond))
+	defer tr.Stop()
+
+	// Simulate token-streaming: many tiny output chunks in quick succession.
+	tr.RecordOutput([]byte("Hel"))
+	tr.RecordOutput([]byte("lo, "))
+	tr.RecordOutput([]byte("world"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 coalesced entry, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Text != "Hello,world" {
+		t.Errorf("coalesced text = %q, want %q", entries[0].Text, "Hello,world")
+	}
+}
+
+func TestTranscriptRecorderPreservesBoundaryOnTypeChange(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithCoalesceWindow(100*time.Millisecond))
+	defer tr.Stop()
+
+	tr.RecordOutput([]byte("Building..."))
+	tr.RecordInput([]byte("y"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries across the output->input boundary, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Type != "output" || entries[1].Type != "input" {
+		t.Errorf("entry types = %q, %q, want output, input", entries[0].Type, entries[1].Type)
+	}
+}
+
+func TestTranscriptRecorderSimilarityModeCollapsesNearIdenticalLines(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithAnimationSimilarityThreshold(0.9))
+	defer tr.Stop()
+
+	// Log lines that differ only by a counter — not caught by the fixed
+	// spinner/progress regexes, but highly similar to one another.
+	tr.RecordOutput([]byte("processing item 1 of 500"))
+	tr.RecordOutput([]byte("processing item 2 of 500"))
+	tr.RecordOutput([]byte("processing item 3 of 500"))
+	tr.RecordOutput([]byte("build finished"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries (first line, dedup summary, final line), got %d: %v", len(entries), entries)
+	}
+	if entries[0].Text != "processing item 1 of 500" {
+		t.Errorf("entries[0].Text = %q, want first line kept", entries[0].Text)
+	}
+	if !strings.Contains(entries[1].Text, "repeated") {
+		t.Errorf("entries[1].Text = %q, want a dedup summary marker", entries[1].Text)
+	}
+	if entries[2].Text != "build finished" {
+		t.Errorf("entries[2].Text = %q, want %q", entries[2].Text, "build finished")
+	}
+}
+
+func TestTranscriptRecorderBufferBytesMatchesSerializedSize(t *testing.T) {
+	tr := NewTranscriptRecorder(nil)
+	defer tr.Stop()
+
+	tr.RecordOutput([]byte("normal output"))
+	// Duplicate spinner frames get suppressed into a dedup count, then
+	// summarized as a single "[repeated N]" entry once normal output resumes.
+	tr.RecordOutput([]byte("|"))
+	tr.RecordOutput([]byte("|"))
+	tr.RecordOutput([]byte("|"))
+	tr.RecordOutput([]byte("more output"))
+
+	var want int
+	for _, entry := range tr.buffer {
+		jsonLine, err := json.Marshal(entry)
+		if err != nil {
+			t.Fatalf("failed to marshal entry: %v", err)
+		}
+		want += len(jsonLine) + 1
+	}
+
+	if tr.bufferBytes != want {
+		t.Errorf("bufferBytes = %d, want %d (buffer: %v)", tr.bufferBytes, want, tr.buffer)
+	}
+}
+
+func TestTranscriptRecorderReadableKeyNamesTranslatesArrowKey(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithReadableKeyNames())
+	defer tr.Stop()
+
+	tr.RecordInput([]byte("\x1b[A"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Text != "<Up>" {
+		t.Errorf("entry text = %q, want %q", entries[0].Text, "<Up>")
+	}
+}
+
+func TestTranscriptRecorderRawModeKeepsRawInputByDefault(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	})
+	defer tr.Stop()
+
+	tr.RecordInput([]byte("\x1b[A"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Text != "\x1b[A" {
+		t.Errorf("entry text = %q, want raw escape sequence", entries[0].Text)
+	}
+}
+
+func TestTranscriptRecorderInputPrivacyFullRecordsEveryKeystroke(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithInputPrivacyMode(InputPrivacyFull))
+	defer tr.Stop()
+
+	tr.RecordInput([]byte("l"))
+	tr.RecordInput([]byte("s"))
+	tr.RecordInput([]byte("\r"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Text != "l" || entries[1].Text != "s" || entries[2].Text != "\r" {
+		t.Errorf("entries = %v, want per-keystroke entries", entries)
+	}
+}
+
+func TestTranscriptRecorderInputPrivacyNoneRecordsNothing(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithInputPrivacyMode(InputPrivacyNone))
+	defer tr.Stop()
+
+	tr.RecordInput([]byte("l"))
+	tr.RecordInput([]byte("s"))
+	tr.RecordInput([]byte("\r"))
+	tr.Stop()
+
+	select {
+	case flushed := <-flushCh:
+		entries := parseTranscriptLines(t, flushed)
+		if len(entries) != 0 {
+			t.Fatalf("expected no entries, got %d: %v", len(entries), entries)
+		}
+	default:
+		// Nothing was ever buffered, so flush had nothing to write — also fine.
+	}
+}
+
+func TestTranscriptRecorderInputPrivacyCommandsOnlyRecordsCompleteLine(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithInputPrivacyMode(InputPrivacyCommandsOnly))
+	defer tr.Stop()
+
+	for _, b := range []byte("gti") {
+		tr.RecordInput([]byte{b})
+	}
+	tr.RecordInput([]byte{127}) // backspace, correcting "gti" -> "gt"
+	tr.RecordInput([]byte("\x1b[A"))
+	tr.RecordInput([]byte("s"))
+	tr.RecordInput([]byte("\r"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Text != "gts" {
+		t.Errorf("entry text = %q, want %q", entries[0].Text, "gts")
+	}
+}
+
+func TestTranscriptRecorderRecordsBracketedPasteAsSingleEntry(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	})
+	defer tr.Stop()
+
+	tr.RecordInput([]byte("\x1b[200~pasted line one\npasted line two\x1b[201~"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Type != "paste" {
+		t.Errorf("entry type = %q, want %q", entries[0].Type, "paste")
+	}
+	wantText := "pasted line one\npasted line two"
+	if entries[0].Text != wantText {
+		t.Errorf("entry text = %q, want %q", entries[0].Text, wantText)
+	}
+}
+
+func TestTranscriptRecorderBracketedPasteSplitAcrossWritesIsOneEntry(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	})
+	defer tr.Stop()
+
+	tr.RecordInput([]byte("\x1b[200~part one "))
+	tr.RecordInput([]byte("part two"))
+	tr.RecordInput([]byte("\x1b[201~"))
+	tr.RecordInput([]byte("n")) // typed normally after the paste completes
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Type != "paste" || entries[0].Text != "part one part two" {
+		t.Errorf("entries[0] = %+v, want paste entry %q", entries[0], "part one part two")
+	}
+	if entries[1].Type != "input" || entries[1].Text != "n" {
+		t.Errorf("entries[1] = %+v, want input entry %q", entries[1], "n")
+	}
+}
+
+func TestTranscriptRecorderTruncatesHugePaste(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithMaxPasteBytes(10))
+	defer tr.Stop()
+
+	tr.RecordInput([]byte("\x1b[200~" + strings.Repeat("x", 20) + "\x1b[201~"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %v", len(entries), entries)
+	}
+	wantText := strings.Repeat("x", 10) + "[... 10 bytes truncated ...]"
+	if entries[0].Text != wantText {
+		t.Errorf("entry text = %q, want %q", entries[0].Text, wantText)
+	}
+}
+
+func TestTranscriptRecorderRecordsTaskEntryForSubagentBanner(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	})
+	defer tr.Stop()
+
+	tr.RecordOutput([]byte("⏺ Task(Investigate the flaky test)\n"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries (output + task), got %d: %v", len(entries), entries)
+	}
+	if entries[0].Type != "output" {
+		t.Errorf("entries[0].Type = %q, want %q", entries[0].Type, "output")
+	}
+	if entries[1].Type != "task" {
+		t.Errorf("entries[1].Type = %q, want %q", entries[1].Type, "task")
+	}
+	if entries[1].Text != "Investigate the flaky test" {
+		t.Errorf("entries[1].Text = %q, want %q", entries[1].Text, "Investigate the flaky test")
+	}
+}
+
+func TestTranscriptRecorderTextOnlyFilterDropsBinaryOutput(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithRecordFilter(TextOnlyFilter))
+	defer tr.Stop()
+
+	tr.RecordOutput([]byte("\x00\x01\x02\x03binary\x04\x05\x06garbage\x07"))
+	tr.RecordOutput([]byte("normal text output"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry (binary dropped), got %d: %v", len(entries), entries)
+	}
+	if entries[0].Text != "normal text output" {
+		t.Errorf("entries[0].Text = %q, want %q", entries[0].Text, "normal text output")
+	}
+}
+
+func TestTranscriptRecorderExcludeSubsystemLogFilterDropsOwnLogLines(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithRecordFilter(ExcludeSubsystemLogFilter))
+	defer tr.Stop()
+
+	tr.RecordOutput([]byte("[termdash:summary] generated title for block abc: \"fix flaky test\""))
+	tr.RecordOutput([]byte("normal session output"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry (subsystem log line dropped), got %d: %v", len(entries), entries)
+	}
+	if entries[0].Text != "normal session output" {
+		t.Errorf("entries[0].Text = %q, want %q", entries[0].Text, "normal session output")
+	}
+}
+
+func TestTranscriptRecorderSamplesLargeBurstKeepingHeadAndTail(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithOutputSampling(100, 3))
+	defer tr.Stop()
+
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("log line %d", i))
+	}
+	tr.RecordOutput([]byte(strings.Join(lines, "\n")))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %v", len(entries), entries)
+	}
+
+	got := strings.Split(entries[0].Text, "\n")
+	want := append(append([]string{}, lines[:3]...), "[... 94 lines omitted ...]")
+	want = append(want, lines[len(lines)-3:]...)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("sampled text = %q, want %q", got, want)
+	}
+}
+
+func TestTranscriptRecorderSamplingLeavesSmallBurstUntouched(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithOutputSampling(1000, 3))
+	defer tr.Stop()
+
+	tr.RecordOutput([]byte("just a few short lines\nnothing to sample here"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	entries := parseTranscriptLines(t, flushed)
+	if len(entries) != 1 || entries[0].Text != "just a few short lines\nnothing to sample here" {
+		t.Errorf("entries = %v, want the burst recorded unchanged", entries)
+	}
+}
+
+func TestIsMostlyBinaryOnPlainText(t *testing.T) {
+	if isMostlyBinary("hello, world\nsecond line", DefaultBinaryRatioThreshold) {
+		t.Error("plain text should not be classified as binary")
+	}
+}
+
+func TestCompactTranscriptEntriesCollapsesRepeatedAnimationFrames(t *testing.T) {
+	entries := []TranscriptEntry{
+		{Timestamp: 1000, Type: "output", Text: "|"},
+		{Timestamp: 1010, Type: "output", Text: "|"},
+		{Timestamp: 1020, Type: "output", Text: "|"},
+		{Timestamp: 1030, Type: "output", Text: "build finished"},
+	}
+
+	compacted := CompactTranscriptEntries(entries)
+	if len(compacted) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %v", len(compacted), compacted)
+	}
+	if compacted[0].Text != "[repeated 3 times]" {
+		t.Errorf("compacted[0].Text = %q, want a repeat marker", compacted[0].Text)
+	}
+	if compacted[1].Text != "build finished" {
+		t.Errorf("compacted[1].Text = %q, want %q", compacted[1].Text, "build finished")
+	}
+}
+
+func TestCompactTranscriptEntriesMergesConsecutiveOutputRegardlessOfTiming(t *testing.T) {
+	entries := []TranscriptEntry{
+		{Timestamp: 1000, Type: "output", Text: "Hel"},
+		{Timestamp: 999999, Type: "output", Text: "lo"},
+	}
+
+	compacted := CompactTranscriptEntries(entries)
+	if len(compacted) != 1 || compacted[0].Text != "Hello" {
+		t.Errorf("CompactTranscriptEntries() = %v, want a single merged \"Hello\" entry", compacted)
+	}
+}
+
+func TestCompactTranscriptEntriesPreservesInputEntriesExactly(t *testing.T) {
+	entries := []TranscriptEntry{
+		{Timestamp: 1000, Type: "output", Text: "a"},
+		{Timestamp: 1001, Type: "input", Text: "y"},
+		{Timestamp: 1002, Type: "output", Text: "b"},
+	}
+
+	compacted := CompactTranscriptEntries(entries)
+	if len(compacted) != 3 {
+		t.Fatalf("expected input entry to stay unmerged, got %d entries: %v", len(compacted), compacted)
+	}
+	if compacted[1] != entries[1] {
+		t.Errorf("input entry = %+v, want it preserved exactly as %+v", compacted[1], entries[1])
+	}
+}
+
+func TestMarshalTranscriptEntriesRoundTripsThroughParse(t *testing.T) {
+	entries := []TranscriptEntry{
+		{Timestamp: 1000, Type: "output", Text: "hello"},
+		{Timestamp: 2000, Type: "input", Text: "y"},
+	}
+
+	data := MarshalTranscriptEntries(entries)
+	got := ParseTranscriptEntries(data)
+	if len(got) != 2 || got[0] != entries[0] || got[1] != entries[1] {
+		t.Errorf("round trip = %v, want %v", got, entries)
+	}
+}
+
+func TestParseTranscriptEntriesReadsV0FileWithNoVersionField(t *testing.T) {
+	v0File := []byte(`{"ts":1000,"type":"output","text":"hello"}` + "\n")
+
+	got := ParseTranscriptEntries(v0File)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(got))
+	}
+	if got[0].Version != 0 {
+		t.Errorf("Version = %d, want 0 for a file predating the field", got[0].Version)
+	}
+	if got[0].Text != "hello" {
+		t.Errorf("Text = %q, want %q", got[0].Text, "hello")
+	}
+}
+
+func TestParseTranscriptEntriesReadsV1FileWithVersionField(t *testing.T) {
+	v1File := []byte(`{"ts":1000,"type":"output","text":"hello","v":1}` + "\n")
+
+	got := ParseTranscriptEntries(v1File)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(got))
+	}
+	if got[0].Version != 1 {
+		t.Errorf("Version = %d, want 1", got[0].Version)
+	}
+	if got[0].Text != "hello" {
+		t.Errorf("Text = %q, want %q", got[0].Text, "hello")
+	}
+}
+
+func TestMigrateTranscriptEntriesUpgradesV0EntriesToCurrentVersion(t *testing.T) {
+	v0Entries := ParseTranscriptEntries([]byte(`{"ts":1000,"type":"output","text":"hello"}` + "\n"))
+
+	migrated, changed := MigrateTranscriptEntries(v0Entries)
+	if !changed {
+		t.Fatal("expected changed = true for a v0 entry")
+	}
+	if len(migrated) != 1 || migrated[0].Version != CurrentTranscriptSchemaVersion {
+		t.Errorf("migrated entries = %v, want Version %d", migrated, CurrentTranscriptSchemaVersion)
+	}
+}
+
+func TestMigrateTranscriptEntriesNoopWhenAlreadyCurrent(t *testing.T) {
+	current := []TranscriptEntry{{Timestamp: 1000, Type: "output", Text: "hello", Version: CurrentTranscriptSchemaVersion}}
+
+	migrated, changed := MigrateTranscriptEntries(current)
+	if changed {
+		t.Error("expected changed = false when already current")
+	}
+	if len(migrated) != 1 || migrated[0] != current[0] {
+		t.Errorf("migrated entries = %v, want unchanged %v", migrated, current)
+	}
+}
+
+func TestTranscriptRecorderFlushEmitsPendingDupCountMarker(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithFlushStrategy(FlushStrategyTimeOnly))
+	defer tr.Stop()
+
+	tr.mu.Lock()
+	tr.flushTimer.Stop()
+	tr.flushInterval = 10 * time.Millisecond
+	tr.startFlushTimer()
+	tr.mu.Unlock()
+
+	tr.RecordOutput([]byte("normal output"))
+	// The first "|" is recorded as its own entry (it isn't similar to the
+	// preceding "normal output" line); only once it repeats does it become
+	// animation-like and start accumulating into dupCount. Those trailing
+	// dups are never followed by a differing frame, so nothing would
+	// normally flush the pending "[repeated N times]" summary.
+	tr.RecordOutput([]byte("|"))
+	tr.RecordOutput([]byte("|"))
+	tr.RecordOutput([]byte("|"))
+	tr.RecordOutput([]byte("|"))
+
+	select {
+	case flushed := <-flushCh:
+		entries := parseTranscriptLines(t, flushed)
+		if len(entries) != 3 {
+			t.Fatalf("expected 3 entries (first line, first spinner frame, dedup summary), got %d: %v", len(entries), entries)
+		}
+		if entries[0].Text != "normal output" {
+			t.Errorf("entries[0].Text = %q, want %q", entries[0].Text, "normal output")
+		}
+		if entries[1].Text != "|" {
+			t.Errorf("entries[1].Text = %q, want %q", entries[1].Text, "|")
+		}
+		if entries[2].Text != "[repeated 3 times]" {
+			t.Errorf("entries[2].Text = %q, want %q", entries[2].Text, "[repeated 3 times]")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the timer to flush the pending dup count")
+	}
+}
+
+func TestTranscriptRecorderTimeOnlyFlushesSubThresholdWriteOnTimer(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithFlushStrategy(FlushStrategyTimeOnly))
+	defer tr.Stop()
+
+	tr.mu.Lock()
+	tr.flushTimer.Stop()
+	tr.flushInterval = 10 * time.Millisecond
+	tr.startFlushTimer()
+	tr.mu.Unlock()
+
+	tr.RecordOutput([]byte("small write, well under the size threshold"))
+
+	select {
+	case flushed := <-flushCh:
+		entries := parseTranscriptLines(t, flushed)
+		if len(entries) != 1 || entries[0].Text != "small write, well under the size threshold" {
+			t.Errorf("flushed entries = %v, want the recorded output", entries)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the timer to flush a sub-threshold write")
+	}
+}
+
+func TestTranscriptRecorderSizeOnlyNeverFlushesOnTimer(t *testing.T) {
+	flushCh := make(chan []byte, 1)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithFlushStrategy(FlushStrategySizeOnly))
+	defer tr.Stop()
+
+	tr.mu.Lock()
+	tr.flushTimer.Stop()
+	tr.flushInterval = 10 * time.Millisecond
+	tr.startFlushTimer()
+	tr.mu.Unlock()
+
+	tr.RecordOutput([]byte("small write, well under the size threshold"))
+
+	select {
+	case flushed := <-flushCh:
+		t.Fatalf("timer flushed in size-only mode, want no flush: %s", flushed)
+	case <-time.After(100 * time.Millisecond):
+		// Expected: several timer periods elapsed with no flush.
+	}
+}
+
+func parseTranscriptLines(t *testing.T, data []byte) []TranscriptEntry {
+	t.Helper()
+	var entries []TranscriptEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry TranscriptEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to unmarshal transcript line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
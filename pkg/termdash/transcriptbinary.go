@@ -0,0 +1,136 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// Transcript encodings selectable via the termdash:transcriptformat setting.
+const (
+	TranscriptFormatJSONL  = "jsonl"
+	TranscriptFormatBinary = "binary"
+)
+
+// transcriptBinaryMagic prefixes binary-encoded transcripts. It starts with
+// a NUL byte, which never appears in valid JSONL, so readers can tell the
+// two formats apart with a single peek at the first byte.
+var transcriptBinaryMagic = []byte{0x00, 'T', 'D', 'B', '1'}
+
+// entryTypeOutput and entryTypeInput are the binary encoding's compact
+// representation of TranscriptEntry.Type, avoiding a length-prefixed string
+// for the two well-known values used in practice.
+const (
+	entryTypeOutput byte = 0
+	entryTypeInput  byte = 1
+	entryTypeOther  byte = 2
+)
+
+// IsBinaryTranscript reports whether data is a binary-encoded transcript,
+// as opposed to JSONL.
+func IsBinaryTranscript(data []byte) bool {
+	return bytes.HasPrefix(data, transcriptBinaryMagic)
+}
+
+// EncodeTranscriptEntriesBinary serializes entries into the compact binary
+// transcript format: a magic header followed by one record per entry
+// (timestamp, type, and length-prefixed text, all varint-encoded where
+// possible). It is substantially smaller than JSONL for high-volume
+// transcripts since it avoids per-entry field names and quoting.
+func EncodeTranscriptEntriesBinary(entries []TranscriptEntry) []byte {
+	buf := make([]byte, 0, len(transcriptBinaryMagic)+len(entries)*16)
+	buf = append(buf, transcriptBinaryMagic...)
+
+	var scratch [binary.MaxVarintLen64]byte
+	for _, entry := range entries {
+		n := binary.PutVarint(scratch[:], entry.Timestamp)
+		buf = append(buf, scratch[:n]...)
+
+		switch entry.Type {
+		case "output":
+			buf = append(buf, entryTypeOutput)
+		case "input":
+			buf = append(buf, entryTypeInput)
+		default:
+			buf = append(buf, entryTypeOther)
+			n = binary.PutUvarint(scratch[:], uint64(len(entry.Type)))
+			buf = append(buf, scratch[:n]...)
+			buf = append(buf, entry.Type...)
+		}
+
+		n = binary.PutUvarint(scratch[:], uint64(len(entry.Text)))
+		buf = append(buf, scratch[:n]...)
+		buf = append(buf, entry.Text...)
+	}
+	return buf
+}
+
+// DecodeTranscriptEntriesBinary parses a transcript encoded with
+// EncodeTranscriptEntriesBinary. It stops and returns what it has decoded
+// so far if the data is truncated or malformed, mirroring
+// ParseTranscriptEntries' tolerance of bad input.
+func DecodeTranscriptEntriesBinary(data []byte) []TranscriptEntry {
+	if !IsBinaryTranscript(data) {
+		return nil
+	}
+	r := bytes.NewReader(data[len(transcriptBinaryMagic):])
+
+	var entries []TranscriptEntry
+	for r.Len() > 0 {
+		entry, ok := decodeOneTranscriptEntry(r)
+		if !ok {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func decodeOneTranscriptEntry(r *bytes.Reader) (TranscriptEntry, bool) {
+	timestamp, err := binary.ReadVarint(r)
+	if err != nil {
+		return TranscriptEntry{}, false
+	}
+	entryType, err := r.ReadByte()
+	if err != nil {
+		return TranscriptEntry{}, false
+	}
+
+	var typeStr string
+	switch entryType {
+	case entryTypeOutput:
+		typeStr = "output"
+	case entryTypeInput:
+		typeStr = "input"
+	case entryTypeOther:
+		typeLen, err := binary.ReadUvarint(r)
+		if err != nil {
+			return TranscriptEntry{}, false
+		}
+		typeBytes := make([]byte, typeLen)
+		if _, err := io.ReadFull(r, typeBytes); err != nil {
+			return TranscriptEntry{}, false
+		}
+		typeStr = string(typeBytes)
+	default:
+		return TranscriptEntry{}, false
+	}
+
+	textLen, err := binary.ReadUvarint(r)
+	if err != nil {
+		return TranscriptEntry{}, false
+	}
+	textBytes := make([]byte, textLen)
+	if _, err := io.ReadFull(r, textBytes); err != nil {
+		return TranscriptEntry{}, false
+	}
+
+	return TranscriptEntry{
+		Timestamp: timestamp,
+		Type:      typeStr,
+		Text:      string(textBytes),
+	}, true
+}
@@ -0,0 +1,125 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestBinaryTranscriptRoundTrips(t *testing.T) {
+	entries := []TranscriptEntry{
+		{Timestamp: 1000, Type: "output", Text: "hello\nworld"},
+		{Timestamp: 2000, Type: "input", Text: "y"},
+		{Timestamp: 3000, Type: "output", Text: ""},
+		{Timestamp: -1, Type: "custom", Text: "unusual entry type"},
+	}
+
+	data := EncodeTranscriptEntriesBinary(entries)
+	got := DecodeTranscriptEntriesBinary(data)
+	if len(got) != len(entries) {
+		t.Fatalf("got %d entries, want %d: %v", len(got), len(entries), got)
+	}
+	for i := range entries {
+		if got[i] != entries[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], entries[i])
+		}
+	}
+}
+
+func TestIsBinaryTranscriptDetectsFormat(t *testing.T) {
+	binary := EncodeTranscriptEntriesBinary([]TranscriptEntry{{Timestamp: 1, Type: "output", Text: "x"}})
+	if !IsBinaryTranscript(binary) {
+		t.Error("expected binary-encoded transcript to be detected as binary")
+	}
+
+	jsonl := MarshalTranscriptEntries([]TranscriptEntry{{Timestamp: 1, Type: "output", Text: "x"}})
+	if IsBinaryTranscript(jsonl) {
+		t.Error("expected JSONL transcript to not be detected as binary")
+	}
+}
+
+func TestDecodeTranscriptEntriesAutoDetectsFormat(t *testing.T) {
+	entries := []TranscriptEntry{{Timestamp: 1, Type: "output", Text: "hello"}}
+
+	gotFromBinary := DecodeTranscriptEntries(EncodeTranscriptEntriesBinary(entries))
+	if len(gotFromBinary) != 1 || gotFromBinary[0] != entries[0] {
+		t.Errorf("DecodeTranscriptEntries(binary) = %v, want %v", gotFromBinary, entries)
+	}
+
+	gotFromJSONL := DecodeTranscriptEntries(MarshalTranscriptEntries(entries))
+	if len(gotFromJSONL) != 1 || gotFromJSONL[0] != entries[0] {
+		t.Errorf("DecodeTranscriptEntries(jsonl) = %v, want %v", gotFromJSONL, entries)
+	}
+}
+
+func TestDecodeTranscriptEntriesBinaryHandlesTruncatedData(t *testing.T) {
+	entries := []TranscriptEntry{
+		{Timestamp: 1, Type: "output", Text: "complete entry"},
+		{Timestamp: 2, Type: "output", Text: "truncated entry"},
+	}
+	data := EncodeTranscriptEntriesBinary(entries)
+	truncated := data[:len(data)-3]
+
+	got := DecodeTranscriptEntriesBinary(truncated)
+	if len(got) != 1 || got[0] != entries[0] {
+		t.Errorf("got %v, want only the first complete entry %v", got, entries[0])
+	}
+}
+
+func TestEncodeTranscriptEntriesFallsBackToJSONLForUnknownFormat(t *testing.T) {
+	entries := []TranscriptEntry{{Timestamp: 1, Type: "output", Text: "hello"}}
+	got := EncodeTranscriptEntries(entries, "unknown-format")
+	if IsBinaryTranscript(got) {
+		t.Error("expected unknown format to fall back to JSONL")
+	}
+	if len(ParseTranscriptEntries(got)) != 1 {
+		t.Error("expected fallback output to parse as valid JSONL")
+	}
+}
+
+func TestTranscriptRecorderBinaryFormatFlushesDecodableData(t *testing.T) {
+	flushCh := make(chan []byte, 4)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithBinaryFormat())
+	defer tr.Stop()
+
+	tr.RecordOutput([]byte("first chunk"))
+	tr.Stop()
+
+	flushed := <-flushCh
+	if !IsBinaryTranscript(flushed) {
+		t.Fatal("expected first flush to include the binary format header")
+	}
+	entries := DecodeTranscriptEntriesBinary(flushed)
+	if len(entries) != 1 || entries[0].Text != "first chunk" {
+		t.Errorf("entries = %v, want a single \"first chunk\" entry", entries)
+	}
+}
+
+func TestTranscriptRecorderBinaryFormatOnlyPrefixesFirstFlush(t *testing.T) {
+	flushCh := make(chan []byte, 4)
+	tr := NewTranscriptRecorder(func(data []byte) {
+		flushCh <- data
+	}, WithBinaryFormat())
+	defer tr.Stop()
+
+	tr.RecordOutput([]byte("first"))
+	tr.flush()
+	first := <-flushCh // wait for the first flush to land before triggering the second
+
+	tr.RecordOutput([]byte("second"))
+	tr.Stop()
+	second := <-flushCh
+	if !IsBinaryTranscript(first) {
+		t.Error("expected the first flush to carry the magic header")
+	}
+	if IsBinaryTranscript(second) {
+		t.Error("expected the second flush to omit the magic header")
+	}
+
+	full := append(append([]byte{}, first...), second...)
+	entries := DecodeTranscriptEntriesBinary(full)
+	if len(entries) != 2 || entries[0].Text != "first" || entries[1].Text != "second" {
+		t.Errorf("concatenated flushes decoded to %v, want [first, second]", entries)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Usage holds parsed token/cost totals from a Claude Code session's
+// end-of-session usage/cost summary banner.
+type Usage struct {
+	TokensIn  int64   `json:"tokensin"`
+	TokensOut int64   `json:"tokensout"`
+	CostUSD   float64 `json:"costusd"`
+}
+
+var (
+	usageCostRegex   = regexp.MustCompile(`(?i)total cost:\s*\$([0-9]+(?:\.[0-9]+)?)`)
+	usageTokensRegex = regexp.MustCompile(`(?i)tokens:\s*([0-9,]+)\s*input,\s*([0-9,]+)\s*output`)
+)
+
+// ParseUsageSummary scans output for Claude Code's end-of-session usage/cost
+// banner and extracts token counts and cost. Returns false if no banner is
+// found, e.g. the session was killed rather than exiting cleanly.
+func ParseUsageSummary(output string) (*Usage, bool) {
+	costMatch := usageCostRegex.FindStringSubmatch(output)
+	tokensMatch := usageTokensRegex.FindStringSubmatch(output)
+	if costMatch == nil && tokensMatch == nil {
+		return nil, false
+	}
+
+	usage := &Usage{}
+	if costMatch != nil {
+		usage.CostUSD, _ = strconv.ParseFloat(costMatch[1], 64)
+	}
+	if tokensMatch != nil {
+		usage.TokensIn = parseCommaInt(tokensMatch[1])
+		usage.TokensOut = parseCommaInt(tokensMatch[2])
+	}
+	return usage, true
+}
+
+// parseCommaInt parses an integer that may contain thousands separators
+// (e.g. "12,345"), ignoring the error since callers only pass regex-matched
+// digit groups.
+func parseCommaInt(s string) int64 {
+	n, _ := strconv.ParseInt(strings.ReplaceAll(s, ",", ""), 10, 64)
+	return n
+}
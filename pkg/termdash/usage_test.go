@@ -0,0 +1,31 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termdash
+
+import "testing"
+
+func TestParseUsageSummary(t *testing.T) {
+	banner := "Total cost: $0.45\nTotal duration: 3m12s\nTokens: 12,345 input, 6,789 output\n"
+
+	usage, ok := ParseUsageSummary(banner)
+	if !ok {
+		t.Fatal("expected usage summary to be found")
+	}
+	if usage.CostUSD != 0.45 {
+		t.Errorf("CostUSD = %v, want 0.45", usage.CostUSD)
+	}
+	if usage.TokensIn != 12345 {
+		t.Errorf("TokensIn = %v, want 12345", usage.TokensIn)
+	}
+	if usage.TokensOut != 6789 {
+		t.Errorf("TokensOut = %v, want 6789", usage.TokensOut)
+	}
+}
+
+func TestParseUsageSummaryNotFound(t *testing.T) {
+	_, ok := ParseUsageSummary("regular terminal output with no banner\n")
+	if ok {
+		t.Error("expected no usage summary to be found")
+	}
+}
@@ -6,154 +6,166 @@
 package waveobj
 
 const (
-	MetaKey_View                             = "view"
-
-	MetaKey_Controller                       = "controller"
-
-	MetaKey_File                             = "file"
-
-	MetaKey_Url                              = "url"
-
-	MetaKey_PinnedUrl                        = "pinnedurl"
-
-	MetaKey_Connection                       = "connection"
-
-	MetaKey_Edit                             = "edit"
-
-	MetaKey_History                          = "history"
-	MetaKey_HistoryForward                   = "history:forward"
-
-	MetaKey_DisplayName                      = "display:name"
-	MetaKey_DisplayOrder                     = "display:order"
-
-	MetaKey_Icon                             = "icon"
-	MetaKey_IconColor                        = "icon:color"
-
-	MetaKey_FrameClear                       = "frame:*"
-	MetaKey_Frame                            = "frame"
-	MetaKey_FrameBorderColor                 = "frame:bordercolor"
-	MetaKey_FrameActiveBorderColor           = "frame:activebordercolor"
-	MetaKey_FrameTitle                       = "frame:title"
-	MetaKey_FrameIcon                        = "frame:icon"
-	MetaKey_FrameText                        = "frame:text"
-
-	MetaKey_CmdClear                         = "cmd:*"
-	MetaKey_Cmd                              = "cmd"
-	MetaKey_CmdInteractive                   = "cmd:interactive"
-	MetaKey_CmdLogin                         = "cmd:login"
-	MetaKey_CmdPersistent                    = "cmd:persistent"
-	MetaKey_CmdRunOnStart                    = "cmd:runonstart"
-	MetaKey_CmdClearOnStart                  = "cmd:clearonstart"
-	MetaKey_CmdRunOnce                       = "cmd:runonce"
-	MetaKey_CmdCloseOnExit                   = "cmd:closeonexit"
-	MetaKey_CmdCloseOnExitForce              = "cmd:closeonexitforce"
-	MetaKey_CmdCloseOnExitDelay              = "cmd:closeonexitdelay"
-	MetaKey_CmdNoWsh                         = "cmd:nowsh"
-	MetaKey_CmdArgs                          = "cmd:args"
-	MetaKey_CmdShell                         = "cmd:shell"
-	MetaKey_CmdAllowConnChange               = "cmd:allowconnchange"
-	MetaKey_CmdJwt                           = "cmd:jwt"
-	MetaKey_CmdEnv                           = "cmd:env"
-	MetaKey_CmdCwd                           = "cmd:cwd"
-	MetaKey_CmdInitScript                    = "cmd:initscript"
-	MetaKey_CmdInitScriptSh                  = "cmd:initscript.sh"
-	MetaKey_CmdInitScriptBash                = "cmd:initscript.bash"
-	MetaKey_CmdInitScriptZsh                 = "cmd:initscript.zsh"
-	MetaKey_CmdInitScriptPwsh                = "cmd:initscript.pwsh"
-	MetaKey_CmdInitScriptFish                = "cmd:initscript.fish"
-
-	MetaKey_AiClear                          = "ai:*"
-	MetaKey_AiPresetKey                      = "ai:preset"
-	MetaKey_AiApiType                        = "ai:apitype"
-	MetaKey_AiBaseURL                        = "ai:baseurl"
-	MetaKey_AiApiToken                       = "ai:apitoken"
-	MetaKey_AiName                           = "ai:name"
-	MetaKey_AiModel                          = "ai:model"
-	MetaKey_AiOrgID                          = "ai:orgid"
-	MetaKey_AIApiVersion                     = "ai:apiversion"
-	MetaKey_AiMaxTokens                      = "ai:maxtokens"
-	MetaKey_AiTimeoutMs                      = "ai:timeoutms"
-
-	MetaKey_AiFileDiffChatId                 = "aifilediff:chatid"
-	MetaKey_AiFileDiffToolCallId             = "aifilediff:toolcallid"
-
-	MetaKey_EditorClear                      = "editor:*"
-	MetaKey_EditorMinimapEnabled             = "editor:minimapenabled"
-	MetaKey_EditorStickyScrollEnabled        = "editor:stickyscrollenabled"
-	MetaKey_EditorWordWrap                   = "editor:wordwrap"
-	MetaKey_EditorFontSize                   = "editor:fontsize"
-
-	MetaKey_GraphClear                       = "graph:*"
-	MetaKey_GraphNumPoints                   = "graph:numpoints"
-	MetaKey_GraphMetrics                     = "graph:metrics"
-
-	MetaKey_SysinfoType                      = "sysinfo:type"
-
-	MetaKey_BgClear                          = "bg:*"
-	MetaKey_Bg                               = "bg"
-	MetaKey_BgOpacity                        = "bg:opacity"
-	MetaKey_BgBlendMode                      = "bg:blendmode"
-	MetaKey_BgBorderColor                    = "bg:bordercolor"
-	MetaKey_BgActiveBorderColor              = "bg:activebordercolor"
-
-	MetaKey_WaveAiPanelOpen                  = "waveai:panelopen"
-	MetaKey_WaveAiPanelWidth                 = "waveai:panelwidth"
-	MetaKey_WaveAiModel                      = "waveai:model"
-	MetaKey_WaveAiChatId                     = "waveai:chatid"
-	MetaKey_WaveAiWidgetContext              = "waveai:widgetcontext"
-
-	MetaKey_TermClear                        = "term:*"
-	MetaKey_TermFontSize                     = "term:fontsize"
-	MetaKey_TermFontFamily                   = "term:fontfamily"
-	MetaKey_TermMode                         = "term:mode"
-	MetaKey_TermTheme                        = "term:theme"
-	MetaKey_TermLocalShellPath               = "term:localshellpath"
-	MetaKey_TermLocalShellOpts               = "term:localshellopts"
-	MetaKey_TermScrollback                   = "term:scrollback"
-	MetaKey_TermVDomSubBlockId               = "term:vdomblockid"
-	MetaKey_TermVDomToolbarBlockId           = "term:vdomtoolbarblockid"
-	MetaKey_TermTransparency                 = "term:transparency"
-	MetaKey_TermAllowBracketedPaste          = "term:allowbracketedpaste"
-	MetaKey_TermShiftEnterNewline            = "term:shiftenternewline"
-	MetaKey_TermMacOptionIsMeta              = "term:macoptionismeta"
-	MetaKey_TermConnDebug                    = "term:conndebug"
-	MetaKey_TermBellSound                    = "term:bellsound"
-	MetaKey_TermBellIndicator                = "term:bellindicator"
-	MetaKey_TermDurable                      = "term:durable"
-
-	MetaKey_WebZoom                          = "web:zoom"
-	MetaKey_WebHideNav                       = "web:hidenav"
-	MetaKey_WebPartition                     = "web:partition"
-	MetaKey_WebUserAgentType                 = "web:useragenttype"
-
-	MetaKey_MarkdownFontSize                 = "markdown:fontsize"
-	MetaKey_MarkdownFixedFontSize            = "markdown:fixedfontsize"
-
-	MetaKey_TsunamiClear                     = "tsunami:*"
-	MetaKey_TsunamiSdkReplacePath            = "tsunami:sdkreplacepath"
-	MetaKey_TsunamiAppPath                   = "tsunami:apppath"
-	MetaKey_TsunamiAppId                     = "tsunami:appid"
-	MetaKey_TsunamiScaffoldPath              = "tsunami:scaffoldpath"
-	MetaKey_TsunamiEnv                       = "tsunami:env"
-
-	MetaKey_VDomClear                        = "vdom:*"
-	MetaKey_VDomInitialized                  = "vdom:initialized"
-	MetaKey_VDomCorrelationId                = "vdom:correlationid"
-	MetaKey_VDomRoute                        = "vdom:route"
-	MetaKey_VDomPersist                      = "vdom:persist"
-
-	MetaKey_OnboardingGithubStar             = "onboarding:githubstar"
-	MetaKey_OnboardingLastVersion            = "onboarding:lastversion"
-
-	MetaKey_Count                            = "count"
-
-	MetaKey_TermDashClear                    = "termdash:*"
-	MetaKey_TermDashType                     = "termdash:type"
-	MetaKey_TermDashClaudeSession            = "termdash:claudesessionid"
-	MetaKey_TermDashResume                   = "termdash:resume"
-	MetaKey_TermDashStatus                   = "termdash:status"
-	MetaKey_TermDashSummary                  = "termdash:summary"
-	MetaKey_TermDashArchived                 = "termdash:archived"
-	MetaKey_TermDashArchivedAt               = "termdash:archivedat"
+	MetaKey_View = "view"
+
+	MetaKey_Controller = "controller"
+
+	MetaKey_File = "file"
+
+	MetaKey_Url = "url"
+
+	MetaKey_PinnedUrl = "pinnedurl"
+
+	MetaKey_Connection = "connection"
+
+	MetaKey_Edit = "edit"
+
+	MetaKey_History        = "history"
+	MetaKey_HistoryForward = "history:forward"
+
+	MetaKey_DisplayName  = "display:name"
+	MetaKey_DisplayOrder = "display:order"
+
+	MetaKey_Icon      = "icon"
+	MetaKey_IconColor = "icon:color"
+
+	MetaKey_FrameClear             = "frame:*"
+	MetaKey_Frame                  = "frame"
+	MetaKey_FrameBorderColor       = "frame:bordercolor"
+	MetaKey_FrameActiveBorderColor = "frame:activebordercolor"
+	MetaKey_FrameTitle             = "frame:title"
+	MetaKey_FrameIcon              = "frame:icon"
+	MetaKey_FrameText              = "frame:text"
+
+	MetaKey_CmdClear            = "cmd:*"
+	MetaKey_Cmd                 = "cmd"
+	MetaKey_CmdInteractive      = "cmd:interactive"
+	MetaKey_CmdLogin            = "cmd:login"
+	MetaKey_CmdPersistent       = "cmd:persistent"
+	MetaKey_CmdRunOnStart       = "cmd:runonstart"
+	MetaKey_CmdClearOnStart     = "cmd:clearonstart"
+	MetaKey_CmdRunOnce          = "cmd:runonce"
+	MetaKey_CmdCloseOnExit      = "cmd:closeonexit"
+	MetaKey_CmdCloseOnExitForce = "cmd:closeonexitforce"
+	MetaKey_CmdCloseOnExitDelay = "cmd:closeonexitdelay"
+	MetaKey_CmdNoWsh            = "cmd:nowsh"
+	MetaKey_CmdArgs             = "cmd:args"
+	MetaKey_CmdShell            = "cmd:shell"
+	MetaKey_CmdAllowConnChange  = "cmd:allowconnchange"
+	MetaKey_CmdJwt              = "cmd:jwt"
+	MetaKey_CmdEnv              = "cmd:env"
+	MetaKey_CmdCwd              = "cmd:cwd"
+	MetaKey_CmdInitScript       = "cmd:initscript"
+	MetaKey_CmdInitScriptSh     = "cmd:initscript.sh"
+	MetaKey_CmdInitScriptBash   = "cmd:initscript.bash"
+	MetaKey_CmdInitScriptZsh    = "cmd:initscript.zsh"
+	MetaKey_CmdInitScriptPwsh   = "cmd:initscript.pwsh"
+	MetaKey_CmdInitScriptFish   = "cmd:initscript.fish"
+
+	MetaKey_AiClear      = "ai:*"
+	MetaKey_AiPresetKey  = "ai:preset"
+	MetaKey_AiApiType    = "ai:apitype"
+	MetaKey_AiBaseURL    = "ai:baseurl"
+	MetaKey_AiApiToken   = "ai:apitoken"
+	MetaKey_AiName       = "ai:name"
+	MetaKey_AiModel      = "ai:model"
+	MetaKey_AiOrgID      = "ai:orgid"
+	MetaKey_AIApiVersion = "ai:apiversion"
+	MetaKey_AiMaxTokens  = "ai:maxtokens"
+	MetaKey_AiTimeoutMs  = "ai:timeoutms"
+
+	MetaKey_AiFileDiffChatId     = "aifilediff:chatid"
+	MetaKey_AiFileDiffToolCallId = "aifilediff:toolcallid"
+
+	MetaKey_EditorClear               = "editor:*"
+	MetaKey_EditorMinimapEnabled      = "editor:minimapenabled"
+	MetaKey_EditorStickyScrollEnabled = "editor:stickyscrollenabled"
+	MetaKey_EditorWordWrap            = "editor:wordwrap"
+	MetaKey_EditorFontSize            = "editor:fontsize"
+
+	MetaKey_GraphClear     = "graph:*"
+	MetaKey_GraphNumPoints = "graph:numpoints"
+	MetaKey_GraphMetrics   = "graph:metrics"
+
+	MetaKey_SysinfoType = "sysinfo:type"
+
+	MetaKey_BgClear             = "bg:*"
+	MetaKey_Bg                  = "bg"
+	MetaKey_BgOpacity           = "bg:opacity"
+	MetaKey_BgBlendMode         = "bg:blendmode"
+	MetaKey_BgBorderColor       = "bg:bordercolor"
+	MetaKey_BgActiveBorderColor = "bg:activebordercolor"
+
+	MetaKey_WaveAiPanelOpen     = "waveai:panelopen"
+	MetaKey_WaveAiPanelWidth    = "waveai:panelwidth"
+	MetaKey_WaveAiModel         = "waveai:model"
+	MetaKey_WaveAiChatId        = "waveai:chatid"
+	MetaKey_WaveAiWidgetContext = "waveai:widgetcontext"
+
+	MetaKey_TermClear               = "term:*"
+	MetaKey_TermFontSize            = "term:fontsize"
+	MetaKey_TermFontFamily          = "term:fontfamily"
+	MetaKey_TermMode                = "term:mode"
+	MetaKey_TermTheme               = "term:theme"
+	MetaKey_TermLocalShellPath      = "term:localshellpath"
+	MetaKey_TermLocalShellOpts      = "term:localshellopts"
+	MetaKey_TermScrollback          = "term:scrollback"
+	MetaKey_TermVDomSubBlockId      = "term:vdomblockid"
+	MetaKey_TermVDomToolbarBlockId  = "term:vdomtoolbarblockid"
+	MetaKey_TermTransparency        = "term:transparency"
+	MetaKey_TermAllowBracketedPaste = "term:allowbracketedpaste"
+	MetaKey_TermShiftEnterNewline   = "term:shiftenternewline"
+	MetaKey_TermMacOptionIsMeta     = "term:macoptionismeta"
+	MetaKey_TermConnDebug           = "term:conndebug"
+	MetaKey_TermBellSound           = "term:bellsound"
+	MetaKey_TermBellIndicator       = "term:bellindicator"
+	MetaKey_TermDurable             = "term:durable"
+
+	MetaKey_WebZoom          = "web:zoom"
+	MetaKey_WebHideNav       = "web:hidenav"
+	MetaKey_WebPartition     = "web:partition"
+	MetaKey_WebUserAgentType = "web:useragenttype"
+
+	MetaKey_MarkdownFontSize      = "markdown:fontsize"
+	MetaKey_MarkdownFixedFontSize = "markdown:fixedfontsize"
+
+	MetaKey_TsunamiClear          = "tsunami:*"
+	MetaKey_TsunamiSdkReplacePath = "tsunami:sdkreplacepath"
+	MetaKey_TsunamiAppPath        = "tsunami:apppath"
+	MetaKey_TsunamiAppId          = "tsunami:appid"
+	MetaKey_TsunamiScaffoldPath   = "tsunami:scaffoldpath"
+	MetaKey_TsunamiEnv            = "tsunami:env"
+
+	MetaKey_VDomClear         = "vdom:*"
+	MetaKey_VDomInitialized   = "vdom:initialized"
+	MetaKey_VDomCorrelationId = "vdom:correlationid"
+	MetaKey_VDomRoute         = "vdom:route"
+	MetaKey_VDomPersist       = "vdom:persist"
+
+	MetaKey_OnboardingGithubStar  = "onboarding:githubstar"
+	MetaKey_OnboardingLastVersion = "onboarding:lastversion"
+
+	MetaKey_Count = "count"
+
+	MetaKey_TermDashClear            = "termdash:*"
+	MetaKey_TermDashType             = "termdash:type"
+	MetaKey_TermDashClaudeSession    = "termdash:claudesessionid"
+	MetaKey_TermDashResume           = "termdash:resume"
+	MetaKey_TermDashResumedFrom      = "termdash:resumedfrom"
+	MetaKey_TermDashStatus           = "termdash:status"
+	MetaKey_TermDashSummary          = "termdash:summary"
+	MetaKey_TermDashUsage            = "termdash:usage"
+	MetaKey_TermDashArchived         = "termdash:archived"
+	MetaKey_TermDashArchivedAt       = "termdash:archivedat"
+	MetaKey_TermDashClaudeMode       = "termdash:claudemode"
+	MetaKey_TermDashPinned           = "termdash:pinned"
+	MetaKey_TermDashNoRecord         = "termdash:norecord"
+	MetaKey_TermDashCwd              = "termdash:cwd"
+	MetaKey_TermDashSummaryManual    = "termdash:summarymanual"
+	MetaKey_TermDashInteractive      = "termdash:interactive"
+	MetaKey_TermDashResumeContext    = "termdash:resumecontext"
+	MetaKey_TermDashResumeContextLen = "termdash:resumecontextlen"
+	MetaKey_TermDashClaudeModel      = "termdash:claudemodel"
+	MetaKey_TermDashAutoLearnings    = "termdash:autolearnings"
+	MetaKey_TermDashShell            = "termdash:shell"
 )
-
@@ -152,14 +152,22 @@ type MetaTSType struct {
 	Count int `json:"count,omitempty"` // temp for cpu plot. will remove later
 
 	// TermDash: Claude Code session management
-	TermDashClear          bool   `json:"termdash:*,omitempty"`
-	TermDashType           string `json:"termdash:type,omitempty"`           // "claude" or "" (shell)
-	TermDashClaudeSession  string `json:"termdash:claudesessionid,omitempty"` // UUID for Claude session tracking
-	TermDashResume         bool   `json:"termdash:resume,omitempty"`          // resume Claude session on start
-	TermDashStatus         string `json:"termdash:status,omitempty"`          // "active", "idle", "needs-input", "exited"
-	TermDashSummary        string `json:"termdash:summary,omitempty"`         // AI-generated session title
-	TermDashArchived       bool   `json:"termdash:archived,omitempty"`
-	TermDashArchivedAt     int64  `json:"termdash:archivedat,omitempty"`
+	TermDashClear         bool   `json:"termdash:*,omitempty"`
+	TermDashType          string `json:"termdash:type,omitempty"`            // "claude" or "" (shell)
+	TermDashClaudeSession string `json:"termdash:claudesessionid,omitempty"` // UUID for Claude session tracking
+	TermDashResume        bool   `json:"termdash:resume,omitempty"`          // resume Claude session on start
+	TermDashResumedFrom   string `json:"termdash:resumedfrom,omitempty"`     // original session ID this session was resumed from
+	TermDashStatus        string `json:"termdash:status,omitempty"`          // "active", "idle", "needs-input", "exited", "rate-limited", "tool-running", "error"
+	TermDashSummary       string `json:"termdash:summary,omitempty"`         // AI-generated session title
+	TermDashUsage         string `json:"termdash:usage,omitempty"`           // JSON-encoded Usage (tokens/cost) from the session's exit banner
+	TermDashArchived      bool   `json:"termdash:archived,omitempty"`
+	TermDashArchivedAt    int64  `json:"termdash:archivedat,omitempty"`
+	TermDashClaudeMode    string `json:"termdash:claudemode,omitempty"`    // "plan", "accept-edits", or "" (default)
+	TermDashPinned        bool   `json:"termdash:pinned,omitempty"`        // exempt from retention sweeps
+	TermDashNoRecord      bool   `json:"termdash:norecord,omitempty"`      // exempt from retention sweeps; recording/rotation disabled
+	TermDashCwd           string `json:"termdash:cwd,omitempty"`           // current working directory, tracked from typed cd commands
+	TermDashSummaryManual bool   `json:"termdash:summarymanual,omitempty"` // true once the user has set the title manually; blocks the poll loop and RegenerateAllSummaries from overwriting it
+	TermDashInteractive   bool   `json:"termdash:interactive,omitempty"`   // false for one-shot `claude -p` invocations; skips idle timers and needs-input detection
 }
 
 type MetaDataDecl struct {
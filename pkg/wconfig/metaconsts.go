@@ -6,119 +6,131 @@
 package wconfig
 
 const (
-	ConfigKey_AppClear                       = "app:*"
-	ConfigKey_AppGlobalHotkey                = "app:globalhotkey"
-	ConfigKey_AppDismissArchitectureWarning  = "app:dismissarchitecturewarning"
-	ConfigKey_AppDefaultNewBlock             = "app:defaultnewblock"
-	ConfigKey_AppShowOverlayBlockNums        = "app:showoverlayblocknums"
-	ConfigKey_AppCtrlVPaste                  = "app:ctrlvpaste"
-	ConfigKey_AppConfirmQuit                 = "app:confirmquit"
-	ConfigKey_AppHideAiButton                = "app:hideaibutton"
-	ConfigKey_AppDisableCtrlShiftArrows      = "app:disablectrlshiftarrows"
-	ConfigKey_AppDisableCtrlShiftDisplay     = "app:disablectrlshiftdisplay"
-
-	ConfigKey_FeatureWaveAppBuilder          = "feature:waveappbuilder"
-
-	ConfigKey_AiClear                        = "ai:*"
-	ConfigKey_AiPreset                       = "ai:preset"
-	ConfigKey_AiApiType                      = "ai:apitype"
-	ConfigKey_AiBaseURL                      = "ai:baseurl"
-	ConfigKey_AiApiToken                     = "ai:apitoken"
-	ConfigKey_AiName                         = "ai:name"
-	ConfigKey_AiModel                        = "ai:model"
-	ConfigKey_AiOrgID                        = "ai:orgid"
-	ConfigKey_AIApiVersion                   = "ai:apiversion"
-	ConfigKey_AiMaxTokens                    = "ai:maxtokens"
-	ConfigKey_AiTimeoutMs                    = "ai:timeoutms"
-	ConfigKey_AiProxyUrl                     = "ai:proxyurl"
-	ConfigKey_AiFontSize                     = "ai:fontsize"
-	ConfigKey_AiFixedFontSize                = "ai:fixedfontsize"
-
-	ConfigKey_WaveAiShowCloudModes           = "waveai:showcloudmodes"
-	ConfigKey_WaveAiDefaultMode              = "waveai:defaultmode"
-
-	ConfigKey_TermClear                      = "term:*"
-	ConfigKey_TermFontSize                   = "term:fontsize"
-	ConfigKey_TermFontFamily                 = "term:fontfamily"
-	ConfigKey_TermTheme                      = "term:theme"
-	ConfigKey_TermDisableWebGl               = "term:disablewebgl"
-	ConfigKey_TermLocalShellPath             = "term:localshellpath"
-	ConfigKey_TermLocalShellOpts             = "term:localshellopts"
-	ConfigKey_TermGitBashPath                = "term:gitbashpath"
-	ConfigKey_TermScrollback                 = "term:scrollback"
-	ConfigKey_TermCopyOnSelect               = "term:copyonselect"
-	ConfigKey_TermTransparency               = "term:transparency"
-	ConfigKey_TermAllowBracketedPaste        = "term:allowbracketedpaste"
-	ConfigKey_TermShiftEnterNewline          = "term:shiftenternewline"
-	ConfigKey_TermMacOptionIsMeta            = "term:macoptionismeta"
-	ConfigKey_TermBellSound                  = "term:bellsound"
-	ConfigKey_TermBellIndicator              = "term:bellindicator"
-	ConfigKey_TermDurable                    = "term:durable"
-
-	ConfigKey_EditorMinimapEnabled           = "editor:minimapenabled"
-	ConfigKey_EditorStickyScrollEnabled      = "editor:stickyscrollenabled"
-	ConfigKey_EditorWordWrap                 = "editor:wordwrap"
-	ConfigKey_EditorFontSize                 = "editor:fontsize"
-	ConfigKey_EditorInlineDiff               = "editor:inlinediff"
-
-	ConfigKey_WebClear                       = "web:*"
-	ConfigKey_WebOpenLinksInternally         = "web:openlinksinternally"
-	ConfigKey_WebDefaultUrl                  = "web:defaulturl"
-	ConfigKey_WebDefaultSearch               = "web:defaultsearch"
-
-	ConfigKey_AutoUpdateClear                = "autoupdate:*"
-	ConfigKey_AutoUpdateEnabled              = "autoupdate:enabled"
-	ConfigKey_AutoUpdateIntervalMs           = "autoupdate:intervalms"
-	ConfigKey_AutoUpdateInstallOnQuit        = "autoupdate:installonquit"
-	ConfigKey_AutoUpdateChannel              = "autoupdate:channel"
-
-	ConfigKey_MarkdownFontSize               = "markdown:fontsize"
-	ConfigKey_MarkdownFixedFontSize          = "markdown:fixedfontsize"
-
-	ConfigKey_PreviewShowHiddenFiles         = "preview:showhiddenfiles"
-
-	ConfigKey_TabPreset                      = "tab:preset"
-
-	ConfigKey_WidgetClear                    = "widget:*"
-	ConfigKey_WidgetShowHelp                 = "widget:showhelp"
-
-	ConfigKey_WindowClear                    = "window:*"
-	ConfigKey_WindowFullscreenOnLaunch       = "window:fullscreenonlaunch"
-	ConfigKey_WindowTransparent              = "window:transparent"
-	ConfigKey_WindowBlur                     = "window:blur"
-	ConfigKey_WindowOpacity                  = "window:opacity"
-	ConfigKey_WindowBgColor                  = "window:bgcolor"
-	ConfigKey_WindowReducedMotion            = "window:reducedmotion"
-	ConfigKey_WindowTileGapSize              = "window:tilegapsize"
-	ConfigKey_WindowShowMenuBar              = "window:showmenubar"
-	ConfigKey_WindowNativeTitleBar           = "window:nativetitlebar"
-	ConfigKey_WindowDisableHardwareAcceleration = "window:disablehardwareacceleration"
-	ConfigKey_WindowMaxTabCacheSize          = "window:maxtabcachesize"
-	ConfigKey_WindowMagnifiedBlockOpacity    = "window:magnifiedblockopacity"
-	ConfigKey_WindowMagnifiedBlockSize       = "window:magnifiedblocksize"
-	ConfigKey_WindowMagnifiedBlockBlurPrimaryPx = "window:magnifiedblockblurprimarypx"
+	ConfigKey_AppClear                      = "app:*"
+	ConfigKey_AppGlobalHotkey               = "app:globalhotkey"
+	ConfigKey_AppDismissArchitectureWarning = "app:dismissarchitecturewarning"
+	ConfigKey_AppDefaultNewBlock            = "app:defaultnewblock"
+	ConfigKey_AppShowOverlayBlockNums       = "app:showoverlayblocknums"
+	ConfigKey_AppCtrlVPaste                 = "app:ctrlvpaste"
+	ConfigKey_AppConfirmQuit                = "app:confirmquit"
+	ConfigKey_AppHideAiButton               = "app:hideaibutton"
+	ConfigKey_AppDisableCtrlShiftArrows     = "app:disablectrlshiftarrows"
+	ConfigKey_AppDisableCtrlShiftDisplay    = "app:disablectrlshiftdisplay"
+
+	ConfigKey_FeatureWaveAppBuilder = "feature:waveappbuilder"
+
+	ConfigKey_AiClear         = "ai:*"
+	ConfigKey_AiPreset        = "ai:preset"
+	ConfigKey_AiApiType       = "ai:apitype"
+	ConfigKey_AiBaseURL       = "ai:baseurl"
+	ConfigKey_AiApiToken      = "ai:apitoken"
+	ConfigKey_AiName          = "ai:name"
+	ConfigKey_AiModel         = "ai:model"
+	ConfigKey_AiOrgID         = "ai:orgid"
+	ConfigKey_AIApiVersion    = "ai:apiversion"
+	ConfigKey_AiMaxTokens     = "ai:maxtokens"
+	ConfigKey_AiTimeoutMs     = "ai:timeoutms"
+	ConfigKey_AiProxyUrl      = "ai:proxyurl"
+	ConfigKey_AiFontSize      = "ai:fontsize"
+	ConfigKey_AiFixedFontSize = "ai:fixedfontsize"
+
+	ConfigKey_WaveAiShowCloudModes = "waveai:showcloudmodes"
+	ConfigKey_WaveAiDefaultMode    = "waveai:defaultmode"
+
+	ConfigKey_TermClear               = "term:*"
+	ConfigKey_TermFontSize            = "term:fontsize"
+	ConfigKey_TermFontFamily          = "term:fontfamily"
+	ConfigKey_TermTheme               = "term:theme"
+	ConfigKey_TermDisableWebGl        = "term:disablewebgl"
+	ConfigKey_TermLocalShellPath      = "term:localshellpath"
+	ConfigKey_TermLocalShellOpts      = "term:localshellopts"
+	ConfigKey_TermGitBashPath         = "term:gitbashpath"
+	ConfigKey_TermScrollback          = "term:scrollback"
+	ConfigKey_TermCopyOnSelect        = "term:copyonselect"
+	ConfigKey_TermTransparency        = "term:transparency"
+	ConfigKey_TermAllowBracketedPaste = "term:allowbracketedpaste"
+	ConfigKey_TermShiftEnterNewline   = "term:shiftenternewline"
+	ConfigKey_TermMacOptionIsMeta     = "term:macoptionismeta"
+	ConfigKey_TermBellSound           = "term:bellsound"
+	ConfigKey_TermBellIndicator       = "term:bellindicator"
+	ConfigKey_TermDurable             = "term:durable"
+
+	ConfigKey_EditorMinimapEnabled      = "editor:minimapenabled"
+	ConfigKey_EditorStickyScrollEnabled = "editor:stickyscrollenabled"
+	ConfigKey_EditorWordWrap            = "editor:wordwrap"
+	ConfigKey_EditorFontSize            = "editor:fontsize"
+	ConfigKey_EditorInlineDiff          = "editor:inlinediff"
+
+	ConfigKey_WebClear               = "web:*"
+	ConfigKey_WebOpenLinksInternally = "web:openlinksinternally"
+	ConfigKey_WebDefaultUrl          = "web:defaulturl"
+	ConfigKey_WebDefaultSearch       = "web:defaultsearch"
+
+	ConfigKey_AutoUpdateClear         = "autoupdate:*"
+	ConfigKey_AutoUpdateEnabled       = "autoupdate:enabled"
+	ConfigKey_AutoUpdateIntervalMs    = "autoupdate:intervalms"
+	ConfigKey_AutoUpdateInstallOnQuit = "autoupdate:installonquit"
+	ConfigKey_AutoUpdateChannel       = "autoupdate:channel"
+
+	ConfigKey_MarkdownFontSize      = "markdown:fontsize"
+	ConfigKey_MarkdownFixedFontSize = "markdown:fixedfontsize"
+
+	ConfigKey_TermDashClear                = "termdash:*"
+	ConfigKey_TermDashSummaryPollMs        = "termdash:summarypollms"
+	ConfigKey_TermDashSummaryStartDelayMs  = "termdash:summarystartdelayms"
+	ConfigKey_TermDashTranscriptFormat     = "termdash:transcriptformat"
+	ConfigKey_TermDashRetentionMaxBytes    = "termdash:retentionmaxbytes"
+	ConfigKey_TermDashRetentionMaxAgeMs    = "termdash:retentionmaxagems"
+	ConfigKey_TermDashRetentionMaxEntries  = "termdash:retentionmaxentries"
+	ConfigKey_TermDashRetentionMaxSessions = "termdash:retentionmaxsessions"
+	ConfigKey_TermDashRetentionSweepMs     = "termdash:retentionsweepms"
+	ConfigKey_TermDashErrorLoopThreshold   = "termdash:errorloopthreshold"
+	ConfigKey_TermDashErrorLoopWindow      = "termdash:errorloopwindow"
+	ConfigKey_TermDashClassifyThrottleMs   = "termdash:classifythrottlems"
+
+	ConfigKey_PreviewShowHiddenFiles = "preview:showhiddenfiles"
+
+	ConfigKey_TabPreset = "tab:preset"
+
+	ConfigKey_WidgetClear    = "widget:*"
+	ConfigKey_WidgetShowHelp = "widget:showhelp"
+
+	ConfigKey_WindowClear                         = "window:*"
+	ConfigKey_WindowFullscreenOnLaunch            = "window:fullscreenonlaunch"
+	ConfigKey_WindowTransparent                   = "window:transparent"
+	ConfigKey_WindowBlur                          = "window:blur"
+	ConfigKey_WindowOpacity                       = "window:opacity"
+	ConfigKey_WindowBgColor                       = "window:bgcolor"
+	ConfigKey_WindowReducedMotion                 = "window:reducedmotion"
+	ConfigKey_WindowTileGapSize                   = "window:tilegapsize"
+	ConfigKey_WindowShowMenuBar                   = "window:showmenubar"
+	ConfigKey_WindowNativeTitleBar                = "window:nativetitlebar"
+	ConfigKey_WindowDisableHardwareAcceleration   = "window:disablehardwareacceleration"
+	ConfigKey_WindowMaxTabCacheSize               = "window:maxtabcachesize"
+	ConfigKey_WindowMagnifiedBlockOpacity         = "window:magnifiedblockopacity"
+	ConfigKey_WindowMagnifiedBlockSize            = "window:magnifiedblocksize"
+	ConfigKey_WindowMagnifiedBlockBlurPrimaryPx   = "window:magnifiedblockblurprimarypx"
 	ConfigKey_WindowMagnifiedBlockBlurSecondaryPx = "window:magnifiedblockblursecondarypx"
-	ConfigKey_WindowConfirmClose             = "window:confirmclose"
-	ConfigKey_WindowSaveLastWindow           = "window:savelastwindow"
-	ConfigKey_WindowDimensions               = "window:dimensions"
-	ConfigKey_WindowZoom                     = "window:zoom"
-
-	ConfigKey_TelemetryClear                 = "telemetry:*"
-	ConfigKey_TelemetryEnabled               = "telemetry:enabled"
-
-	ConfigKey_ConnClear                      = "conn:*"
-	ConfigKey_ConnAskBeforeWshInstall        = "conn:askbeforewshinstall"
-	ConfigKey_ConnWshEnabled                 = "conn:wshenabled"
-	ConfigKey_ConnLocalHostnameDisplay       = "conn:localhostdisplayname"
-
-	ConfigKey_DebugClear                     = "debug:*"
-	ConfigKey_DebugPprofPort                 = "debug:pprofport"
-	ConfigKey_DebugPprofMemProfileRate       = "debug:pprofmemprofilerate"
-
-	ConfigKey_TsunamiClear                   = "tsunami:*"
-	ConfigKey_TsunamiScaffoldPath            = "tsunami:scaffoldpath"
-	ConfigKey_TsunamiSdkReplacePath          = "tsunami:sdkreplacepath"
-	ConfigKey_TsunamiSdkVersion              = "tsunami:sdkversion"
-	ConfigKey_TsunamiGoPath                  = "tsunami:gopath"
+	ConfigKey_WindowConfirmClose                  = "window:confirmclose"
+	ConfigKey_WindowSaveLastWindow                = "window:savelastwindow"
+	ConfigKey_WindowDimensions                    = "window:dimensions"
+	ConfigKey_WindowZoom                          = "window:zoom"
+
+	ConfigKey_TelemetryClear   = "telemetry:*"
+	ConfigKey_TelemetryEnabled = "telemetry:enabled"
+
+	ConfigKey_ConnClear                = "conn:*"
+	ConfigKey_ConnAskBeforeWshInstall  = "conn:askbeforewshinstall"
+	ConfigKey_ConnWshEnabled           = "conn:wshenabled"
+	ConfigKey_ConnLocalHostnameDisplay = "conn:localhostdisplayname"
+
+	ConfigKey_DebugClear               = "debug:*"
+	ConfigKey_DebugPprofPort           = "debug:pprofport"
+	ConfigKey_DebugPprofMemProfileRate = "debug:pprofmemprofilerate"
+
+	ConfigKey_TsunamiClear          = "tsunami:*"
+	ConfigKey_TsunamiScaffoldPath   = "tsunami:scaffoldpath"
+	ConfigKey_TsunamiSdkReplacePath = "tsunami:sdkreplacepath"
+	ConfigKey_TsunamiSdkVersion     = "tsunami:sdkversion"
+	ConfigKey_TsunamiGoPath         = "tsunami:gopath"
 )
-
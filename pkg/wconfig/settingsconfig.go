@@ -122,6 +122,19 @@ type SettingsType struct {
 	MarkdownFontSize      float64 `json:"markdown:fontsize,omitempty"`
 	MarkdownFixedFontSize float64 `json:"markdown:fixedfontsize,omitempty"`
 
+	TermDashClear                bool     `json:"termdash:*,omitempty"`
+	TermDashSummaryPollMs        *float64 `json:"termdash:summarypollms,omitempty"` // 0 disables polling (on-demand only); unset uses SummaryPollInterval
+	TermDashSummaryStartDelayMs  *float64 `json:"termdash:summarystartdelayms,omitempty"`
+	TermDashTranscriptFormat     string   `json:"termdash:transcriptformat,omitempty"`     // "jsonl" (default) or "binary"
+	TermDashRetentionMaxBytes    *float64 `json:"termdash:retentionmaxbytes,omitempty"`    // 0 or unset disables this limit
+	TermDashRetentionMaxAgeMs    *float64 `json:"termdash:retentionmaxagems,omitempty"`    // 0 or unset disables this limit
+	TermDashRetentionMaxEntries  *float64 `json:"termdash:retentionmaxentries,omitempty"`  // 0 or unset disables this limit
+	TermDashRetentionMaxSessions *float64 `json:"termdash:retentionmaxsessions,omitempty"` // 0 or unset disables this limit
+	TermDashRetentionSweepMs     *float64 `json:"termdash:retentionsweepms,omitempty"`     // 0 disables the background sweep; unset uses RetentionSweepInterval
+	TermDashErrorLoopThreshold   *float64 `json:"termdash:errorloopthreshold,omitempty"`   // unset uses DefaultErrorLoopThreshold
+	TermDashErrorLoopWindow      *float64 `json:"termdash:errorloopwindow,omitempty"`      // unset uses DefaultErrorLoopWindow
+	TermDashClassifyThrottleMs   *float64 `json:"termdash:classifythrottlems,omitempty"`   // 0 disables throttling; unset uses DefaultClassifyThrottle
+
 	PreviewShowHiddenFiles *bool `json:"preview:showhiddenfiles,omitempty"`
 
 	TabPreset string `json:"tab:preset,omitempty"`